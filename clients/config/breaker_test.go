@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(2, 20*time.Millisecond)
+
+	breaker.Failure()
+	if !breaker.Allow() {
+		t.Fatal("opened before reaching the threshold")
+	}
+	breaker.Failure()
+	if breaker.Allow() {
+		t.Fatal("still closed after reaching the threshold")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("no trial call after the cooldown")
+	}
+	if breaker.Allow() {
+		t.Fatal("let a second call through while half open")
+	}
+	breaker.Failure()
+	if breaker.Allow() {
+		t.Fatal("still letting calls through after the trial failed")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !breaker.Allow() {
+		t.Fatal("no trial call after the second cooldown")
+	}
+	breaker.Success()
+	for i := 0; i < 3; i++ {
+		if !breaker.Allow() {
+			t.Fatal("not closed after the trial succeeded")
+		}
+	}
+}
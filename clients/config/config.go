@@ -1,30 +1,54 @@
 package config
 
-import "github.com/parnurzeal/gorequest"
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout          = 5 * time.Second
+	defaultMaxRetries       = 2
+	defaultRetryBackoff     = 100 * time.Millisecond
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
 
 type ClientConfig struct {
-	client       *gorequest.SuperAgent
-	baseURL      string
-	signatureKey string
+	client           *http.Client
+	baseURL          string
+	signatureKey     string
+	timeout          time.Duration
+	maxRetries       int
+	retryBackoff     time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	breaker          *CircuitBreaker
+	random           func(int64) int64
 }
 
 type IClientConfig interface {
-	Client() *gorequest.SuperAgent
 	BaseURL() string
 	SignatureKey() string
+	Do(*Request) (*Response, error)
 }
 
 type Option func(*ClientConfig)
 
 func NewClientConfig(options ...Option) IClientConfig {
 	clientConfig := &ClientConfig{
-		client: gorequest.New().
-			Set("Content-Type", "application/json").
-			Set("Accept", "application/json"),
+		client:           &http.Client{},
+		timeout:          defaultTimeout,
+		maxRetries:       defaultMaxRetries,
+		retryBackoff:     defaultRetryBackoff,
+		breakerThreshold: defaultBreakerThreshold,
+		breakerCooldown:  defaultBreakerCooldown,
+		random:           rand.Int63n,
 	}
 	for _, option := range options {
 		option(clientConfig)
 	}
+	clientConfig.breaker = NewCircuitBreaker(clientConfig.breakerThreshold, clientConfig.breakerCooldown)
 	return clientConfig
 }
 
@@ -33,11 +57,6 @@ func (c *ClientConfig) BaseURL() string {
 	return c.baseURL
 }
 
-// Client implements IClientConfig.
-func (c *ClientConfig) Client() *gorequest.SuperAgent {
-	return c.client
-}
-
 // SignatureKey implements IClientConfig.
 func (c *ClientConfig) SignatureKey() string {
 	return c.signatureKey
@@ -54,3 +73,44 @@ func WithSignatureKey(signatureKey string) Option {
 		c.signatureKey = signatureKey
 	}
 }
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *ClientConfig) {
+		c.client = client
+	}
+}
+
+// WithTimeout sets the deadline of a single attempt.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *ClientConfig) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithRetry sets how many times an idempotent request is retried and the base
+// delay of the jittered exponential backoff between attempts.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *ClientConfig) {
+		if maxRetries > 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.retryBackoff = backoff
+		}
+	}
+}
+
+// WithCircuitBreaker opens the circuit after threshold consecutive failures
+// and lets a trial request through once cooldown has passed.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *ClientConfig) {
+		if threshold > 0 {
+			c.breakerThreshold = threshold
+		}
+		if cooldown > 0 {
+			c.breakerCooldown = cooldown
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	ErrTimeout     = errors.New("request timed out")
+)
+
+// HTTPError is returned when the upstream answers with a non 2xx status.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("upstream responded %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the status is worth another attempt.
+func (e *HTTPError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Ctx    context.Context
+	Method string
+	Path   string
+	Header map[string]string
+	Body   interface{}
+	// Sign, when set, adds the headers authenticating the request. It runs
+	// before every attempt, so a retry carries a fresh timestamp and nonce
+	// instead of replaying the previous ones.
+	Sign func(http.Header)
+}
+
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Decode unmarshals the response body into dest.
+func (r *Response) Decode(dest interface{}) error {
+	return json.Unmarshal(r.Body, dest)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Do sends the request through the circuit breaker. Idempotent requests are
+// retried on network errors, timeouts, 429 and 5xx responses. Non 2xx
+// responses are returned together with an *HTTPError.
+func (c *ClientConfig) Do(req *Request) (*Response, error) {
+	ctx := req.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attempts := 1
+	if isIdempotent(req.Method) {
+		attempts += c.maxRetries
+	}
+
+	var (
+		response *Response
+		err      error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			err = c.wait(ctx, attempt)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !c.breaker.Allow() {
+			return nil, ErrCircuitOpen
+		}
+		response, err = c.send(ctx, req)
+		if err == nil {
+			c.breaker.Success()
+			return response, nil
+		}
+
+		var httpError *HTTPError
+		if errors.As(err, &httpError) && !httpError.Retryable() {
+			// The upstream is healthy, it just rejected this request.
+			c.breaker.Success()
+			return response, err
+		}
+		c.breaker.Failure()
+		if ctx.Err() != nil {
+			return response, err
+		}
+	}
+	return response, err
+}
+
+func (c *ClientConfig) send(ctx context.Context, req *Request) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if req.Body != nil {
+		payload, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payload)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, req.Method, fmt.Sprintf("%s%s", c.baseURL, req.Path), body)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json")
+	for key, value := range req.Header {
+		httpRequest.Header.Set(key, value)
+	}
+	if req.Sign != nil {
+		req.Sign(httpRequest.Header)
+	}
+
+	httpResponse, err := c.client.Do(httpRequest)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %v", ErrTimeout, err)
+		}
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	data, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, err
+	}
+	response := &Response{StatusCode: httpResponse.StatusCode, Body: data}
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode >= 300 {
+		var message struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &message)
+		if message.Message == "" {
+			message.Message = http.StatusText(httpResponse.StatusCode)
+		}
+		return response, &HTTPError{StatusCode: httpResponse.StatusCode, Message: message.Message}
+	}
+	return response, nil
+}
+
+// wait sleeps for an exponential backoff with full jitter before the given
+// attempt, returning early when the context is done.
+func (c *ClientConfig) wait(ctx context.Context, attempt int) error {
+	backoff := c.retryBackoff * time.Duration(1<<(attempt-1))
+	delay := time.Duration(c.random(int64(backoff) + 1))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// upstream answers with the given statuses in turn, repeating the last one,
+// and records the headers of every call.
+type upstream struct {
+	mutex    sync.Mutex
+	statuses []int
+	headers  []http.Header
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	index := len(u.headers)
+	if index >= len(u.statuses) {
+		index = len(u.statuses) - 1
+	}
+	status := u.statuses[index]
+	u.headers = append(u.headers, r.Header.Clone())
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`{"message":"` + http.StatusText(status) + `"}`))
+}
+
+func (u *upstream) calls() int {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return len(u.headers)
+}
+
+func newTestClient(t *testing.T, statuses ...int) (*ClientConfig, *upstream) {
+	t.Helper()
+	handler := &upstream{statuses: statuses}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := NewClientConfig(
+		WithBaseURL(server.URL),
+		WithRetry(2, time.Millisecond),
+		WithCircuitBreaker(3, time.Hour),
+	).(*ClientConfig)
+	return client, handler
+}
+
+func TestDoRetriesIdempotentRequests(t *testing.T) {
+	client, handler := newTestClient(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+
+	response, err := client.Do(&Request{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("failed after retries: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || handler.calls() != 3 {
+		t.Errorf("got %d after %d calls, want 200 after 3", response.StatusCode, handler.calls())
+	}
+}
+
+func TestDoGivesUpAfterMaxRetries(t *testing.T) {
+	client, handler := newTestClient(t, http.StatusBadGateway)
+
+	_, err := client.Do(&Request{Method: http.MethodGet, Path: "/"})
+	var httpError *HTTPError
+	if !errors.As(err, &httpError) || httpError.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want the last HTTPError", err)
+	}
+	if handler.calls() != 3 {
+		t.Errorf("called %d times, want 1 attempt and 2 retries", handler.calls())
+	}
+}
+
+func TestDoDoesNotRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{name: "non idempotent request", method: http.MethodPost, status: http.StatusServiceUnavailable},
+		{name: "client error", method: http.MethodGet, status: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, handler := newTestClient(t, test.status)
+			_, err := client.Do(&Request{Method: test.method, Path: "/"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if handler.calls() != 1 {
+				t.Errorf("called %d times, want 1", handler.calls())
+			}
+		})
+	}
+}
+
+func TestDoSignsEveryAttempt(t *testing.T) {
+	client, handler := newTestClient(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+	var signed int
+	sign := func(header http.Header) {
+		signed++
+		header.Set("x-request-id", strconv.Itoa(signed))
+	}
+
+	_, err := client.Do(&Request{Method: http.MethodGet, Path: "/", Sign: sign})
+	if err != nil {
+		t.Fatalf("failed after retries: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, header := range handler.headers {
+		requestID := header.Get("x-request-id")
+		if requestID == "" || seen[requestID] {
+			t.Errorf("attempt sent request id %q, want a fresh one", requestID)
+		}
+		seen[requestID] = true
+	}
+	if signed != 3 {
+		t.Errorf("signed %d times, want once per attempt", signed)
+	}
+}
+
+func TestWaitUsesFullJitter(t *testing.T) {
+	client := NewClientConfig(WithRetry(3, 100*time.Millisecond)).(*ClientConfig)
+	var bounds []int64
+	client.random = func(n int64) int64 {
+		bounds = append(bounds, n)
+		return 0
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if err := client.wait(context.Background(), attempt); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	for i, bound := range bounds {
+		if time.Duration(bound) != want[i]+1 {
+			t.Errorf("attempt %d drew from [0, %s), want [0, %s]", i+1, time.Duration(bound), want[i])
+		}
+	}
+}
+
+func TestWaitStopsWithContext(t *testing.T) {
+	client := NewClientConfig(WithRetry(1, time.Hour)).(*ClientConfig)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.wait(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestDoOpensCircuit(t *testing.T) {
+	client, handler := newTestClient(t, http.StatusInternalServerError)
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.Do(&Request{Method: http.MethodPost, Path: "/"})
+	}
+	_, err := client.Do(&Request{Method: http.MethodPost, Path: "/"})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if handler.calls() != 3 {
+		t.Errorf("called %d times, want the open circuit to stop the 4th call", handler.calls())
+	}
+}
@@ -4,23 +4,52 @@ import (
 	"field-service/clients/config"
 	clients "field-service/clients/user"
 	config2 "field-service/config"
+	"time"
 )
 
-type ClientRegistry struct{}
+type ClientRegistry struct {
+	user clients.IUserClient
+}
 
 type IClientRegistry interface {
 	GetUser() clients.IUserClient
 }
 
+const (
+	defaultUserCacheTTL         = 5 * time.Minute
+	defaultUserNegativeCacheTTL = 5 * time.Second
+)
+
 func NewClientRegistry() IClientRegistry {
-	return &ClientRegistry{}
+	userConfig := config2.Config.InternalService.User
+	cacheTTL := defaultUserCacheTTL
+	if userConfig.CacheTTLSecond > 0 {
+		cacheTTL = time.Duration(userConfig.CacheTTLSecond) * time.Second
+	}
+	negativeCacheTTL := defaultUserNegativeCacheTTL
+	if userConfig.NegativeCacheTTLSecond > 0 {
+		negativeCacheTTL = time.Duration(userConfig.NegativeCacheTTLSecond) * time.Second
+	}
+	return &ClientRegistry{
+		user: clients.NewCachedUserClient(
+			clients.NewUserClient(
+				config.NewClientConfig(
+					config.WithBaseURL(userConfig.Host),
+					config.WithSignatureKey(userConfig.SignatureKey),
+					config.WithTimeout(time.Duration(userConfig.TimeoutSecond)*time.Second),
+					config.WithRetry(userConfig.MaxRetries, 0),
+					config.WithCircuitBreaker(
+						userConfig.BreakerThreshold,
+						time.Duration(userConfig.BreakerCooldownSecond)*time.Second,
+					),
+				),
+			),
+			cacheTTL,
+			negativeCacheTTL,
+		),
+	}
 }
 
 func (c *ClientRegistry) GetUser() clients.IUserClient {
-	return clients.NewUserClient(
-		config.NewClientConfig(
-			config.WithBaseURL(config2.Config.InternalService.User.Host),
-			config.WithSignatureKey(config2.Config.InternalService.User.SignatureKey),
-		),
-	)
+	return c.user
 }
@@ -0,0 +1,125 @@
+package clients
+
+import (
+	"context"
+	"expvar"
+	"field-service/common/util"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	userCacheHits   = expvar.NewInt("user_cache_hits")
+	userCacheMisses = expvar.NewInt("user_cache_misses")
+)
+
+func init() {
+	expvar.Publish("user_cache_hit_ratio", expvar.Func(func() any {
+		hits := userCacheHits.Value()
+		total := hits + userCacheMisses.Value()
+		if total == 0 {
+			return float64(0)
+		}
+		return float64(hits) / float64(total)
+	}))
+}
+
+type userCacheEntry struct {
+	user      *UserData
+	err       error
+	expiresAt time.Time
+}
+
+// CachedUserClient wraps an IUserClient with a token to user cache. Concurrent
+// lookups of the same token share a single upstream request and failed lookups
+// are remembered for a short negative TTL.
+type CachedUserClient struct {
+	next        IUserClient
+	ttl         time.Duration
+	negativeTTL time.Duration
+	mutex       sync.RWMutex
+	entries     map[string]userCacheEntry
+	sweepAt     time.Time
+	group       singleflight.Group
+}
+
+func NewCachedUserClient(next IUserClient, ttl, negativeTTL time.Duration) IUserClient {
+	return &CachedUserClient{
+		next:        next,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		entries:     map[string]userCacheEntry{},
+	}
+}
+
+func (u *CachedUserClient) GetUserByToken(ctx context.Context) (*UserData, error) {
+	token, _ := ctx.Value(constants.Token).(string)
+	if token == "" {
+		return nil, errConstant.ErrUnauthorized
+	}
+	key := util.GenerateSHA256(token)
+
+	if entry, ok := u.get(key); ok {
+		userCacheHits.Add(1)
+		return entry.user, entry.err
+	}
+	userCacheMisses.Add(1)
+
+	result, err, _ := u.group.Do(key, func() (interface{}, error) {
+		user, err := u.next.GetUserByToken(context.WithoutCancel(ctx))
+		u.set(key, userCacheEntry{user: user, err: err, expiresAt: u.expiry(ctx, err)})
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*UserData), nil
+}
+
+// expiry caps the cache TTL at the token expiry so a cached user never
+// outlives the token it was resolved from.
+func (u *CachedUserClient) expiry(ctx context.Context, err error) time.Time {
+	now := time.Now()
+	if err != nil {
+		return now.Add(u.negativeTTL)
+	}
+	expiresAt := now.Add(u.ttl)
+	userLogin, ok := ctx.Value(constants.UserLogin).(*dto.UserLogin)
+	if ok && userLogin != nil && !userLogin.ExpiresAt.IsZero() && userLogin.ExpiresAt.Before(expiresAt) {
+		expiresAt = userLogin.ExpiresAt
+	}
+	return expiresAt
+}
+
+func (u *CachedUserClient) get(key string) (userCacheEntry, bool) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	entry, ok := u.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return userCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (u *CachedUserClient) set(key string, entry userCacheEntry) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	now := time.Now()
+	if now.After(u.sweepAt) {
+		for k, e := range u.entries {
+			if now.After(e.expiresAt) {
+				delete(u.entries, k)
+			}
+		}
+		u.sweepAt = now.Add(time.Minute)
+	}
+	if !entry.expiresAt.After(now) {
+		return
+	}
+	u.entries[key] = entry
+}
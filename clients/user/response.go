@@ -9,6 +9,7 @@ type UserData struct {
 	Role        string    `json:"role"`
 	PhoneNumber string    `json:"phoneNumber"`
 	Username    string    `json:"username"`
+	TenantID    string    `json:"tenantID"`
 }
 
 type UserResponse struct {
@@ -6,8 +6,10 @@ import (
 	"field-service/common/util"
 	config2 "field-service/config"
 	"field-service/constants"
+	errConstant "field-service/constants/error"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -23,26 +25,37 @@ func NewUserClient(client config.IClientConfig) IUserClient {
 	return &UserClient{client: client}
 }
 
+// sign stamps an attempt with the time and the API key derived from it. Every
+// attempt is signed afresh, so a retry carries its own x-request-at.
+func (u *UserClient) sign(header http.Header) {
+	requestAt := strconv.FormatInt(time.Now().Unix(), 10)
+	header.Set("x-api-key", util.GenerateInternalApiKey(config2.Config.AppName, u.client.SignatureKey(), requestAt))
+	header.Set("x-request-at", requestAt)
+}
+
 func (u *UserClient) GetUserByToken(ctx context.Context) (*UserData, error) {
-	unixTime := time.Now().Unix()
-	generateApiKey := fmt.Sprintf("%s:%s:%d", config2.Config.AppName, u.client.SignatureKey(), unixTime)
-	apiKey := util.GenerateSHA256(generateApiKey)
-	token := ctx.Value(constants.Token).(string)
+	token, ok := ctx.Value(constants.Token).(string)
+	if !ok || token == "" {
+		return nil, errConstant.ErrUnauthorized
+	}
+	resp, err := u.client.Do(&config.Request{
+		Ctx:    ctx,
+		Method: http.MethodGet,
+		Path:   "/api/v1/auth/user",
+		Header: map[string]string{
+			"Authorization":  token,
+			"x-service-name": config2.Config.AppName,
+		},
+		Sign: u.sign,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	var response UserResponse
-	request := u.client.Client().
-		Get(fmt.Sprintf("%s/api/v1/auth/user", u.client.BaseURL())).
-		Set("Authorization", fmt.Sprintf("%s", token)).
-		Set("x-api-key", apiKey).
-		Set("x-service-name", config2.Config.AppName).
-		Set("x-request-at", fmt.Sprintf("%d", unixTime))
-	resp, _, errs := request.EndStruct(&response)
-
-	if len(errs) > 0 {
-		return nil, errs[0]
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("user response: %s", response.Message)
+	err = resp.Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("user response: %w", err)
 	}
 	return &response.Data, nil
 }
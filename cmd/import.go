@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/pubsub"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/repositories"
+	"field-service/services"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var importFlags struct {
+	importType string
+	file       string
+	tenantID   string
+	dryRun     bool
+}
+
+// importCommand runs the same import as POST /import for onboarding a venue
+// from a shell, e.g.
+//
+//	go run . import --type fields --file fields.xlsx --dry-run
+var importCommand = &cobra.Command{
+	Use:   "import",
+	Short: "Import fields, times or schedules from a CSV or XLSX file",
+	RunE: func(c *cobra.Command, args []string) error {
+		_ = godotenv.Load()
+		config.Init()
+		db, err := config.InitDatabase()
+		if err != nil {
+			return err
+		}
+		loc, err := time.LoadLocation("Asia/Jakarta")
+		if err != nil {
+			return err
+		}
+		time.Local = loc
+		err = db.Use(tenant.NewPlugin())
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(importFlags.file)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		tenantID := importFlags.tenantID
+		if tenantID == "" {
+			tenantID = config.Config.DefaultTenantID
+		}
+		ctx := context.WithValue(tenant.WithTenant(context.Background(), tenantID), constants.ServiceName, constants.ImportActor)
+		repository := repositories.NewRepositoryRegistry(db)
+		service := services.NewServiceRegistry(repository, nil, pubsub.NewMemoryPubSub())
+		result, importErr := service.GetImporter().Import(ctx, importFlags.importType, importFlags.file, file, importFlags.dryRun)
+		if result != nil {
+			report, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(report))
+		}
+		return importErr
+	},
+}
+
+func init() {
+	importCommand.Flags().StringVar(&importFlags.importType, "type", "", "what the file holds: fields, times or schedules")
+	importCommand.Flags().StringVar(&importFlags.file, "file", "", "path of the .csv or .xlsx file")
+	importCommand.Flags().StringVar(&importFlags.tenantID, "tenant", "", "tenant to import into, the default tenant when empty")
+	importCommand.Flags().BoolVar(&importFlags.dryRun, "dry-run", false, "validate the rows and report without writing")
+	_ = importCommand.MarkFlagRequired("type")
+	_ = importCommand.MarkFlagRequired("file")
+	command.AddCommand(importCommand)
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
+	"expvar"
 	"field-service/clients"
+	"field-service/common/event"
 	"field-service/common/gcs"
+	"field-service/common/notification"
+	"field-service/common/pubsub"
 	"field-service/common/response"
+	"field-service/common/tenant"
 	"field-service/config"
 	"field-service/constants"
 	"field-service/controllers"
@@ -13,15 +19,23 @@ import (
 	"field-service/repositories"
 	"field-service/routes"
 	"field-service/services"
+	analyticsWorker "field-service/workers/analytics"
+	exportWorker "field-service/workers/export"
+	noShowWorker "field-service/workers/noShow"
+	outboxWorker "field-service/workers/outbox"
+	paymentWorker "field-service/workers/payment"
+	reminderWorker "field-service/workers/reminder"
+	webhookWorker "field-service/workers/webhook"
 	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/didip/tollbooth"
-	"github.com/didip/tollbooth/limiter"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 var command = &cobra.Command{
@@ -41,19 +55,34 @@ var command = &cobra.Command{
 		time.Local = loc
 		err = db.AutoMigrate(
 			&models.Role{}, &models.User{},
+			&models.Field{}, &models.Time{}, &models.FieldSchedule{},
+			&models.MaintenanceWindow{}, &models.AuditLog{}, &models.FieldScheduleHistory{},
+			&models.OutboxEvent{}, &models.InboxEvent{}, &models.Payment{},
+			&models.Webhook{}, &models.WebhookDelivery{},
+			&models.OrderCustomer{}, &models.ScheduleReminder{},
+			&models.CalendarFeed{}, &models.ExportJob{}, &models.FieldScheduleRollup{},
 		)
 		if err != nil {
 			panic(err)
 		}
+		err = protectAuditLog(db)
+		if err != nil {
+			panic(err)
+		}
+		err = db.Use(tenant.NewPlugin())
+		if err != nil {
+			panic(err)
+		}
 
 		gcs := initGCS()
 		client := clients.NewClientRegistry()
 		repository := repositories.NewRepositoryRegistry(db)
-		service := services.NewServiceRegistry(repository, gcs)
+		service := services.NewServiceRegistry(repository, gcs, initPubSub())
 		controller := controllers.NewControllerRegistry(service)
 
 		router := gin.Default()
 		router.Use(middlewares.HandlePanic())
+		router.Use(middlewares.RequestID())
 
 		// NoRoute handler
 		router.NoRoute(func(c *gin.Context) {
@@ -63,6 +92,14 @@ var command = &cobra.Command{
 			})
 		})
 
+		// Runtime metrics, including the user cache hit ratio. Only admins may
+		// read them.
+		router.GET("/debug/vars",
+			middlewares.Authenticate(),
+			middlewares.CheckPermission(constants.MetricsRead, client),
+			gin.WrapH(expvar.Handler()),
+		)
+
 		// Example root route
 		router.GET("/", func(c *gin.Context) {
 			c.JSON(http.StatusOK, response.Response{
@@ -75,21 +112,49 @@ var command = &cobra.Command{
 		router.Use(func(c *gin.Context) {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-service-name, x-api-key, x-request-at")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-service-name, x-api-key, x-request-at, x-tenant-id, x-request-id")
 			c.Next()
 		})
 
 		// Rate limiter
-		lmt := tollbooth.NewLimiter(config.Config.RateLimiterMaxRequest, &limiter.ExpirableOptions{
-			DefaultExpirationTTL: time.Duration(config.Config.RateLimiterTimeSecond) * time.Second,
-		})
-		router.Use(middlewares.RateLimiter(lmt))
+		router.Use(middlewares.RateLimiter(constants.RateLimitGlobal))
+
+		// Tenant resolution
+		router.Use(middlewares.ResolveTenant())
 
 		// API group
 		group := router.Group("api/v1")
 		route := routes.NewRouterRegistry(group, controller, client)
 		route.Serve()
 
+		// Outbox relay
+		publisher := event.NewFanoutPublisher(
+			event.Sink{Name: constants.EventSinkBroker, Publisher: initPublisher()},
+			event.Sink{Name: constants.EventSinkWebhook, Publisher: service.GetWebhook()},
+			event.Sink{Name: constants.EventSinkAvailability, Publisher: service.GetAvailability()},
+		)
+		go outboxWorker.NewOutboxRelay(repository, publisher).Run(context.Background())
+
+		// Partner webhooks
+		go webhookWorker.NewWebhookDispatcher(repository).Run(context.Background())
+
+		// Booking reminders
+		go reminderWorker.NewReminderWorker(repository, initNotificationChannels()...).Run(context.Background())
+
+		// No-shows
+		go noShowWorker.NewNoShowWorker(repository).Run(context.Background())
+
+		// Export jobs
+		go exportWorker.NewExportWorker(repository, service).Run(context.Background())
+
+		// Analytics rollups
+		go analyticsWorker.NewRollupWorker(repository).Run(context.Background())
+
+		// Payment results
+		if subscriber := initPaymentSubscriber(); subscriber != nil {
+			go paymentWorker.NewPaymentConsumer(service, subscriber).Run(context.Background())
+		}
+
 		// Run server
 		port := fmt.Sprintf(":%d", config.Config.Port)
 		fmt.Println("Server running on port %s\n", port)
@@ -105,6 +170,97 @@ func Run() {
 	}
 }
 
+// auditLogAppendOnly makes the database reject any UPDATE or DELETE on
+// audit_logs, so the trail stays append-only even outside the service.
+var auditLogAppendOnly = []string{
+	`CREATE OR REPLACE FUNCTION prevent_audit_log_change() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit_logs is append-only';
+	END;
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`,
+	`CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_change()`,
+}
+
+func protectAuditLog(db *gorm.DB) error {
+	for _, statement := range auditLogAppendOnly {
+		err := db.Exec(statement).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initPublisher builds the broker selected in config. Events are only logged
+// when none is configured.
+func initPublisher() event.IEventPublisher {
+	broker := config.Config.Broker
+	switch broker.Type {
+	case constants.BrokerKafka:
+		return event.NewKafkaPublisher(broker.Kafka.Brokers, broker.Kafka.Topic)
+	case constants.BrokerFile:
+		return event.NewFilePublisher(broker.FilePath)
+	default:
+		return event.NewLogPublisher()
+	}
+}
+
+// initPaymentSubscriber returns the payment results subscription, or nil when
+// no Kafka payment topic is configured and order-service still calls
+// update-status.
+func initPaymentSubscriber() event.IEventSubscriber {
+	broker := config.Config.Broker
+	if broker.Type != constants.BrokerKafka || broker.Kafka.PaymentTopic == "" {
+		return nil
+	}
+	return event.NewKafkaSubscriber(broker.Kafka.Brokers, broker.Kafka.GroupID, broker.Kafka.PaymentTopic)
+}
+
+// initNotificationChannels builds the reminder channels listed in config,
+// falling back to the log when none is.
+func initNotificationChannels() []notification.IChannel {
+	cfg := config.Config.Notification
+	channels := make([]notification.IChannel, 0, len(cfg.Channels))
+	for _, name := range cfg.Channels {
+		switch name {
+		case constants.NotificationEmail:
+			channels = append(channels, notification.NewSMTPChannel(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From))
+		case constants.NotificationWhatsApp:
+			provider := notification.NewHTTPMessageProvider(cfg.WhatsApp.URL, cfg.WhatsApp.Token, cfg.WhatsApp.Sender, time.Duration(cfg.WhatsApp.TimeoutSecond)*time.Second)
+			channels = append(channels, notification.NewMessageChannel(name, provider))
+		case constants.NotificationSMS:
+			provider := notification.NewHTTPMessageProvider(cfg.SMS.URL, cfg.SMS.Token, cfg.SMS.Sender, time.Duration(cfg.SMS.TimeoutSecond)*time.Second)
+			channels = append(channels, notification.NewMessageChannel(name, provider))
+		case constants.NotificationLog:
+			channels = append(channels, notification.NewLogChannel())
+		default:
+			logrus.Warnf("ignoring unknown notification channel %s", name)
+		}
+	}
+	if len(channels) == 0 {
+		channels = append(channels, notification.NewLogChannel())
+	}
+	return channels
+}
+
+// initPubSub builds the store that carries availability updates to the
+// streams. Redis is needed once several replicas serve streams, since the
+// relay publishing an event runs on only one of them.
+func initPubSub() pubsub.IPubSub {
+	cfg := config.Config.PubSub
+	if cfg.Store == constants.PubSubRedis && cfg.Redis.Address != "" {
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Address,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		return pubsub.NewRedisPubSub(client, fmt.Sprintf("%s:pubsub:", config.Config.AppName))
+	}
+	return pubsub.NewMemoryPubSub()
+}
+
 func initGCS() gcs.IGCSClient {
 	decode, err := base64.StdEncoding.DecodeString(config.Config.GCSPrivateKey)
 	if err != nil {
@@ -0,0 +1,127 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeLayout    = "20060102T150405"
+	utcDateTimeLayout = "20060102T150405Z"
+	maxLineOctets     = 75
+)
+
+// Event is one VEVENT. UID must stay the same for the same event across
+// fetches so calendar clients update it instead of adding a copy.
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	LastModified time.Time
+}
+
+// Calendar is an iCalendar (RFC 5545) document whose event times are written
+// in Location, described by a VTIMEZONE.
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+// Render writes the calendar with CRLF line endings and long lines folded.
+func (c *Calendar) Render() []byte {
+	location := c.Location
+	if location == nil {
+		location = time.Local
+	}
+	var buffer bytes.Buffer
+	line := func(name string, value string) {
+		writeFolded(&buffer, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//field-service//calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escape(c.Name))
+	line("X-WR-TIMEZONE", location.String())
+	writeTimeZone(&buffer, location)
+	now := time.Now().UTC().Format(utcDateTimeLayout)
+	for _, event := range c.Events {
+		stamp := now
+		if !event.LastModified.IsZero() {
+			stamp = event.LastModified.UTC().Format(utcDateTimeLayout)
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("DTSTAMP", stamp)
+		line("LAST-MODIFIED", stamp)
+		line(fmt.Sprintf("DTSTART;TZID=%s", location.String()), event.Start.In(location).Format(dateTimeLayout))
+		line(fmt.Sprintf("DTEND;TZID=%s", location.String()), event.End.In(location).Format(dateTimeLayout))
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.Location != "" {
+			line("LOCATION", escape(event.Location))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return buffer.Bytes()
+}
+
+// writeTimeZone describes the zone by its current offset. This is exact for
+// zones without daylight saving time, such as the Asia/Jakarta zone the
+// service runs in.
+func writeTimeZone(buffer *bytes.Buffer, location *time.Location) {
+	name, offset := time.Now().In(location).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	utcOffset := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	for _, value := range []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:" + location.String(),
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:" + utcOffset,
+		"TZOFFSETTO:" + utcOffset,
+		"TZNAME:" + name,
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	} {
+		writeFolded(buffer, value)
+	}
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// writeFolded splits content lines longer than 75 octets, continuing them on
+// lines starting with a space, without cutting a UTF-8 character.
+func writeFolded(buffer *bytes.Buffer, value string) {
+	limit := maxLineOctets
+	for len(value) > limit {
+		cut := limit
+		for cut > 0 && value[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buffer.WriteString(value[:cut])
+		buffer.WriteString("\r\n ")
+		value = value[cut:]
+		limit = maxLineOctets - 1
+	}
+	buffer.WriteString(value)
+	buffer.WriteString("\r\n")
+}
@@ -0,0 +1,152 @@
+package dailysheet
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pageMargin = 15.0
+	rowHeight  = 9.0
+	boxSize    = 5.0
+)
+
+// column is one column of the slot table, in millimetres.
+type column struct {
+	title string
+	width float64
+}
+
+var columns = []column{
+	{title: "Time", width: 28},
+	{title: "Status", width: 27},
+	{title: "Booker", width: 62},
+	{title: "Phone", width: 38},
+	{title: "Checked in", width: 25},
+}
+
+// Slot is one row of a field's page. The booker is empty for slots not held
+// by an order.
+type Slot struct {
+	StartTime   string
+	EndTime     string
+	Status      string
+	BookerName  string
+	BookerPhone string
+	CheckedIn   bool
+}
+
+// Page lists the slots of one field in time order.
+type Page struct {
+	FieldCode string
+	FieldName string
+	Slots     []Slot
+}
+
+// Sheet is the printable list of a venue's slots on one date, one page per
+// field. A page continues on the next sheet of paper when its slots do not
+// fit, repeating its heading.
+type Sheet struct {
+	Venue     string
+	Date      time.Time
+	PrintedAt time.Time
+	Pages     []Page
+}
+
+// Render lays the sheet out on A4 paper. Text is written in the PDF core
+// fonts, so characters outside Windows-1252 are replaced.
+func (s *Sheet) Render() ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(fmt.Sprintf("%s %s", s.Venue, s.Date.Format(time.DateOnly)), true)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	var current *Page
+	pdf.SetHeaderFunc(func() {
+		s.heading(pdf, translate, current)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin + 5)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Printed %s", s.PrintedAt.Format("2006-01-02 15:04")), "", 0, "L", false, 0, "")
+		pdf.SetX(pageMargin)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	if len(s.Pages) == 0 {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, rowHeight, "No schedules on this date.", "", 1, "L", false, 0, "")
+	}
+	for i := range s.Pages {
+		current = &s.Pages[i]
+		pdf.AddPage()
+		s.slots(pdf, translate, current)
+	}
+
+	var buffer bytes.Buffer
+	err := pdf.Output(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// heading writes the venue, date and field at the top of every page, followed
+// by the column titles.
+func (s *Sheet) heading(pdf *gofpdf.Fpdf, translate func(string) string, page *Page) {
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, translate(s.Venue), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, s.Date.Format("Monday, 2 January 2006"), "", 1, "L", false, 0, "")
+	if page == nil {
+		pdf.Ln(4)
+		return
+	}
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, translate(fmt.Sprintf("%s (%s)", page.FieldName, page.FieldCode)), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for _, column := range columns {
+		pdf.CellFormat(column.width, rowHeight, column.title, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func (s *Sheet) slots(pdf *gofpdf.Fpdf, translate func(string) string, page *Page) {
+	pdf.SetFont("Helvetica", "", 10)
+	if len(page.Slots) == 0 {
+		pdf.CellFormat(0, rowHeight, "No schedules for this field.", "", 1, "L", false, 0, "")
+		return
+	}
+	for _, slot := range page.Slots {
+		values := []string{
+			fmt.Sprintf("%s - %s", slot.StartTime, slot.EndTime),
+			slot.Status,
+			translate(slot.BookerName),
+			translate(slot.BookerPhone),
+		}
+		for i, value := range values {
+			pdf.CellFormat(columns[i].width, rowHeight, value, "1", 0, "L", false, 0, "")
+		}
+		x, y := pdf.GetXY()
+		pdf.CellFormat(columns[len(columns)-1].width, rowHeight, "", "1", 0, "L", false, 0, "")
+		s.checkBox(pdf, x+(columns[len(columns)-1].width-boxSize)/2, y+(rowHeight-boxSize)/2, slot.CheckedIn)
+		pdf.Ln(-1)
+	}
+}
+
+// checkBox draws an empty box for staff to tick, already crossed when the
+// booking was checked in by code.
+func (s *Sheet) checkBox(pdf *gofpdf.Fpdf, x float64, y float64, checked bool) {
+	pdf.Rect(x, y, boxSize, boxSize, "D")
+	if checked {
+		pdf.Line(x+1, y+1, x+boxSize-1, y+boxSize-1)
+		pdf.Line(x+boxSize-1, y+1, x+1, y+boxSize-1)
+	}
+}
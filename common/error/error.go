@@ -14,7 +14,12 @@ type ValidationResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
-var ErrValidator = map[string]string{}
+var ErrValidator = map[string]string{
+	"oneof":    "%s must be one of [%s]",
+	"min":      "%s must be at least %s",
+	"uuid":     "%s must be a valid uuid",
+	"datetime": "%s must match the format %s",
+}
 
 func ErrValidationResponse(err error) (validationResponse []ValidationResponse) {
 	var fieldErrors validator.ValidationErrors
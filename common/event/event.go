@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Event is a domain event. ID is unique per event and stays the same when a
+// delivery is retried, so consumers can drop duplicates by it.
+type Event struct {
+	ID         string
+	TenantID   string
+	Type       string
+	Key        string
+	Payload    interface{}
+	OccurredAt time.Time
+}
+
+// IEventPublisher hands events to a broker.
+type IEventPublisher interface {
+	Publish(context.Context, Event) error
+}
+
+type LogPublisher struct{}
+
+// NewLogPublisher returns a publisher that only writes events to the log. It
+// is used when no broker is configured.
+func NewLogPublisher() IEventPublisher {
+	return &LogPublisher{}
+}
+
+func (l *LogPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		logrus.Errorf("failed to marshal event payload: %v", err)
+		return err
+	}
+	logrus.Infof("event %s id=%s key=%s payload=%s", event.Type, event.ID, event.Key, string(payload))
+	return nil
+}
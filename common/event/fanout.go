@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sink is one destination of a fanout. Its name is what the outbox records
+// once the sink accepted an event.
+type Sink struct {
+	Name      string
+	Publisher IEventPublisher
+}
+
+// IFanoutPublisher hands an event to sinks that accept or reject it
+// independently of each other.
+type IFanoutPublisher interface {
+	IEventPublisher
+	// PublishExcept hands the event to every sink not listed in done and
+	// returns the names of the sinks that accepted it, along with an error
+	// naming the ones that did not.
+	PublishExcept(context.Context, Event, []string) ([]string, error)
+}
+
+type FanoutPublisher struct {
+	sinks []Sink
+}
+
+// NewFanoutPublisher hands every event to each sink. A failing sink does not
+// keep the event from the sinks after it, and a retry only needs to reach the
+// sinks that failed.
+func NewFanoutPublisher(sinks ...Sink) IFanoutPublisher {
+	return &FanoutPublisher{sinks: sinks}
+}
+
+func (f *FanoutPublisher) Publish(ctx context.Context, event Event) error {
+	_, err := f.PublishExcept(ctx, event, nil)
+	return err
+}
+
+func (f *FanoutPublisher) PublishExcept(ctx context.Context, event Event, done []string) ([]string, error) {
+	skip := make(map[string]bool, len(done))
+	for _, name := range done {
+		skip[name] = true
+	}
+	var (
+		accepted []string
+		failures []string
+	)
+	for _, sink := range f.sinks {
+		if skip[sink.Name] {
+			continue
+		}
+		err := sink.Publisher.Publish(ctx, event)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name, err))
+			continue
+		}
+		accepted = append(accepted, sink.Name)
+	}
+	if len(failures) > 0 {
+		return accepted, errors.New(strings.Join(failures, "; "))
+	}
+	return accepted, nil
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type failingPublisher struct {
+	calls int
+}
+
+func (f *failingPublisher) Publish(context.Context, Event) error {
+	f.calls++
+	return errors.New("broker down")
+}
+
+func TestFanoutPublishesToEverySink(t *testing.T) {
+	first, last := NewMemoryPublisher(), NewMemoryPublisher()
+	failing := &failingPublisher{}
+	fanout := NewFanoutPublisher(
+		Sink{Name: "first", Publisher: first},
+		Sink{Name: "failing", Publisher: failing},
+		Sink{Name: "last", Publisher: last},
+	)
+
+	accepted, err := fanout.PublishExcept(context.Background(), Event{ID: "1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "failing: broker down") {
+		t.Fatalf("err = %v, want the failing sink named", err)
+	}
+	if !reflect.DeepEqual(accepted, []string{"first", "last"}) {
+		t.Errorf("accepted = %v, want the sinks before and after the failure", accepted)
+	}
+	if len(last.Events()) != 1 {
+		t.Error("a failing sink kept the event from the next one")
+	}
+}
+
+func TestFanoutSkipsSinksThatAccepted(t *testing.T) {
+	done, pending := &failingPublisher{}, NewMemoryPublisher()
+	fanout := NewFanoutPublisher(
+		Sink{Name: "done", Publisher: done},
+		Sink{Name: "pending", Publisher: pending},
+	)
+
+	accepted, err := fanout.PublishExcept(context.Background(), Event{ID: "1"}, []string{"done"})
+	if err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if done.calls != 0 {
+		t.Error("published again to a sink that already accepted the event")
+	}
+	if !reflect.DeepEqual(accepted, []string{"pending"}) {
+		t.Errorf("accepted = %v, want [pending]", accepted)
+	}
+}
+
+func TestMemoryPublisherDropsDuplicates(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	for _, id := range []string{"1", "2", "1"} {
+		if err := publisher.Publish(context.Background(), Event{ID: id}); err != nil {
+			t.Fatalf("failed to publish: %v", err)
+		}
+	}
+	if events := publisher.Events(); len(events) != 2 || events[0].ID != "1" || events[1].ID != "2" {
+		t.Errorf("events = %v, want 1 and 2 once, in order", events)
+	}
+}
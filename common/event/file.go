@@ -0,0 +1,56 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileRecord struct {
+	ID         string      `json:"id"`
+	TenantID   string      `json:"tenantID"`
+	Type       string      `json:"type"`
+	Key        string      `json:"key"`
+	Payload    interface{} `json:"payload"`
+	OccurredAt time.Time   `json:"occurredAt"`
+}
+
+// FilePublisher appends every event as one JSON line to a file, for local
+// runs and tests that want to inspect what would have been sent.
+type FilePublisher struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func NewFilePublisher(path string) IEventPublisher {
+	return &FilePublisher{path: path}
+}
+
+func (f *FilePublisher) Publish(_ context.Context, event Event) error {
+	line, err := json.Marshal(fileRecord{
+		ID:         event.ID,
+		TenantID:   event.TenantID,
+		Type:       event.Type,
+		Key:        event.Key,
+		Payload:    event.Payload,
+		OccurredAt: event.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,112 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	kafkaHeaderEventID   = "event-id"
+	kafkaHeaderEventType = "event-type"
+	kafkaHeaderTenantID  = "tenant-id"
+)
+
+// IKafkaWriter is the part of kafka.Writer the publisher needs.
+type IKafkaWriter interface {
+	WriteMessages(context.Context, ...kafka.Message) error
+}
+
+type KafkaPublisher struct {
+	writer IKafkaWriter
+	topic  string
+}
+
+// NewKafkaPublisher publishes every event to one topic, keyed by the event
+// key so that the events of a schedule keep their order within a partition.
+func NewKafkaPublisher(brokers []string, topic string) IEventPublisher {
+	return NewKafkaPublisherWithWriter(&kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		BatchTimeout:           10 * time.Millisecond,
+	}, topic)
+}
+
+func NewKafkaPublisherWithWriter(writer IKafkaWriter, topic string) IEventPublisher {
+	return &KafkaPublisher{writer: writer, topic: topic}
+}
+
+func (k *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Topic: k.topic,
+		Key:   []byte(event.Key),
+		Value: payload,
+		Time:  event.OccurredAt,
+		Headers: []kafka.Header{
+			{Key: kafkaHeaderEventID, Value: []byte(event.ID)},
+			{Key: kafkaHeaderEventType, Value: []byte(event.Type)},
+			{Key: kafkaHeaderTenantID, Value: []byte(event.TenantID)},
+		},
+	})
+}
+
+// IKafkaReader is the part of kafka.Reader the subscriber needs.
+type IKafkaReader interface {
+	FetchMessage(context.Context) (kafka.Message, error)
+	CommitMessages(context.Context, ...kafka.Message) error
+}
+
+type KafkaSubscriber struct {
+	reader IKafkaReader
+}
+
+// NewKafkaSubscriber reads the topic as a member of the consumer group,
+// committing each message once it was handled.
+func NewKafkaSubscriber(brokers []string, groupID string, topic string) IEventSubscriber {
+	return NewKafkaSubscriberWithReader(kafka.NewReader(kafka.ReaderConfig{
+		Brokers: brokers,
+		GroupID: groupID,
+		Topic:   topic,
+	}))
+}
+
+func NewKafkaSubscriberWithReader(reader IKafkaReader) IEventSubscriber {
+	return &KafkaSubscriber{reader: reader}
+}
+
+func (k *KafkaSubscriber) Subscribe(ctx context.Context, handler Handler) error {
+	for {
+		kafkaMessage, err := k.reader.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+		message := Message{
+			Key:        string(kafkaMessage.Key),
+			Payload:    kafkaMessage.Value,
+			OccurredAt: kafkaMessage.Time,
+		}
+		for _, header := range kafkaMessage.Headers {
+			switch header.Key {
+			case kafkaHeaderEventID:
+				message.ID = string(header.Value)
+			case kafkaHeaderEventType:
+				message.Type = string(header.Value)
+			case kafkaHeaderTenantID:
+				message.TenantID = string(header.Value)
+			}
+		}
+		deliver(ctx, handler, message)
+		err = k.reader.CommitMessages(ctx, kafkaMessage)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps published events in process. Events with an ID that
+// was already published are dropped, as a consumer would. It stands in for a
+// broker in tests and local runs.
+type MemoryPublisher struct {
+	mutex  sync.Mutex
+	events []Event
+	seen   map[string]bool
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{seen: map[string]bool{}}
+}
+
+func (m *MemoryPublisher) Publish(_ context.Context, event Event) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if event.ID != "" {
+		if m.seen[event.ID] {
+			return nil
+		}
+		m.seen[event.ID] = true
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+// Events returns the published events in publish order.
+func (m *MemoryPublisher) Events() []Event {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	events := make([]Event, len(m.events))
+	copy(events, m.events)
+	return events
+}
+
+// MemorySubscriber delivers the messages sent to it in process. It stands in
+// for a broker in tests and local runs.
+type MemorySubscriber struct {
+	messages chan Message
+}
+
+func NewMemorySubscriber(buffer int) *MemorySubscriber {
+	return &MemorySubscriber{messages: make(chan Message, buffer)}
+}
+
+// Send queues a message, blocking while the buffer is full.
+func (m *MemorySubscriber) Send(message Message) {
+	m.messages <- message
+}
+
+func (m *MemorySubscriber) Subscribe(ctx context.Context, handler Handler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case message := <-m.messages:
+			deliver(ctx, handler, message)
+		}
+	}
+}
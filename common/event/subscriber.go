@@ -0,0 +1,56 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxDeliveryAttempts = 5
+	deliveryBackoff     = time.Second
+)
+
+// Message is an event received from a broker.
+type Message struct {
+	ID         string
+	TenantID   string
+	Type       string
+	Key        string
+	Payload    []byte
+	OccurredAt time.Time
+}
+
+type Handler func(context.Context, Message) error
+
+// IEventSubscriber hands received messages to the handler one at a time until
+// the context is cancelled. A message is acknowledged once the handler
+// returns nil, so handlers must tolerate receiving a message again.
+type IEventSubscriber interface {
+	Subscribe(context.Context, Handler) error
+}
+
+// deliver calls the handler, retrying with a doubling backoff. A message that
+// still fails after maxDeliveryAttempts is logged and dropped so that it does
+// not hold up the ones behind it.
+func deliver(ctx context.Context, handler Handler, message Message) {
+	backoff := deliveryBackoff
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, message)
+		if err == nil {
+			return
+		}
+		if attempt >= maxDeliveryAttempts {
+			logrus.Errorf("dropping %s message %s after %d attempts: %v", message.Type, message.ID, attempt, err)
+			return
+		}
+		logrus.Warnf("failed to handle %s message %s, attempt %d: %v", message.Type, message.ID, attempt, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
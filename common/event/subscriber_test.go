@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemorySubscriberRetriesFailedMessage(t *testing.T) {
+	subscriber := NewMemorySubscriber(2)
+	subscriber.Send(Message{ID: "1"})
+	subscriber.Send(Message{ID: "2"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var handled []string
+	handler := func(_ context.Context, message Message) error {
+		handled = append(handled, message.ID)
+		if len(handled) == 1 {
+			return errors.New("database down")
+		}
+		if message.ID == "2" {
+			cancel()
+		}
+		return nil
+	}
+
+	err := subscriber.Subscribe(ctx, handler)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	want := []string{"1", "1", "2"}
+	if len(handled) != len(want) {
+		t.Fatalf("handled %v, want %v", handled, want)
+	}
+	for i := range want {
+		if handled[i] != want[i] {
+			t.Errorf("handled %v, want %v", handled, want)
+			break
+		}
+	}
+}
@@ -34,6 +34,8 @@ type GCSClient struct {
 
 type IGCSClient interface {
 	UploadFile(context.Context, string, []byte) (string, error)
+	UploadStream(context.Context, string, string, io.Reader) error
+	SignedURL(string, time.Duration) (string, error)
 }
 
 func NewGCSClient(serviceAccountKeyJSON ServiceAccountKeyJSON, bucketName string) IGCSClient {
@@ -109,3 +111,39 @@ func (g *GCSClient) UploadFile(ctx context.Context, filename string, data []byte
 		fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.BucketName, filename)
 	return url, nil
 }
+
+// UploadStream copies the reader into a private object as it is read, so a
+// large file never has to fit in memory.
+func (g *GCSClient) UploadStream(ctx context.Context, filename string, contentType string, reader io.Reader) error {
+	client, err := g.createClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer func(client *storage.Client) {
+		err := client.Close()
+		if err != nil {
+			logrus.Errorf("Failed to close client: %v", err)
+		}
+	}(client)
+
+	writer := client.Bucket(g.BucketName).Object(filename).NewWriter(ctx)
+	writer.ContentType = contentType
+	_, err = io.Copy(writer, reader)
+	if err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// SignedURL returns a link that downloads the object without credentials
+// until it expires.
+func (g *GCSClient) SignedURL(filename string, expiry time.Duration) (string, error) {
+	return storage.SignedURL(g.BucketName, filename, &storage.SignedURLOptions{
+		GoogleAccessID: g.ServiceAccountKeyJSON.ClientEmail,
+		PrivateKey:     []byte(g.ServiceAccountKeyJSON.PrivateKey),
+		Method:         "GET",
+		Expires:        time.Now().Add(expiry),
+		Scheme:         storage.SigningSchemeV4,
+	})
+}
@@ -0,0 +1,40 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the nonces in process. A nonce is only rejected by the
+// replica that saw it, and forgotten on restart.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	entries map[string]time.Time
+	sweepAt time.Time
+}
+
+func NewMemoryStore() IStore {
+	return &MemoryStore{entries: map[string]time.Time{}}
+}
+
+func (m *MemoryStore) Add(_ context.Context, nonce string, ttl time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.After(m.sweepAt) {
+		for key, expiry := range m.entries {
+			if now.After(expiry) {
+				delete(m.entries, key)
+			}
+		}
+		m.sweepAt = now.Add(time.Minute)
+	}
+
+	if expiry, ok := m.entries[nonce]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	m.entries[nonce] = now.Add(ttl)
+	return true, nil
+}
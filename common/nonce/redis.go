@@ -0,0 +1,24 @@
+package nonce
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the nonces in any server speaking the Redis protocol,
+// sharing them across replicas. SET NX makes the check and the insert one
+// atomic step.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisStore(client redis.Cmdable, prefix string) IStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (r *RedisStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, r.prefix+nonce, 1, ttl).Result()
+}
@@ -0,0 +1,13 @@
+package nonce
+
+import (
+	"context"
+	"time"
+)
+
+// IStore remembers nonces until they expire. Add reports false when the nonce
+// was already seen. Shared stores let every replica reject a nonce first used
+// against another one.
+type IStore interface {
+	Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
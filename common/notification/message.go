@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultMessageTimeout = 10 * time.Second
+
+// IMessageProvider sends a text message to a phone number through a WhatsApp
+// or SMS gateway.
+type IMessageProvider interface {
+	SendMessage(ctx context.Context, phoneNumber string, text string) error
+}
+
+type MessageChannel struct {
+	name     string
+	provider IMessageProvider
+}
+
+// NewMessageChannel sends the subject and body of messages as one text
+// message to the recipient's phone number.
+func NewMessageChannel(name string, provider IMessageProvider) IChannel {
+	return &MessageChannel{name: name, provider: provider}
+}
+
+func (m *MessageChannel) Name() string {
+	return m.name
+}
+
+func (m *MessageChannel) Send(ctx context.Context, message Message) error {
+	if message.To.PhoneNumber == "" {
+		return ErrNoAddress
+	}
+	return m.provider.SendMessage(ctx, message.To.PhoneNumber, fmt.Sprintf("*%s*\n\n%s", message.Subject, message.Body))
+}
+
+type httpMessageRequest struct {
+	Sender  string `json:"sender,omitempty"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// HTTPMessageProvider posts {"sender", "to", "message"} as JSON to a gateway
+// authenticated with a bearer token, the shape most WhatsApp and SMS gateways
+// accept.
+type HTTPMessageProvider struct {
+	client *http.Client
+	url    string
+	token  string
+	sender string
+}
+
+func NewHTTPMessageProvider(url string, token string, sender string, timeout time.Duration) IMessageProvider {
+	if timeout <= 0 {
+		timeout = defaultMessageTimeout
+	}
+	return &HTTPMessageProvider{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		token:  token,
+		sender: sender,
+	}
+}
+
+func (h *HTTPMessageProvider) SendMessage(ctx context.Context, phoneNumber string, text string) error {
+	body, err := json.Marshal(httpMessageRequest{Sender: h.sender, To: phoneNumber, Message: text})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		request.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	response, err := h.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, string(message))
+	}
+	return nil
+}
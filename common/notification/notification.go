@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"field-service/constants"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoAddress is returned by a channel when the recipient has no address it
+// can deliver to. Retrying does not help.
+var ErrNoAddress = errors.New("recipient has no address for this channel")
+
+type Recipient struct {
+	Name        string
+	Email       string
+	PhoneNumber string
+}
+
+type Message struct {
+	To      Recipient
+	Subject string
+	Body    string
+}
+
+// IChannel delivers messages to customers. Name is the channel's key in the
+// notification config.
+type IChannel interface {
+	Name() string
+	Send(context.Context, Message) error
+}
+
+type LogChannel struct{}
+
+// NewLogChannel returns a channel that only writes messages to the log, for
+// local runs and tests.
+func NewLogChannel() IChannel {
+	return &LogChannel{}
+}
+
+func (l *LogChannel) Name() string {
+	return constants.NotificationLog
+}
+
+func (l *LogChannel) Send(_ context.Context, message Message) error {
+	logrus.Infof("notification to %s <%s> %s: %s\n%s",
+		message.To.Name, message.To.Email, message.To.PhoneNumber, message.Subject, message.Body)
+	return nil
+}
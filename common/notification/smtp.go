@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"field-service/constants"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"time"
+)
+
+type SMTPChannel struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// NewSMTPChannel sends messages as plain text email. Authentication is only
+// used when a username is given.
+func NewSMTPChannel(host string, port int, username string, password string, from string) IChannel {
+	channel := &SMTPChannel{
+		address: fmt.Sprintf("%s:%d", host, port),
+		from:    from,
+	}
+	if username != "" {
+		channel.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return channel
+}
+
+func (s *SMTPChannel) Name() string {
+	return constants.NotificationEmail
+}
+
+func (s *SMTPChannel) Send(_ context.Context, message Message) error {
+	if message.To.Email == "" {
+		return ErrNoAddress
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", s.from)
+	fmt.Fprintf(&body, "To: %s\r\n", message.To.Email)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(message.Body)
+	return smtp.SendMail(s.address, s.auth, s.from, []string{message.To.Email}, body.Bytes())
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"bytes"
+	"field-service/constants"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const BookingReminder = "booking_reminder"
+
+// ReminderData fills the booking reminder templates. Lead is how long before
+// the start the reminder is sent.
+type ReminderData struct {
+	CustomerName string
+	FieldName    string
+	Date         time.Time
+	StartTime    string
+	EndTime      string
+	Lead         time.Duration
+}
+
+type localizedTemplate struct {
+	subject string
+	body    string
+}
+
+var templates = map[string]map[string]localizedTemplate{
+	constants.LanguageIndonesian: {
+		BookingReminder: {
+			subject: "Pengingat booking {{.FieldName}}, {{date .Date}}",
+			body: `Halo{{if .CustomerName}} {{.CustomerName}}{{end}},
+
+Booking Anda di {{.FieldName}} akan dimulai {{lead .Lead}} lagi, pada {{date .Date}} pukul {{clock .StartTime}} - {{clock .EndTime}}.
+
+Mohon datang tepat waktu. Jika Anda berhalangan hadir, silakan batalkan booking Anda sebelum jadwal dimulai.
+
+Terima kasih.`,
+		},
+	},
+	constants.LanguageEnglish: {
+		BookingReminder: {
+			subject: "Booking reminder: {{.FieldName}}, {{date .Date}}",
+			body: `Hi{{if .CustomerName}} {{.CustomerName}}{{end}},
+
+Your booking at {{.FieldName}} starts in {{lead .Lead}}, on {{date .Date}} at {{clock .StartTime}} - {{clock .EndTime}}.
+
+Please arrive on time. If you cannot make it, please cancel your booking before it starts.
+
+Thank you.`,
+		},
+	},
+}
+
+var (
+	indonesianDays   = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+	indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+)
+
+func templateFuncs(language string) template.FuncMap {
+	return template.FuncMap{
+		"date": func(date time.Time) string {
+			if language == constants.LanguageIndonesian {
+				return fmt.Sprintf("%s, %d %s %d", indonesianDays[date.Weekday()], date.Day(), indonesianMonths[date.Month()-1], date.Year())
+			}
+			return date.Format("Monday, 2 January 2006")
+		},
+		"clock": func(value string) string {
+			if len(value) > 5 {
+				return value[:5]
+			}
+			return value
+		},
+		"lead": func(lead time.Duration) string {
+			return formatLead(lead, language)
+		},
+	}
+}
+
+// formatLead spells a lead time out in whole hours and minutes, such as "1 jam
+// 30 menit" or "2 hours".
+func formatLead(lead time.Duration, language string) string {
+	hours := int(lead.Hours())
+	minutes := int(lead.Minutes()) % 60
+	parts := make([]string, 0, 2)
+	if language == constants.LanguageIndonesian {
+		if hours > 0 {
+			parts = append(parts, fmt.Sprintf("%d jam", hours))
+		}
+		if minutes > 0 || hours == 0 {
+			parts = append(parts, fmt.Sprintf("%d menit", minutes))
+		}
+		return strings.Join(parts, " ")
+	}
+	plural := func(count int, unit string) string {
+		if count == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", count, unit)
+	}
+	if hours > 0 {
+		parts = append(parts, plural(hours, "hour"))
+	}
+	if minutes > 0 || hours == 0 {
+		parts = append(parts, plural(minutes, "minute"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Render fills the subject and body of the named template in the language,
+// falling back to Indonesian for a language without templates.
+func Render(name string, language string, data interface{}) (*Message, error) {
+	localized, ok := templates[language]
+	if !ok {
+		language = constants.LanguageIndonesian
+		localized = templates[language]
+	}
+	tmpl, ok := localized[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification template %s", name)
+	}
+	subject, err := execute(tmpl.subject, language, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := execute(tmpl.body, language, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Subject: subject, Body: body}, nil
+}
+
+func execute(text string, language string, data interface{}) (string, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs(language)).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, data)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
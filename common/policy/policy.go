@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"context"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// defaultRolePermissions is used for every role that is not listed in the
+// rolePermissions config.
+var defaultRolePermissions = map[string][]string{
+	constants.Admin: {
+		string(constants.AllPermissions),
+	},
+	constants.Customer: {
+		string(constants.FieldRead),
+		string(constants.ScheduleRead),
+		string(constants.BookingCode) + constants.OwnSuffix,
+		string(constants.NoShowRead) + constants.OwnSuffix,
+		string(constants.CalendarSubscribe) + constants.OwnSuffix,
+	},
+	constants.Partner: {
+		string(constants.FieldRead),
+		string(constants.FieldUpdate) + constants.OwnSuffix,
+		string(constants.ScheduleRead),
+		string(constants.ScheduleCreate) + constants.OwnSuffix,
+		string(constants.ScheduleUpdate) + constants.OwnSuffix,
+		string(constants.ScheduleDelete) + constants.OwnSuffix,
+		string(constants.ScheduleGenerate) + constants.OwnSuffix,
+		string(constants.ScheduleHistory) + constants.OwnSuffix,
+		string(constants.ScheduleExport) + constants.OwnSuffix,
+		string(constants.ScheduleSheet) + constants.OwnSuffix,
+		string(constants.TimeRead),
+		string(constants.MaintenanceRead) + constants.OwnSuffix,
+		string(constants.MaintenanceManage) + constants.OwnSuffix,
+		string(constants.WebhookRead) + constants.OwnSuffix,
+		string(constants.WebhookManage) + constants.OwnSuffix,
+		string(constants.BookingCheckIn) + constants.OwnSuffix,
+		string(constants.CalendarSubscribe) + constants.OwnSuffix,
+		string(constants.CalendarManage) + constants.OwnSuffix,
+		string(constants.AnalyticsRead) + constants.OwnSuffix,
+	},
+}
+
+var (
+	loadOnce        sync.Once
+	rolePermissions map[string]map[string]constants.PermissionScope
+)
+
+func load() map[string]map[string]constants.PermissionScope {
+	loadOnce.Do(func() {
+		rolePermissions = map[string]map[string]constants.PermissionScope{}
+		for role, permissions := range defaultRolePermissions {
+			rolePermissions[role] = parse(permissions)
+		}
+		for role, permissions := range config.Config.RolePermissions {
+			rolePermissions[strings.ToUpper(role)] = parse(permissions)
+		}
+	})
+	return rolePermissions
+}
+
+func parse(permissions []string) map[string]constants.PermissionScope {
+	result := map[string]constants.PermissionScope{}
+	for _, permission := range permissions {
+		if name, ok := strings.CutSuffix(permission, constants.OwnSuffix); ok {
+			if _, exists := result[name]; !exists {
+				result[name] = constants.ScopeOwn
+			}
+			continue
+		}
+		result[permission] = constants.ScopeAll
+	}
+	return result
+}
+
+// Authorize returns the scope in which the role holds the permission and
+// whether it holds it at all.
+func Authorize(role string, permission constants.Permission) (constants.PermissionScope, bool) {
+	permissions, ok := load()[strings.ToUpper(role)]
+	if !ok {
+		return "", false
+	}
+	if _, ok := permissions[string(constants.AllPermissions)]; ok {
+		return constants.ScopeAll, true
+	}
+	scope, ok := permissions[string(permission)]
+	return scope, ok
+}
+
+// IsOwnScope reports whether the current request was only granted access to
+// resources owned by the user.
+func IsOwnScope(ctx context.Context) bool {
+	scope, _ := ctx.Value(constants.AccessScope).(constants.PermissionScope)
+	return scope == constants.ScopeOwn
+}
+
+func UserFromContext(ctx context.Context) *dto.UserLogin {
+	user, _ := ctx.Value(constants.UserLogin).(*dto.UserLogin)
+	return user
+}
+
+// Actor identifies who made a change: the logged in user, or the calling
+// service for requests on the internal endpoints.
+type Actor struct {
+	UUID *uuid.UUID
+	Name string
+	Role string
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	if user := UserFromContext(ctx); user != nil {
+		userUUID := user.UUID
+		return Actor{UUID: &userUUID, Name: user.Username, Role: user.Role}
+	}
+	if serviceName, ok := ctx.Value(constants.ServiceName).(string); ok && serviceName != "" {
+		return Actor{Name: serviceName, Role: constants.AuditActorService}
+	}
+	return Actor{}
+}
+
+// CheckOwnership rejects the request when it is limited to owned resources
+// and the resource does not belong to the user.
+func CheckOwnership(ctx context.Context, ownerUUID *uuid.UUID) error {
+	if !IsOwnScope(ctx) {
+		return nil
+	}
+	user := UserFromContext(ctx)
+	if user == nil || ownerUUID == nil || *ownerUUID != user.UUID {
+		return errConstant.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+const memoryBuffer = 64
+
+// MemoryPubSub delivers messages in process. A subscriber that falls more
+// than its buffer behind misses messages rather than blocking publishers.
+type MemoryPubSub struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*memorySubscription]struct{}
+}
+
+func NewMemoryPubSub() IPubSub {
+	return &MemoryPubSub{subscribers: map[string]map[*memorySubscription]struct{}{}}
+}
+
+type memorySubscription struct {
+	pubSub   *MemoryPubSub
+	channel  string
+	messages chan []byte
+	once     sync.Once
+}
+
+func (m *MemoryPubSub) Publish(_ context.Context, channel string, message []byte) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for subscription := range m.subscribers[channel] {
+		select {
+		case subscription.messages <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+func (m *MemoryPubSub) Subscribe(_ context.Context, channel string) (ISubscription, error) {
+	subscription := &memorySubscription{
+		pubSub:   m,
+		channel:  channel,
+		messages: make(chan []byte, memoryBuffer),
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.subscribers[channel] == nil {
+		m.subscribers[channel] = map[*memorySubscription]struct{}{}
+	}
+	m.subscribers[channel][subscription] = struct{}{}
+	return subscription, nil
+}
+
+func (s *memorySubscription) Messages() <-chan []byte {
+	return s.messages
+}
+
+func (s *memorySubscription) Close() error {
+	s.once.Do(func() {
+		s.pubSub.mutex.Lock()
+		defer s.pubSub.mutex.Unlock()
+		delete(s.pubSub.subscribers[s.channel], s)
+		if len(s.pubSub.subscribers[s.channel]) == 0 {
+			delete(s.pubSub.subscribers, s.channel)
+		}
+		close(s.messages)
+	})
+	return nil
+}
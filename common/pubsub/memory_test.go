@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, subscription ISubscription) string {
+	t.Helper()
+	select {
+	case message := <-subscription.Messages():
+		return string(message)
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return ""
+	}
+}
+
+func TestMemoryPubSubFansOutPerChannel(t *testing.T) {
+	pubSub := NewMemoryPubSub()
+	ctx := context.Background()
+	first, _ := pubSub.Subscribe(ctx, "availability:a")
+	second, _ := pubSub.Subscribe(ctx, "availability:a")
+	other, _ := pubSub.Subscribe(ctx, "availability:b")
+	defer other.Close()
+
+	if err := pubSub.Publish(ctx, "availability:a", []byte("booked")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if got := receive(t, first); got != "booked" {
+		t.Errorf("first subscriber got %q", got)
+	}
+	if got := receive(t, second); got != "booked" {
+		t.Errorf("second subscriber got %q", got)
+	}
+	select {
+	case message := <-other.Messages():
+		t.Errorf("subscriber of another channel got %q", message)
+	default:
+	}
+
+	first.Close()
+	if _, open := <-first.Messages(); open {
+		t.Error("messages still open after Close")
+	}
+	if err := pubSub.Publish(ctx, "availability:a", []byte("released")); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if got := receive(t, second); got != "released" {
+		t.Errorf("second subscriber got %q after the first closed", got)
+	}
+	second.Close()
+	second.Close()
+}
+
+func TestMemoryPubSubDropsForSlowSubscriber(t *testing.T) {
+	pubSub := NewMemoryPubSub()
+	ctx := context.Background()
+	slow, _ := pubSub.Subscribe(ctx, "availability:a")
+	defer slow.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < memoryBuffer*2; i++ {
+			_ = pubSub.Publish(ctx, "availability:a", []byte("update"))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a subscriber that does not read blocked the publisher")
+	}
+	if queued := len(slow.Messages()); queued != memoryBuffer {
+		t.Errorf("queued %d messages, want the buffer of %d", queued, memoryBuffer)
+	}
+}
@@ -0,0 +1,18 @@
+package pubsub
+
+import "context"
+
+// IPubSub broadcasts messages to every current subscriber of a channel. A
+// store shared by the replicas, such as Redis, reaches the subscribers of all
+// of them; the memory store only those of the same process.
+type IPubSub interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+	Subscribe(ctx context.Context, channel string) (ISubscription, error)
+}
+
+// ISubscription receives the messages published on its channel after it was
+// made. Close must be called once the subscriber is gone.
+type ISubscription interface {
+	Messages() <-chan []byte
+	Close() error
+}
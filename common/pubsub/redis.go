@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPubSub broadcasts through Redis PUBLISH/SUBSCRIBE so that every
+// replica's subscribers receive the message.
+type RedisPubSub struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisPubSub(client *redis.Client, prefix string) IPubSub {
+	return &RedisPubSub{client: client, prefix: prefix}
+}
+
+type redisSubscription struct {
+	pubSub   *redis.PubSub
+	messages chan []byte
+	once     sync.Once
+}
+
+func (r *RedisPubSub) Publish(ctx context.Context, channel string, message []byte) error {
+	return r.client.Publish(ctx, r.prefix+channel, message).Err()
+}
+
+func (r *RedisPubSub) Subscribe(ctx context.Context, channel string) (ISubscription, error) {
+	pubSub := r.client.Subscribe(ctx, r.prefix+channel)
+	// Wait for the confirmation so no message published afterwards is missed.
+	_, err := pubSub.Receive(ctx)
+	if err != nil {
+		_ = pubSub.Close()
+		return nil, err
+	}
+	subscription := &redisSubscription{
+		pubSub:   pubSub,
+		messages: make(chan []byte, memoryBuffer),
+	}
+	go func() {
+		defer close(subscription.messages)
+		for message := range pubSub.Channel() {
+			select {
+			case subscription.messages <- []byte(message.Payload):
+			default:
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+func (s *redisSubscription) Messages() <-chan []byte {
+	return s.messages
+}
+
+func (s *redisSubscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		err = s.pubSub.Close()
+	})
+	return err
+}
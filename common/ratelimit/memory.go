@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type counter struct {
+	count   int64
+	resetAt time.Time
+}
+
+// MemoryStore keeps the counters in process. Limits are per replica and reset
+// on restart.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	counters map[string]*counter
+	sweepAt  time.Time
+}
+
+func NewMemoryStore() IStore {
+	return &MemoryStore{counters: map[string]*counter{}}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.After(m.sweepAt) {
+		for k, c := range m.counters {
+			if !now.Before(c.resetAt) {
+				delete(m.counters, k)
+			}
+		}
+		m.sweepAt = now.Add(time.Minute)
+	}
+
+	c, ok := m.counters[key]
+	if !ok || !now.Before(c.resetAt) {
+		c = &counter{resetAt: now.Add(window)}
+		m.counters[key] = c
+	}
+	c.count++
+	return newResult(c.count, limit, c.resetAt.Sub(now)), nil
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript increments the key and starts its window on the first hit, in a
+// single round trip so concurrent replicas cannot race between INCR and
+// PEXPIRE.
+var takeScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// RedisStore keeps the counters in any server speaking the Redis protocol,
+// sharing them across replicas.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisStore(client redis.Scripter, prefix string) IStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (r *RedisStore) Take(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	values, err := takeScript.Run(ctx, r.client, []string{r.prefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return newResult(values[0], limit, time.Duration(values[1])*time.Millisecond), nil
+}
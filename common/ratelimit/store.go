@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result describes the state of a key's window after a request was counted.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetIn   time.Duration
+}
+
+// IStore counts requests per key in fixed windows. Implementations must be
+// safe for concurrent use; shared stores let every replica enforce the same
+// limit.
+type IStore interface {
+	Take(ctx context.Context, key string, limit int, window time.Duration) (*Result, error)
+}
+
+func newResult(count int64, limit int, resetIn time.Duration) *Result {
+	remaining := limit - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	if resetIn < 0 {
+		resetIn = 0
+	}
+	return &Result{
+		Allowed:   count <= int64(limit),
+		Limit:     limit,
+		Remaining: remaining,
+		ResetIn:   resetIn,
+	}
+}
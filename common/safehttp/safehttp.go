@@ -0,0 +1,79 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrDisallowedTarget is returned for a URL that is not https or whose host
+// resolves to an address inside the network, such as loopback, private or
+// link-local ones.
+var ErrDisallowedTarget = errors.New("target is not a public https address")
+
+// IsPublicIP reports whether the address may be reached from a request made on
+// behalf of a user.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// ValidateURL accepts an https URL whose host only resolves to public
+// addresses.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" {
+		return ErrDisallowedTarget
+	}
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, target.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDisallowedTarget, err)
+	}
+	for _, address := range addresses {
+		if !IsPublicIP(address.IP) {
+			return ErrDisallowedTarget
+		}
+	}
+	return nil
+}
+
+// control refuses the connection once the dialer resolved the host, so a name
+// that resolved to a public address at registration cannot be pointed inside
+// the network later.
+func control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrDisallowedTarget
+	}
+	return nil
+}
+
+// NewClient returns a client that only connects to public addresses and does
+// not follow redirects. The response of a redirect is returned as is.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"0.0.0.0":         false,
+	}
+	for address, want := range tests {
+		if got := IsPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://8.8.8.8/hook",
+		"ftp://8.8.8.8/hook",
+		"https:///hook",
+		"https://127.0.0.1/hook",
+		"https://localhost/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/hook",
+	} {
+		if err := ValidateURL(context.Background(), rawURL); !errors.Is(err, ErrDisallowedTarget) {
+			t.Errorf("ValidateURL(%s) = %v, want ErrDisallowedTarget", rawURL, err)
+		}
+	}
+	if err := ValidateURL(context.Background(), "https://8.8.8.8/hook"); err != nil {
+		t.Errorf("ValidateURL of a public address = %v", err)
+	}
+}
+
+func TestClientRefusesInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the internal server was reached")
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrDisallowedTarget) {
+		t.Errorf("err = %v, want ErrDisallowedTarget", err)
+	}
+}
+
+func TestClientDoesNotFollowRedirects(t *testing.T) {
+	client := NewClient(time.Second)
+	client.Transport = http.DefaultTransport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hook" {
+			http.Redirect(w, r, "/internal", http.StatusFound)
+			return
+		}
+		t.Error("the redirect was followed")
+	}))
+	defer server.Close()
+
+	response, err := client.Get(server.URL + "/hook")
+	if err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		t.Errorf("status = %d, want the redirect returned as is", response.StatusCode)
+	}
+}
@@ -0,0 +1,115 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("spreadsheet must be a .csv or .xlsx file")
+	ErrMissingHeader     = errors.New("spreadsheet has no header row")
+)
+
+// Row is one data row keyed by its normalized column name. Number is the row
+// number as shown in a spreadsheet program, the header being row 1.
+type Row struct {
+	Number int
+	Values map[string]string
+}
+
+// Get returns the trimmed value of the column, "" when the row lacks it.
+func (r Row) Get(column string) string {
+	return strings.TrimSpace(r.Values[NormalizeColumn(column)])
+}
+
+// NormalizeColumn lets "pricePerHour", "Price Per Hour" and "price_per_hour"
+// name the same column.
+func NormalizeColumn(column string) string {
+	replacer := strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "")
+	return strings.ToLower(replacer.Replace(strings.TrimSpace(column)))
+}
+
+// Format picks the format from the file name extension.
+func Format(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Read parses a CSV file, or the first sheet of an XLSX file, whose first
+// row names the columns. Blank rows are skipped.
+func Read(name string, reader io.Reader) ([]string, []Row, error) {
+	format, err := Format(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var records [][]string
+	if format == FormatCSV {
+		records, err = readCSV(reader)
+	} else {
+		records, err = readXLSX(reader)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, ErrMissingHeader
+	}
+
+	header := make([]string, 0, len(records[0]))
+	for _, column := range records[0] {
+		header = append(header, NormalizeColumn(column))
+	}
+	rows := make([]Row, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := Row{Number: i + 2, Values: make(map[string]string, len(header))}
+		blank := true
+		for j, value := range record {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			row.Values[header[j]] = value
+			if strings.TrimSpace(value) != "" {
+				blank = false
+			}
+		}
+		if !blank {
+			rows = append(rows, row)
+		}
+	}
+	return header, rows, nil
+}
+
+func readCSV(reader io.Reader) ([][]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	return csvReader.ReadAll()
+}
+
+func readXLSX(reader io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrMissingHeader
+	}
+	return file.GetRows(sheets[0])
+}
@@ -0,0 +1,147 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// csvFlushEvery pushes CSV rows to the underlying writer in small
+	// batches, so a client downloading the file sees it grow.
+	csvFlushEvery = 500
+
+	sheetName = "Sheet1"
+
+	// formulaPrefixes make spreadsheet programs read a CSV cell as a formula.
+	formulaPrefixes = "=+-@\t\r"
+)
+
+var ErrTooManyRows = errors.New("spreadsheet has more rows than XLSX allows")
+
+// IWriter writes a spreadsheet one row at a time. Close must be called to
+// finish the file.
+type IWriter interface {
+	Write([]interface{}) error
+	Close() error
+}
+
+// NewWriter starts a spreadsheet of the format on writer.
+func NewWriter(format string, writer io.Writer) (IWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &CSVWriter{writer: csv.NewWriter(writer)}, nil
+	case FormatXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter(sheetName)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return &XLSXWriter{file: file, stream: stream, writer: writer}, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType is the MIME type of the format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// isScalar reports whether the value is written as a number, boolean or time
+// rather than as text.
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool, time.Time:
+		return true
+	}
+	return false
+}
+
+// escapeFormula prefixes text that a spreadsheet program would evaluate as a
+// formula with a quote, so it is shown as typed.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+type CSVWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+func (c *CSVWriter) Write(values []interface{}) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		if isScalar(value) {
+			record = append(record, fmt.Sprint(value))
+			continue
+		}
+		record = append(record, escapeFormula(fmt.Sprint(value)))
+	}
+	err := c.writer.Write(record)
+	if err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%csvFlushEvery == 0 {
+		c.writer.Flush()
+		return c.writer.Error()
+	}
+	return nil
+}
+
+func (c *CSVWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// XLSXWriter streams the rows into the sheet, which excelize spills to a
+// temporary file once large, and writes the workbook out on Close. Values
+// other than numbers, booleans and times are written as inline strings, which
+// are never evaluated as formulas.
+type XLSXWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	writer io.Writer
+	rows   int
+}
+
+func (x *XLSXWriter) Write(values []interface{}) error {
+	if x.rows >= excelize.TotalRows {
+		return ErrTooManyRows
+	}
+	x.rows++
+	cell, err := excelize.CoordinatesToCellName(1, x.rows)
+	if err != nil {
+		return err
+	}
+	cells := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if isScalar(value) {
+			cells = append(cells, value)
+			continue
+		}
+		cells = append(cells, fmt.Sprint(value))
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+func (x *XLSXWriter) Close() error {
+	defer x.file.Close()
+	err := x.stream.Flush()
+	if err != nil {
+		return err
+	}
+	return x.file.Write(x.writer)
+}
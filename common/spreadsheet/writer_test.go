@@ -0,0 +1,75 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var injectedRow = []interface{}{"=HYPERLINK(\"http://evil\")", "+1", "-2", "@SUM(A1)", "\tcmd", "\rcmd", "Field A", -5, 100000}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buffer)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	if err = writer.Write(injectedRow); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	_, rows, err := Read("export.csv", strings.NewReader("a,b,c,d,e,f,g,h,i\n"+buffer.String()))
+	if err != nil {
+		t.Fatalf("failed to read back: %v", err)
+	}
+	want := map[string]string{
+		"a": "'=HYPERLINK(\"http://evil\")",
+		"b": "'+1",
+		"c": "'-2",
+		"d": "'@SUM(A1)",
+		"e": "'\tcmd",
+		"f": "'\rcmd",
+		"g": "Field A",
+		"h": "-5",
+		"i": "100000",
+	}
+	for column, value := range want {
+		if got := rows[0].Values[column]; got != value {
+			t.Errorf("column %s = %q, want %q", column, got, value)
+		}
+	}
+}
+
+func TestXLSXWriterWritesTextAsStrings(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(FormatXLSX, &buffer)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	if err = writer.Write(injectedRow); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	file, err := excelize.OpenReader(&buffer)
+	if err != nil {
+		t.Fatalf("failed to open workbook: %v", err)
+	}
+	defer file.Close()
+	formula, err := file.GetCellFormula(sheetName, "A1")
+	if err != nil || formula != "" {
+		t.Errorf("A1 holds formula %q (%v), want none", formula, err)
+	}
+	text, _ := file.GetCellType(sheetName, "A1")
+	number, _ := file.GetCellType(sheetName, "I1")
+	if text != excelize.CellTypeInlineString || number != excelize.CellTypeUnset {
+		t.Errorf("cell types = %v and %v, want an inline string and a number", text, number)
+	}
+}
@@ -0,0 +1,94 @@
+package tenant
+
+import (
+	errConstant "field-service/constants/error"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const fieldName = "TenantID"
+
+// Plugin scopes every statement on a model with a TenantID field to the tenant
+// found in the statement context. Queries, updates and deletes get an extra
+// tenant_id condition and created rows get their TenantID set, so repositories
+// only have to pass the request context through WithContext. A statement on
+// such a model without a tenant in its context fails with ErrTenantRequired.
+type Plugin struct{}
+
+func NewPlugin() gorm.Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return "tenant"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	err := db.Callback().Create().Before("gorm:create").Register("tenant:create", p.assign)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Query().Before("gorm:query").Register("tenant:query", p.scope)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Update().Before("gorm:update").Register("tenant:update", p.scope)
+	if err != nil {
+		return err
+	}
+	err = db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", p.scope)
+	if err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register("tenant:row", p.scope)
+}
+
+func (p *Plugin) tenantField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(fieldName)
+}
+
+func (p *Plugin) scope(db *gorm.DB) {
+	field := p.tenantField(db)
+	if field == nil || isUnscoped(db.Statement.Context) {
+		return
+	}
+	tenantID, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(errConstant.ErrTenantRequired)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+			Value:  tenantID,
+		},
+	}})
+}
+
+func (p *Plugin) assign(db *gorm.DB) {
+	field := p.tenantField(db)
+	if field == nil || isUnscoped(db.Statement.Context) {
+		return
+	}
+	tenantID, ok := FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(errConstant.ErrTenantRequired)
+		return
+	}
+	ctx := db.Statement.Context
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			_ = db.AddError(field.Set(ctx, reflect.Indirect(value.Index(i)), tenantID))
+		}
+	case reflect.Struct:
+		_ = db.AddError(field.Set(ctx, value, tenantID))
+	}
+}
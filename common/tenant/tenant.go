@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"context"
+	"field-service/constants"
+)
+
+type unscopedKey struct{}
+
+// WithTenant returns a context whose queries are scoped to the tenant. It is
+// meant for work started outside an HTTP request, such as workers and CLI
+// commands.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, constants.TenantID, tenantID)
+}
+
+// Unscoped returns a context whose queries see the rows of every tenant. Only
+// system jobs that deliberately work across tenants should use it.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, _ := ctx.Value(constants.TenantID).(string)
+	return tenantID, tenantID != ""
+}
+
+func isUnscoped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
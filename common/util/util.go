@@ -1,8 +1,12 @@
 package util
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	errConstant "field-service/constants/error"
 	"fmt"
 	"math"
 	"os"
@@ -32,6 +36,64 @@ type PaginationResult struct {
 	Data         interface{} `json:"data"`
 }
 
+type CursorPaginationParam struct {
+	Limit      int         `json:"limit"`
+	NextCursor *string     `json:"nextCursor"`
+	PrevCursor *string     `json:"prevCursor"`
+	Total      *int64      `json:"total"`
+	Data       interface{} `json:"data"`
+}
+
+type CursorPaginationResult struct {
+	NextCursor *string     `json:"nextCursor"`
+	PrevCursor *string     `json:"prevCursor"`
+	Limit      int         `json:"limit"`
+	TotalData  *int64      `json:"totalData,omitempty"`
+	Data       interface{} `json:"data"`
+}
+
+func GenerateCursorPagination(params CursorPaginationParam) CursorPaginationResult {
+	return CursorPaginationResult{
+		NextCursor: params.NextCursor,
+		PrevCursor: params.PrevCursor,
+		Limit:      params.Limit,
+		TotalData:  params.Total,
+		Data:       params.Data,
+	}
+}
+
+// EncodeCursor serializes a keyset position into an opaque url safe token.
+func EncodeCursor(cursor any) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// HashCursorQuery fingerprints the filter and sort a cursor was issued for,
+// so a cursor replayed against another query can be told apart.
+func HashCursorQuery(filter any, sort string) (string, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(append(data, 0), sort...))
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
+
+func DecodeCursor(token string, dest any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return errConstant.ErrInvalidCursor
+	}
+	err = json.Unmarshal(data, dest)
+	if err != nil {
+		return errConstant.ErrInvalidCursor
+	}
+	return nil
+}
+
 func GeneratePagination(params PaginationParam) PaginationResult {
 	totalPage := int(math.Ceil(float64(params.Count) / float64(params.Limit)))
 	var (
@@ -57,6 +119,29 @@ func GeneratePagination(params PaginationParam) PaginationResult {
 	return result
 }
 
+// GenerateSortQuery maps a client supplied sort key to its column through the
+// allowed list so that raw query string values never reach ORDER BY.
+func GenerateSortQuery(allowedColumns map[string]string, sortColumn, sortOrder *string, defaultSort string) (string, error) {
+	if sortColumn == nil || *sortColumn == "" {
+		return defaultSort, nil
+	}
+	column, ok := allowedColumns[*sortColumn]
+	if !ok {
+		return "", errConstant.ErrInvalidSortColumn
+	}
+	order := "asc"
+	if sortOrder != nil {
+		switch strings.ToLower(*sortOrder) {
+		case "asc":
+		case "desc":
+			order = "desc"
+		default:
+			return "", errConstant.ErrInvalidSortColumn
+		}
+	}
+	return fmt.Sprintf("%s %s", column, order), nil
+}
+
 func GenerateSHA256(inputString string) string {
 	hash := sha256.New()
 	hash.Write([]byte(inputString))
@@ -65,6 +150,19 @@ func GenerateSHA256(inputString string) string {
 	return hashString
 }
 
+// GenerateInternalApiKey returns the x-api-key of a call between services: the
+// SHA256 of "<service>:<signature key>:<request at>".
+func GenerateInternalApiKey(serviceName, signatureKey, requestAt string) string {
+	return GenerateSHA256(fmt.Sprintf("%s:%s:%s", serviceName, signatureKey, requestAt))
+}
+
+// GenerateHMACSHA256 returns the hex HMAC-SHA256 of the message under key.
+func GenerateHMACSHA256(key, message string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func RupiahFormat(amount *float64) string {
 	stringValue := "0"
 	if amount != nil {
@@ -9,29 +9,46 @@ import (
 
 var Config AppConfig
 
+// AppConfig is the configuration of the service. VenueNames holds the name of
+// the venue every tenant runs, keyed by tenant ID.
 type AppConfig struct {
-	Port                       int             `json:"port"`
-	AppName                    string          `json:"appName"`
-	AppEnv                     string          `json:"appEnv"`
-	SignatureKey               string          `json:"signatureKey"`
-	Database                   Database        `json:"database"`
-	RateLimiterMaxRequest      float64         `json:"rateLimiterMaxRequest"`
-	RateLimiterTimeSecond      int             `json:"rateLimiterTimeSecond"`
-	JwtSecretKey               string          `json:"jwtSecretKey"`
-	JwtExpirationTime          int             `json:"jwtExpirationTime"`
-	InternalService            InternalService `json:"InternalService"`
-	GCSType                    string          `json:"GCSType"`
-	GCSProjectID               string          `json:"GCSProjectID"`
-	GCSPrivateKeyID            string          `json:"GCSPrivateKeyID"`
-	GCSPrivateKey              string          `json:"GCSPrivateKey"`
-	GCSClientEmail             string          `json:"GCSClientEmail"`
-	GCSClientID                string          `json:"GCSClientID"`
-	GCSAuthURI                 string          `json:"GCSAuthURI"`
-	GCSTokenURI                string          `json:"GCSTokenURI"`
-	GCSAuthProviderX509CertURL string          `json:"gcsAuthProviderX509CertURL"`
-	GCSClientX509CertURL       string          `json:"gcsClientX509CertURL"`
-	GCSUniverseDomain          string          `json:"gcsUniverseDomain"`
-	GCSBucketName              string          `json:"gcsBucketName"`
+	Port                       int                 `json:"port"`
+	AppName                    string              `json:"appName"`
+	AppEnv                     string              `json:"appEnv"`
+	SignatureKey               string              `json:"signatureKey"`
+	Database                   Database            `json:"database"`
+	RateLimiterMaxRequest      float64             `json:"rateLimiterMaxRequest"`
+	RateLimiterTimeSecond      int                 `json:"rateLimiterTimeSecond"`
+	RateLimit                  RateLimit           `json:"rateLimit"`
+	JwtSecretKey               string              `json:"jwtSecretKey"`
+	JwtExpirationTime          int                 `json:"jwtExpirationTime"`
+	JwtJwksFile                string              `json:"jwtJwksFile"`
+	InternalService            InternalService     `json:"InternalService"`
+	InternalAuth               InternalAuth        `json:"internalAuth"`
+	RolePermissions            map[string][]string `json:"rolePermissions"`
+	DefaultTenantID            string              `json:"defaultTenantID"`
+	VenueNames                 map[string]string   `json:"venueNames"`
+	Broker                     Broker              `json:"broker"`
+	Outbox                     Outbox              `json:"outbox"`
+	Webhook                    Webhook             `json:"webhook"`
+	PubSub                     PubSub              `json:"pubSub"`
+	Notification               Notification        `json:"notification"`
+	CheckIn                    CheckIn             `json:"checkIn"`
+	Calendar                   Calendar            `json:"calendar"`
+	Export                     Export              `json:"export"`
+	Analytics                  Analytics           `json:"analytics"`
+	GCSType                    string              `json:"GCSType"`
+	GCSProjectID               string              `json:"GCSProjectID"`
+	GCSPrivateKeyID            string              `json:"GCSPrivateKeyID"`
+	GCSPrivateKey              string              `json:"GCSPrivateKey"`
+	GCSClientEmail             string              `json:"GCSClientEmail"`
+	GCSClientID                string              `json:"GCSClientID"`
+	GCSAuthURI                 string              `json:"GCSAuthURI"`
+	GCSTokenURI                string              `json:"GCSTokenURI"`
+	GCSAuthProviderX509CertURL string              `json:"gcsAuthProviderX509CertURL"`
+	GCSClientX509CertURL       string              `json:"gcsClientX509CertURL"`
+	GCSUniverseDomain          string              `json:"gcsUniverseDomain"`
+	GCSBucketName              string              `json:"gcsBucketName"`
 }
 
 type Database struct {
@@ -46,13 +63,175 @@ type Database struct {
 	MaxIdleTime           int    `json:"maxIdleTime"`
 }
 
+type Redis struct {
+	Address  string `json:"address"`
+	Password string `json:"password"`
+	DB       int    `json:"db"`
+}
+
+// RateLimit selects the counter store ("memory" or "redis") and the limit of
+// every policy applied to the route groups.
+type RateLimit struct {
+	Store    string                     `json:"store"`
+	Redis    Redis                      `json:"redis"`
+	Policies map[string]RateLimitPolicy `json:"policies"`
+}
+
+// RateLimitPolicy allows MaxRequest requests per WindowSecond for each client
+// identified by KeyBy ("ip", "user" or "service").
+type RateLimitPolicy struct {
+	KeyBy        string `json:"keyBy"`
+	MaxRequest   int    `json:"maxRequest"`
+	WindowSecond int    `json:"windowSecond"`
+}
+
+// Broker selects where domain events are published: "log" (default), "file"
+// or "kafka".
+type Broker struct {
+	Type     string `json:"type"`
+	FilePath string `json:"filePath"`
+	Kafka    Kafka  `json:"kafka"`
+}
+
+// Kafka holds the topic domain events are published to and the one payment
+// results are consumed from, as member of GroupID.
+type Kafka struct {
+	Brokers      []string `json:"brokers"`
+	Topic        string   `json:"topic"`
+	GroupID      string   `json:"groupID"`
+	PaymentTopic string   `json:"paymentTopic"`
+}
+
+// Outbox tunes the relay publishing the outbox. A failed event is retried
+// after BaseBackoffSecond, doubling up to MaxBackoffSecond, and given up after
+// MaxAttempts.
+type Outbox struct {
+	PollIntervalSecond int `json:"pollIntervalSecond"`
+	BatchSize          int `json:"batchSize"`
+	MaxAttempts        int `json:"maxAttempts"`
+	BaseBackoffSecond  int `json:"baseBackoffSecond"`
+	MaxBackoffSecond   int `json:"maxBackoffSecond"`
+}
+
+// Webhook tunes the delivery of partner webhooks. A failed delivery is retried
+// like outbox events, and a webhook is disabled after DisableAfterFailures
+// failed attempts in a row.
+type Webhook struct {
+	PollIntervalSecond   int `json:"pollIntervalSecond"`
+	BatchSize            int `json:"batchSize"`
+	TimeoutSecond        int `json:"timeoutSecond"`
+	MaxAttempts          int `json:"maxAttempts"`
+	BaseBackoffSecond    int `json:"baseBackoffSecond"`
+	MaxBackoffSecond     int `json:"maxBackoffSecond"`
+	DisableAfterFailures int `json:"disableAfterFailures"`
+}
+
+// PubSub selects the store ("memory" or "redis") spreading availability
+// updates to the streams open on every replica. AllowedOrigins lists the
+// origins, such as "https://app.example.com", whose pages may open a
+// WebSocket stream.
+type PubSub struct {
+	Store          string   `json:"store"`
+	Redis          Redis    `json:"redis"`
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+// Notification configures booking reminders. A reminder is sent through every
+// channel in Channels ("email", "whatsapp", "sms" or "log") at each of the
+// ReminderOffsetMinutes before the schedule starts, and retried like webhook
+// deliveries when a channel fails.
+type Notification struct {
+	ReminderOffsetMinutes []int           `json:"reminderOffsetMinutes"`
+	Channels              []string        `json:"channels"`
+	DefaultLanguage       string          `json:"defaultLanguage"`
+	PollIntervalSecond    int             `json:"pollIntervalSecond"`
+	BatchSize             int             `json:"batchSize"`
+	MaxAttempts           int             `json:"maxAttempts"`
+	BaseBackoffSecond     int             `json:"baseBackoffSecond"`
+	MaxBackoffSecond      int             `json:"maxBackoffSecond"`
+	SMTP                  SMTP            `json:"smtp"`
+	WhatsApp              MessageProvider `json:"whatsApp"`
+	SMS                   MessageProvider `json:"sms"`
+}
+
+type SMTP struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+// MessageProvider is the HTTP API of a WhatsApp or SMS gateway.
+type MessageProvider struct {
+	URL           string `json:"url"`
+	Token         string `json:"token"`
+	Sender        string `json:"sender"`
+	TimeoutSecond int    `json:"timeoutSecond"`
+}
+
+// CheckIn configures booking check-in. Check-in codes are signed with
+// SecretKey. A booking can be checked in from EarlyMinutes before it starts
+// until NoShowAfterMinutes after, when it becomes NoShow.
+type CheckIn struct {
+	SecretKey          string `json:"secretKey"`
+	EarlyMinutes       int    `json:"earlyMinutes"`
+	NoShowAfterMinutes int    `json:"noShowAfterMinutes"`
+	PollIntervalSecond int    `json:"pollIntervalSecond"`
+	BatchSize          int    `json:"batchSize"`
+}
+
+// Calendar configures the ICS feeds. BaseURL is the public address the feed
+// URLs handed out start with, and a feed carries the slots from PastDays ago
+// to FutureDays ahead.
+type Calendar struct {
+	BaseURL    string `json:"baseURL"`
+	PastDays   int    `json:"pastDays"`
+	FutureDays int    `json:"futureDays"`
+}
+
+// Export configures schedule exports. Exports of more than MaxSyncRows rows
+// must be requested as jobs, whose download links stay valid for
+// LinkExpiryMinute.
+type Export struct {
+	MaxSyncRows        int `json:"maxSyncRows"`
+	LinkExpiryMinute   int `json:"linkExpiryMinute"`
+	PollIntervalSecond int `json:"pollIntervalSecond"`
+}
+
+// Analytics configures the schedule rollups behind the analytics endpoints.
+// Slots starting from PeakStartHour until PeakEndHour, and every slot of the
+// weekend when WeekendPeak is set, count as peak.
+type Analytics struct {
+	PeakStartHour      int  `json:"peakStartHour"`
+	PeakEndHour        int  `json:"peakEndHour"`
+	WeekendPeak        bool `json:"weekendPeak"`
+	PollIntervalSecond int  `json:"pollIntervalSecond"`
+}
+
 type InternalService struct {
 	User User `json:"user"`
 }
 
+// InternalAuth holds the signature key of every service allowed to call the
+// internal endpoints, keyed by its x-service-name. Store selects where used
+// signatures are remembered ("memory" or "redis").
+type InternalAuth struct {
+	WindowSecond  int               `json:"windowSecond"`
+	SignatureKeys map[string]string `json:"signatureKeys"`
+	Store         string            `json:"store"`
+	Redis         Redis             `json:"redis"`
+}
+
 type User struct {
-	Host         string `json:"host"`
-	SignatureKey string `json:"signatureKey"`
+	Host                   string `json:"host"`
+	SignatureKey           string `json:"signatureKey"`
+	CacheTTLSecond         int    `json:"cacheTTLSecond"`
+	NegativeCacheTTLSecond int    `json:"negativeCacheTTLSecond"`
+	TimeoutSecond          int    `json:"timeoutSecond"`
+	MaxRetries             int    `json:"maxRetries"`
+	BreakerThreshold       int    `json:"breakerThreshold"`
+	BreakerCooldownSecond  int    `json:"breakerCooldownSecond"`
 }
 
 func Init() {
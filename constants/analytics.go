@@ -0,0 +1,23 @@
+package constants
+
+const (
+	AnalyticsDay   = "day"
+	AnalyticsWeek  = "week"
+	AnalyticsMonth = "month"
+
+	AnalyticsByField   = "field"
+	AnalyticsByWeekday = "weekday"
+	AnalyticsByHour    = "hour"
+
+	AnalyticsPeak    = "peak"
+	AnalyticsOffPeak = "off-peak"
+
+	// DefaultAnalyticsPeakStartHour and DefaultAnalyticsPeakEndHour bound the
+	// peak hours when the analytics config leaves them unset. The end hour is
+	// exclusive.
+	DefaultAnalyticsPeakStartHour = 17
+	DefaultAnalyticsPeakEndHour   = 23
+
+	// MaxAnalyticsRangeDays bounds the date range of an analytics query.
+	MaxAnalyticsRangeDays = 366
+)
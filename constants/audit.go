@@ -0,0 +1,20 @@
+package constants
+
+const (
+	AuditCreate       = "create"
+	AuditUpdate       = "update"
+	AuditUpdateStatus = "update_status"
+	AuditDelete       = "delete"
+	AuditRestore      = "restore"
+	AuditCheckIn      = "check_in"
+)
+
+const (
+	AuditEntityField         = "field"
+	AuditEntityTime          = "time"
+	AuditEntityFieldSchedule = "field_schedule"
+)
+
+// AuditActorService is recorded as the role of changes made by another
+// service through the internal endpoints.
+const AuditActorService = "SERVICE"
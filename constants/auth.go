@@ -1,6 +1,11 @@
 package constants
 
 const (
-	UserLogin = "user_login"
-	Token     = "token"
+	UserLogin   = "user_login"
+	Token       = "token"
+	AccessScope = "access_scope"
+	TenantID    = "tenant_id"
+	RequestID   = "request_id"
+	ClientIP    = "client_ip"
+	ServiceName = "service_name"
 )
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	CalendarFeedField    = "field"
+	CalendarFeedBookings = "bookings"
+
+	// DefaultCalendarPastDays and DefaultCalendarFutureDays bound the slots a
+	// feed carries when the calendar config leaves them unset.
+	DefaultCalendarPastDays   = 30
+	DefaultCalendarFutureDays = 180
+)
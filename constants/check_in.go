@@ -0,0 +1,15 @@
+package constants
+
+const (
+	// CheckInCodeVersion prefixes check-in codes so their format can change
+	// without breaking the codes already handed out.
+	CheckInCodeVersion = "v1"
+
+	// DefaultCheckInEarlyMinutes and DefaultNoShowAfterMinutes apply when the
+	// checkIn config leaves them unset.
+	DefaultCheckInEarlyMinutes = 30
+	DefaultNoShowAfterMinutes  = 15
+
+	// NoShowActor is recorded as the actor of bookings marked NoShow.
+	NoShowActor = "no-show-worker"
+)
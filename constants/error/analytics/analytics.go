@@ -0,0 +1,12 @@
+package error
+
+import "errors"
+
+var (
+	ErrInvalidAnalyticsRange = errors.New("Analytics start date must not be after its end date")
+	ErrAnalyticsRangeTooLong = errors.New("Analytics date range must not exceed 366 days")
+)
+
+var AnalyticsErrors = []error{
+	ErrInvalidAnalyticsRange, ErrAnalyticsRangeTooLong,
+}
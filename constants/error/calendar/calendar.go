@@ -0,0 +1,11 @@
+package error
+
+import "errors"
+
+var (
+	ErrCalendarFeedNotFound = errors.New("Calendar feed not found")
+)
+
+var CalendarErrors = []error{
+	ErrCalendarFeedNotFound,
+}
@@ -0,0 +1,20 @@
+package error
+
+import "errors"
+
+var (
+	ErrCheckInNotConfigured = errors.New("Check-in is not configured")
+	ErrCheckInCodeInvalid   = errors.New("Check-in code is invalid")
+	ErrCheckInCodeOutdated  = errors.New("Check-in code is outdated, the booking has changed")
+	ErrCheckInWrongField    = errors.New("Booking is for another field")
+	ErrCheckInNotBooked     = errors.New("Schedule is not booked")
+	ErrAlreadyCheckedIn     = errors.New("Booking is already checked in")
+	ErrCheckInTooEarly      = errors.New("Check-in is not open yet")
+	ErrCheckInClosed        = errors.New("Check-in is closed")
+	ErrInvalidCustomerID    = errors.New("Customer ID is invalid")
+)
+
+var CheckInErrors = []error{
+	ErrCheckInNotConfigured, ErrCheckInCodeInvalid, ErrCheckInCodeOutdated, ErrCheckInWrongField,
+	ErrCheckInNotBooked, ErrAlreadyCheckedIn, ErrCheckInTooEarly, ErrCheckInClosed, ErrInvalidCustomerID,
+}
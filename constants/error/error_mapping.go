@@ -1,18 +1,32 @@
 package error
 
 import (
+	errAnalytics "field-service/constants/error/analytics"
+	errCalendar "field-service/constants/error/calendar"
+	errCheckIn "field-service/constants/error/checkIn"
+	errExport "field-service/constants/error/export"
 	errField "field-service/constants/error/field"
 	errFieldSchedule "field-service/constants/error/fieldSchedule"
+	errImporter "field-service/constants/error/importer"
+	errMaintenanceWindow "field-service/constants/error/maintenanceWindow"
 	errTime "field-service/constants/error/time"
+	errWebhook "field-service/constants/error/webhook"
 )
 
 func ErrMapping(err error) bool {
-	allErrors := make([]error, 8)
+	allErrors := make([]error, 0)
 	// allErrors = append(append(append(GeneralErrors[:], errField.FieldErrors[:]...), errFieldSchedule.FieldScheduleErrors[:]...), errTime.TimeErrors[:]...)
 	allErrors = append(allErrors, GeneralErrors...)
 	allErrors = append(allErrors, errField.FieldErrors...)
 	allErrors = append(allErrors, errFieldSchedule.FieldScheduleErrors...)
 	allErrors = append(allErrors, errTime.TimeErrors...)
+	allErrors = append(allErrors, errMaintenanceWindow.MaintenanceWindowErrors...)
+	allErrors = append(allErrors, errWebhook.WebhookErrors...)
+	allErrors = append(allErrors, errCheckIn.CheckInErrors...)
+	allErrors = append(allErrors, errCalendar.CalendarErrors...)
+	allErrors = append(allErrors, errImporter.ImporterErrors...)
+	allErrors = append(allErrors, errExport.ExportErrors...)
+	allErrors = append(allErrors, errAnalytics.AnalyticsErrors...)
 
 	for _, item := range allErrors {
 		if err.Error() == item.Error() {
@@ -0,0 +1,13 @@
+package error
+
+import "errors"
+
+var (
+	ErrExportTooLarge             = errors.New("Export is too large, request it as an export job")
+	ErrExportStorageNotConfigured = errors.New("Export storage is not configured")
+	ErrExportJobNotFound          = errors.New("Export job not found")
+)
+
+var ExportErrors = []error{
+	ErrExportTooLarge, ErrExportStorageNotConfigured, ErrExportJobNotFound,
+}
@@ -3,9 +3,13 @@ package error
 import "errors"
 
 var (
-	ErrFieldNotFound = errors.New("Field not found")
+	ErrFieldNotFound           = errors.New("Field not found")
+	ErrDeletedFieldNotFound    = errors.New("Deleted field not found")
+	ErrFieldNotActive          = errors.New("Field is not active")
+	ErrFieldHasBookedSchedules = errors.New("Field still has upcoming booked schedules")
+	ErrInvalidParentField      = errors.New("Invalid parent field")
 )
 
 var FieldErrors = []error{
-	ErrFieldNotFound,
+	ErrFieldNotFound, ErrDeletedFieldNotFound, ErrFieldNotActive, ErrFieldHasBookedSchedules, ErrInvalidParentField,
 }
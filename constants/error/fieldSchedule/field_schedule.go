@@ -3,10 +3,11 @@ package error
 import "errors"
 
 var (
-	ErrFieldScheduleNotFound = errors.New("Field schedule not found")
-	ErrFieldScheduleIsExist  = errors.New("Field schedule already exists")
+	ErrFieldScheduleNotFound     = errors.New("Field schedule not found")
+	ErrFieldScheduleIsExist      = errors.New("Field schedule already exists")
+	ErrFieldScheduleNotAvailable = errors.New("Field schedule is not available")
 )
 
 var FieldScheduleErrors = []error{
-	ErrFieldScheduleNotFound, ErrFieldScheduleIsExist,
+	ErrFieldScheduleNotFound, ErrFieldScheduleIsExist, ErrFieldScheduleNotAvailable,
 }
@@ -8,9 +8,18 @@ var (
 	ErrTooManyRequests     = errors.New("too many requests")
 	ErrUnauthorized        = errors.New("unauthorized")
 	ErrInvalidToken        = errors.New("invalid token")
+	ErrTokenExpired        = errors.New("token expired")
+	ErrTokenMalformed      = errors.New("token malformed")
+	ErrInvalidTokenMethod  = errors.New("invalid token signing method")
 	ErrForbidden           = errors.New("forbidden")
+	ErrUnknownService      = errors.New("unknown service")
+	ErrRequestExpired      = errors.New("request expired")
+	ErrRequestReplayed     = errors.New("request replayed")
 	ErrInvalidUploadFile   = errors.New("invalid upload file")
 	ErrSizeTooBig          = errors.New("Size is too big.")
+	ErrInvalidSortColumn   = errors.New("invalid sort column")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrTenantRequired      = errors.New("tenant is required")
 )
 
-var GeneralErrors = []error{ErrInternalServerError, ErrSQLError, ErrTooManyRequests, ErrUnauthorized, ErrInvalidToken, ErrForbidden, ErrInvalidUploadFile, ErrSizeTooBig}
+var GeneralErrors = []error{ErrInternalServerError, ErrSQLError, ErrTooManyRequests, ErrUnauthorized, ErrInvalidToken, ErrTokenExpired, ErrTokenMalformed, ErrInvalidTokenMethod, ErrForbidden, ErrUnknownService, ErrRequestExpired, ErrRequestReplayed, ErrInvalidUploadFile, ErrSizeTooBig, ErrInvalidSortColumn, ErrInvalidCursor, ErrTenantRequired}
@@ -0,0 +1,15 @@
+package error
+
+import "errors"
+
+var (
+	ErrImportTypeInvalid = errors.New("Import type must be fields, times or schedules")
+	ErrImportFileInvalid = errors.New("Import file must be a CSV or XLSX file")
+	ErrImportFileEmpty   = errors.New("Import file has no rows")
+	ErrImportTooManyRows = errors.New("Import file has too many rows")
+	ErrImportInvalidRows = errors.New("Import has invalid rows, nothing was imported")
+)
+
+var ImporterErrors = []error{
+	ErrImportTypeInvalid, ErrImportFileInvalid, ErrImportFileEmpty, ErrImportTooManyRows, ErrImportInvalidRows,
+}
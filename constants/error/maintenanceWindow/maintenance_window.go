@@ -0,0 +1,13 @@
+package error
+
+import "errors"
+
+var (
+	ErrMaintenanceWindowNotFound     = errors.New("Maintenance window not found")
+	ErrInvalidMaintenanceWindowRange = errors.New("Maintenance window start must be before its end")
+	ErrMaintenanceWindowHasBooked    = errors.New("Maintenance window overlaps booked schedules")
+)
+
+var MaintenanceWindowErrors = []error{
+	ErrMaintenanceWindowNotFound, ErrInvalidMaintenanceWindowRange, ErrMaintenanceWindowHasBooked,
+}
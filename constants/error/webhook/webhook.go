@@ -0,0 +1,14 @@
+package error
+
+import "errors"
+
+var (
+	ErrWebhookNotFound         = errors.New("Webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("Webhook delivery not found")
+	ErrWebhookDisabled         = errors.New("Webhook is disabled")
+	ErrWebhookTargetNotAllowed = errors.New("Webhook target must be a public https URL")
+)
+
+var WebhookErrors = []error{
+	ErrWebhookNotFound, ErrWebhookDeliveryNotFound, ErrWebhookDisabled, ErrWebhookTargetNotAllowed,
+}
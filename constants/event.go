@@ -0,0 +1,37 @@
+package constants
+
+const (
+	FieldScheduleBooked      = "field_schedule.booked"
+	FieldScheduleReleased    = "field_schedule.released"
+	FieldScheduleCancelled   = "field_schedule.cancelled"
+	FieldScheduleRescheduled = "field_schedule.rescheduled"
+	FieldScheduleCheckedIn   = "field_schedule.checked_in"
+	FieldScheduleNoShow      = "field_schedule.no_show"
+	// FieldScheduleStatusChanged announces the status changes that are none
+	// of the above, such as a slot becoming Blocked or going to Maintenance.
+	FieldScheduleStatusChanged = "field_schedule.status_changed"
+)
+
+const (
+	OutboxPending   = "pending"
+	OutboxPublished = "published"
+	OutboxFailed    = "failed"
+)
+
+// Sinks the outbox relay fans events out to.
+const (
+	EventSinkBroker       = "broker"
+	EventSinkWebhook      = "webhook"
+	EventSinkAvailability = "availability"
+)
+
+const (
+	PubSubMemory = "memory"
+	PubSubRedis  = "redis"
+)
+
+const (
+	BrokerLog   = "log"
+	BrokerFile  = "file"
+	BrokerKafka = "kafka"
+)
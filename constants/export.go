@@ -0,0 +1,19 @@
+package constants
+
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+
+	// DefaultExportMaxSyncRows and DefaultExportLinkExpiryMinute apply when
+	// the export config leaves them unset.
+	DefaultExportMaxSyncRows      = 50000
+	DefaultExportLinkExpiryMinute = 60
+
+	// ExportObjectPrefix is where export job files are kept in the bucket.
+	ExportObjectPrefix = "exports"
+)
@@ -0,0 +1,35 @@
+package constants
+
+type FieldStatusName string
+
+type FieldStatus int
+
+const (
+	FieldActive      FieldStatus = 100
+	FieldInactive    FieldStatus = 200
+	FieldMaintenance FieldStatus = 300
+
+	FieldActiveString      FieldStatusName = "Active"
+	FieldInactiveString    FieldStatusName = "Inactive"
+	FieldMaintenanceString FieldStatusName = "Maintenance"
+)
+
+var mapFieldStatusIntToString = map[FieldStatus]FieldStatusName{
+	FieldActive:      FieldActiveString,
+	FieldInactive:    FieldInactiveString,
+	FieldMaintenance: FieldMaintenanceString,
+}
+
+var mapFieldStatusStringToInt = map[FieldStatusName]FieldStatus{
+	FieldActiveString:      FieldActive,
+	FieldInactiveString:    FieldInactive,
+	FieldMaintenanceString: FieldMaintenance,
+}
+
+func (f FieldStatus) GetStatusString() FieldStatusName {
+	return mapFieldStatusIntToString[f]
+}
+
+func (f FieldStatusName) GetStatusInt() FieldStatus {
+	return mapFieldStatusStringToInt[f]
+}
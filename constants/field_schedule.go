@@ -5,23 +5,63 @@ type FieldScheduleStatusName string
 type FieldScheduleStatus int
 
 const (
-	Available FieldScheduleStatus = 100
-	Booked    FieldScheduleStatus = 200
+	Available   FieldScheduleStatus = 100
+	Booked      FieldScheduleStatus = 200
+	Maintenance FieldScheduleStatus = 300
+	Blocked     FieldScheduleStatus = 400
+	CheckedIn   FieldScheduleStatus = 500
+	NoShow      FieldScheduleStatus = 600
 
-	AvailableString FieldScheduleStatusName = "Available"
-	BookedString    FieldScheduleStatusName = "Booked"
+	AvailableString   FieldScheduleStatusName = "Available"
+	BookedString      FieldScheduleStatusName = "Booked"
+	MaintenanceString FieldScheduleStatusName = "Maintenance"
+	BlockedString     FieldScheduleStatusName = "Blocked"
+	CheckedInString   FieldScheduleStatusName = "CheckedIn"
+	NoShowString      FieldScheduleStatusName = "NoShow"
 )
 
+// BookingStatuses are the statuses of a booked slot over its life. The slot
+// stays held by its order and occupied in all of them.
+var BookingStatuses = []FieldScheduleStatus{Booked, CheckedIn, NoShow}
+
 var mapFieldScheduleStatusIntToString = map[FieldScheduleStatus]FieldScheduleStatusName{
-	Available: AvailableString,
-	Booked:    BookedString,
+	Available:   AvailableString,
+	Booked:      BookedString,
+	Maintenance: MaintenanceString,
+	Blocked:     BlockedString,
+	CheckedIn:   CheckedInString,
+	NoShow:      NoShowString,
 }
 
 var mapFieldScheduleStatusStringToInt = map[FieldScheduleStatusName]FieldScheduleStatus{
-	AvailableString: Available,
-	BookedString:    Booked,
+	AvailableString:   Available,
+	BookedString:      Booked,
+	MaintenanceString: Maintenance,
+	BlockedString:     Blocked,
+	CheckedInString:   CheckedIn,
+	NoShowString:      NoShow,
 }
 
 func (f FieldScheduleStatus) GetStatusString() FieldScheduleStatusName {
 	return mapFieldScheduleStatusIntToString[f]
 }
+
+func (f FieldScheduleStatus) IsBooking() bool {
+	for _, status := range BookingStatuses {
+		if f == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (f FieldScheduleStatusName) GetStatusInt() FieldScheduleStatus {
+	return mapFieldScheduleStatusStringToInt[f]
+}
+
+const (
+	FieldScheduleHistoryCreated       = "created"
+	FieldScheduleHistoryRescheduled   = "rescheduled"
+	FieldScheduleHistoryStatusChanged = "status_changed"
+	FieldScheduleHistoryDeleted       = "deleted"
+)
@@ -7,4 +7,17 @@ var (
 	XApiKey       = textproto.CanonicalMIMEHeaderKey("x-api-key")
 	XRequestAt    = textproto.CanonicalMIMEHeaderKey("x-request-at")
 	Authorization = textproto.CanonicalMIMEHeaderKey("authorization")
+	XTenantID     = textproto.CanonicalMIMEHeaderKey("x-tenant-id")
+	XRequestID    = textproto.CanonicalMIMEHeaderKey("x-request-id")
+
+	RateLimitLimit     = textproto.CanonicalMIMEHeaderKey("ratelimit-limit")
+	RateLimitRemaining = textproto.CanonicalMIMEHeaderKey("ratelimit-remaining")
+	RateLimitReset     = textproto.CanonicalMIMEHeaderKey("ratelimit-reset")
+	RateLimitPolicy    = textproto.CanonicalMIMEHeaderKey("ratelimit-policy")
+	RetryAfter         = textproto.CanonicalMIMEHeaderKey("retry-after")
+
+	XWebhookID        = textproto.CanonicalMIMEHeaderKey("x-webhook-id")
+	XWebhookEvent     = textproto.CanonicalMIMEHeaderKey("x-webhook-event")
+	XWebhookTimestamp = textproto.CanonicalMIMEHeaderKey("x-webhook-timestamp")
+	XWebhookSignature = textproto.CanonicalMIMEHeaderKey("x-webhook-signature")
 )
@@ -0,0 +1,17 @@
+package constants
+
+const (
+	ImportFields    = "fields"
+	ImportTimes     = "times"
+	ImportSchedules = "schedules"
+
+	// MaxImportRows keeps one import small enough for a single transaction.
+	MaxImportRows = 20000
+
+	// MaxImportFileSize bounds the file uploaded to the import endpoint.
+	MaxImportFileSize = 10 * 1024 * 1024
+
+	// ImportActor is recorded as the actor of changes made by the import
+	// command.
+	ImportActor = "import-cli"
+)
@@ -0,0 +1,23 @@
+package constants
+
+const (
+	NotificationEmail    = "email"
+	NotificationWhatsApp = "whatsapp"
+	NotificationSMS      = "sms"
+	NotificationLog      = "log"
+)
+
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+const (
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	ReminderFailed  = "failed"
+	// ReminderSkipped marks a reminder that was not sent because it no longer
+	// applies, such as a cancelled booking or a customer without an address
+	// for the channel.
+	ReminderSkipped = "skipped"
+)
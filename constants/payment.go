@@ -0,0 +1,12 @@
+package constants
+
+const (
+	PaymentPaid     = "paid"
+	PaymentExpired  = "expired"
+	PaymentFailed   = "failed"
+	PaymentRefunded = "refunded"
+
+	// PaymentEventActor is recorded as the actor of schedule changes made
+	// from payment events.
+	PaymentEventActor = "payment-event"
+)
@@ -0,0 +1,56 @@
+package constants
+
+type Permission string
+
+type PermissionScope string
+
+const (
+	FieldRead    Permission = "field:read"
+	FieldCreate  Permission = "field:create"
+	FieldUpdate  Permission = "field:update"
+	FieldDelete  Permission = "field:delete"
+	FieldRestore Permission = "field:restore"
+
+	ScheduleRead     Permission = "schedule:read"
+	ScheduleCreate   Permission = "schedule:create"
+	ScheduleUpdate   Permission = "schedule:update"
+	ScheduleDelete   Permission = "schedule:delete"
+	ScheduleGenerate Permission = "schedule:generate"
+	ScheduleHistory  Permission = "schedule:history"
+	ScheduleExport   Permission = "schedule:export"
+	ScheduleSheet    Permission = "schedule:sheet"
+
+	TimeRead   Permission = "time:read"
+	TimeCreate Permission = "time:create"
+
+	MaintenanceRead   Permission = "maintenance:read"
+	MaintenanceManage Permission = "maintenance:manage"
+
+	AuditRead Permission = "audit:read"
+
+	WebhookRead   Permission = "webhook:read"
+	WebhookManage Permission = "webhook:manage"
+
+	BookingCode    Permission = "booking:code"
+	BookingCheckIn Permission = "booking:checkin"
+	NoShowRead     Permission = "noshow:read"
+
+	CalendarSubscribe Permission = "calendar:subscribe"
+	CalendarManage    Permission = "calendar:manage"
+
+	ImportWrite Permission = "import:write"
+
+	AnalyticsRead Permission = "analytics:read"
+
+	MetricsRead Permission = "metrics:read"
+
+	// AllPermissions grants every permission to a role.
+	AllPermissions Permission = "*"
+
+	// OwnSuffix limits a permission to resources owned by the user,
+	// e.g. "field:update:own".
+	OwnSuffix = ":own"
+
+	ScopeAll PermissionScope = "all"
+	ScopeOwn PermissionScope = "own"
+)
@@ -0,0 +1,19 @@
+package constants
+
+const (
+	RateLimitGlobal        = "global"
+	RateLimitPublic        = "public"
+	RateLimitAuthenticated = "authenticated"
+	RateLimitInternal      = "internal"
+)
+
+const (
+	RateLimitByIP      = "ip"
+	RateLimitByUser    = "user"
+	RateLimitByService = "service"
+)
+
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
@@ -3,4 +3,5 @@ package constants
 const (
 	Admin    = "ADMIN"
 	Customer = "CUSTOMER"
+	Partner  = "PARTNER"
 )
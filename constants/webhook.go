@@ -0,0 +1,11 @@
+package constants
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+
+	// WebhookSignaturePrefix precedes the hex HMAC-SHA256 in the signature
+	// header.
+	WebhookSignaturePrefix = "sha256="
+)
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	errValidation "field-service/common/error"
+	"field-service/common/response"
+	"field-service/domain/dto"
+	"field-service/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type AnalyticsController struct {
+	service services.IServiceRegistry
+}
+
+type IAnalyticsController interface {
+	GetUtilization(*gin.Context)
+	GetRevenue(*gin.Context)
+	GetPeak(*gin.Context)
+}
+
+func NewAnalyticsController(service services.IServiceRegistry) IAnalyticsController {
+	return &AnalyticsController{service: service}
+}
+
+// bind reads and validates the query into params, answering the request
+// itself when it cannot.
+func (a *AnalyticsController) bind(c *gin.Context, params interface{}) bool {
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return false
+	}
+	validate := validator.New()
+	err = validate.Struct(params)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return false
+	}
+	return true
+}
+
+func (a *AnalyticsController) respond(c *gin.Context, result []dto.AnalyticsRow, err error) {
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (a *AnalyticsController) GetUtilization(c *gin.Context) {
+	var params dto.AnalyticsUtilizationRequestParam
+	if !a.bind(c, &params) {
+		return
+	}
+	result, err := a.service.GetAnalytics().GetUtilization(c, &params)
+	a.respond(c, result, err)
+}
+
+func (a *AnalyticsController) GetRevenue(c *gin.Context) {
+	var params dto.AnalyticsRequestParam
+	if !a.bind(c, &params) {
+		return
+	}
+	result, err := a.service.GetAnalytics().GetRevenue(c, &params)
+	a.respond(c, result, err)
+}
+
+func (a *AnalyticsController) GetPeak(c *gin.Context) {
+	var params dto.AnalyticsRequestParam
+	if !a.bind(c, &params) {
+		return
+	}
+	result, err := a.service.GetAnalytics().GetPeak(c, &params)
+	a.respond(c, result, err)
+}
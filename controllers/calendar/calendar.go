@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"field-service/common/response"
+	"field-service/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarController struct {
+	service services.IServiceRegistry
+}
+
+type ICalendarController interface {
+	GetAll(*gin.Context)
+	CreateFieldFeed(*gin.Context)
+	CreateBookingFeed(*gin.Context)
+	Delete(*gin.Context)
+	Feed(*gin.Context)
+}
+
+func NewCalendarController(service services.IServiceRegistry) ICalendarController {
+	return &CalendarController{service: service}
+}
+
+func (ca *CalendarController) GetAll(c *gin.Context) {
+	result, err := ca.service.GetCalendar().GetAll(c)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (ca *CalendarController) CreateFieldFeed(c *gin.Context) {
+	result, err := ca.service.GetCalendar().CreateFieldFeed(c, c.Param("uuid"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusCreated,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (ca *CalendarController) CreateBookingFeed(c *gin.Context) {
+	result, err := ca.service.GetCalendar().CreateBookingFeed(c)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusCreated,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (ca *CalendarController) Delete(c *gin.Context) {
+	successMessage := fmt.Sprintf("Calendar feed with uuid %s successfully deleted", c.Param("uuid"))
+	err := ca.service.GetCalendar().Delete(c, c.Param("uuid"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code:    http.StatusOK,
+		Message: &successMessage,
+		Gin:     c,
+	})
+}
+
+// Feed serves the ICS document itself, which calendar clients poll.
+func (ca *CalendarController) Feed(c *gin.Context) {
+	result, err := ca.service.GetCalendar().Render(c, c.Param("token"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusNotFound,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", result)
+}
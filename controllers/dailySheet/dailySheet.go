@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	errValidation "field-service/common/error"
+	"field-service/common/response"
+	"field-service/domain/dto"
+	"field-service/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type DailySheetController struct {
+	service services.IServiceRegistry
+}
+
+type IDailySheetController interface {
+	Render(*gin.Context)
+}
+
+func NewDailySheetController(service services.IServiceRegistry) IDailySheetController {
+	return &DailySheetController{service: service}
+}
+
+func (d *DailySheetController) Render(c *gin.Context) {
+	var params dto.DailySheetRequestParam
+	err := c.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(params)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return
+	}
+	result, err := d.service.GetDailySheet().Render(c, &params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="daily-sheet-%s.pdf"`, params.Date))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", result)
+}
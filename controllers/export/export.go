@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	errValidation "field-service/common/error"
+	"field-service/common/response"
+	"field-service/common/spreadsheet"
+	"field-service/domain/dto"
+	"field-service/services"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type ExportController struct {
+	service services.IServiceRegistry
+}
+
+type IExportController interface {
+	Export(*gin.Context)
+	CreateJob(*gin.Context)
+	GetJob(*gin.Context)
+}
+
+func NewExportController(service services.IServiceRegistry) IExportController {
+	return &ExportController{service: service}
+}
+
+func (e *ExportController) validate(c *gin.Context, request *dto.FieldScheduleExportRequestParam) bool {
+	validate := validator.New()
+	err := validate.Struct(request)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return false
+	}
+	return true
+}
+
+// Export streams the schedules straight into the response. Once the first
+// row is sent the status can no longer change, so a failure halfway is only
+// logged and leaves the file truncated.
+func (e *ExportController) Export(c *gin.Context) {
+	var params dto.FieldScheduleExportRequestParam
+	err := c.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	if !e.validate(c, &params) {
+		return
+	}
+	err = e.service.GetExport().CheckSyncSize(c, &params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+
+	fileName := fmt.Sprintf("schedules-%s.%s", time.Now().Format("20060102150405"), params.Format)
+	c.Header("Content-Type", spreadsheet.ContentType(params.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+	err = e.service.GetExport().Write(c, &params, c.Writer)
+	if err != nil {
+		logrus.Errorf("failed to export schedules: %v", err)
+		_ = c.Error(err)
+	}
+}
+
+func (e *ExportController) CreateJob(c *gin.Context) {
+	var request dto.FieldScheduleExportRequestParam
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	if !e.validate(c, &request) {
+		return
+	}
+	result, err := e.service.GetExport().CreateJob(c, &request)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusAccepted,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (e *ExportController) GetJob(c *gin.Context) {
+	result, err := e.service.GetExport().GetJob(c, c.Param("uuid"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
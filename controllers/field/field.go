@@ -5,6 +5,7 @@ import (
 	"field-service/common/response"
 	"field-service/domain/dto"
 	"field-service/services"
+	"fmt"
 
 	"net/http"
 
@@ -20,10 +21,14 @@ type FieldController struct {
 type IFieldController interface {
 	GetAllWithPagination(*gin.Context)
 	GetAllWithoutPagination(*gin.Context)
+	GetAllDeleted(*gin.Context)
 	GetByUUID(*gin.Context)
+	GetActiveByUUID(*gin.Context)
 	Create(*gin.Context)
 	Update(*gin.Context)
+	UpdateStatus(*gin.Context)
 	Delete(*gin.Context)
+	Restore(*gin.Context)
 }
 
 func NewFieldController(service services.IServiceRegistry) IFieldController {
@@ -88,6 +93,23 @@ func (f *FieldController) GetAllWithoutPagination(c *gin.Context) {
 	})
 }
 
+func (f *FieldController) GetAllDeleted(c *gin.Context) {
+	result, err := f.service.GetField().GetAllDeleted(c)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
 func (f *FieldController) GetByUUID(c *gin.Context) {
 	result, err := f.service.GetField().GetByUUID(c, c.Param("uuid"))
 	if err != nil {
@@ -108,6 +130,26 @@ func (f *FieldController) GetByUUID(c *gin.Context) {
 	})
 }
 
+func (f *FieldController) GetActiveByUUID(c *gin.Context) {
+	result, err := f.service.GetField().GetActiveByUUID(c, c.Param("uuid"))
+	if err != nil {
+		c.Set("error_message", err)
+		c.Set("http_status", http.StatusBadRequest)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
 func (f *FieldController) Create(c *gin.Context) {
 	var request dto.FieldRequest
 	err := c.ShouldBindWith(&request, binding.FormMultipart)
@@ -194,6 +236,48 @@ func (f *FieldController) Update(c *gin.Context) {
 	})
 }
 
+func (f *FieldController) UpdateStatus(c *gin.Context) {
+	var request dto.UpdateFieldStatusRequest
+	successMessage := fmt.Sprintf("Field with uuid %s status successfully updated.", c.Param("uuid"))
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(request)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return
+	}
+	err = f.service.GetField().UpdateStatus(c, c.Param("uuid"), &request)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code:    http.StatusOK,
+		Message: &successMessage,
+		Gin:     c,
+	})
+}
+
 func (f *FieldController) Delete(c *gin.Context) {
 	err := f.service.GetField().Delete(c, c.Param("uuid"))
 	if err != nil {
@@ -209,3 +293,21 @@ func (f *FieldController) Delete(c *gin.Context) {
 		Gin:  c,
 	})
 }
+
+func (f *FieldController) Restore(c *gin.Context) {
+	successMessage := fmt.Sprintf("Field with uuid %s successfully restored.", c.Param("uuid"))
+	err := f.service.GetField().Restore(c, c.Param("uuid"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code:    http.StatusOK,
+		Message: &successMessage,
+		Gin:     c,
+	})
+}
@@ -1,27 +1,64 @@
 package controllers
 
 import (
+	"encoding/json"
 	errValidation "field-service/common/error"
 	"field-service/common/response"
+	"field-service/config"
 	"field-service/domain/dto"
 	"field-service/services"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
 )
 
+// streamHeartbeat keeps idle streams open through proxies that close silent
+// connections.
+const streamHeartbeat = 25 * time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin lets a browser open a stream only from a page of an allowed
+// origin. Clients other than browsers send no Origin, cannot be driven by
+// another site and are let through.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range config.Config.PubSub.AllowedOrigins {
+		if strings.EqualFold(origin, strings.TrimSuffix(allowed, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
 type FieldScheduleController struct {
 	service services.IServiceRegistry
 }
 
 type IFieldScheduleController interface {
 	GetAllWithPagination(*gin.Context)
+	GetAllWithCursor(*gin.Context)
 	// GetAllWithoutPagination(*gin.Context)
 	GetAllByFieldIDAndDate(*gin.Context)
+	Stream(*gin.Context)
+	StreamWebSocket(*gin.Context)
 	GetByUUID(*gin.Context)
+	GetHistory(*gin.Context)
+	GetBoardAt(*gin.Context)
 	Create(*gin.Context)
 	Update(*gin.Context)
 	UpdateStatus(*gin.Context)
@@ -74,6 +111,47 @@ func (f *FieldScheduleController) GetAllWithPagination(c *gin.Context) {
 	})
 }
 
+func (f *FieldScheduleController) GetAllWithCursor(c *gin.Context) {
+	var params dto.FieldScheduleCursorRequestParam
+	err := c.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(params)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return
+	}
+	result, err := f.service.GetFieldSchedule().GetAllWithCursor(c, &params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
 func (f *FieldScheduleController) GetAllByFieldIDAndDate(c *gin.Context) {
 	var params dto.FieldScheduleByFieldIDAndDateRequestParam
 	err := c.ShouldBindQuery(&params)
@@ -115,6 +193,64 @@ func (f *FieldScheduleController) GetAllByFieldIDAndDate(c *gin.Context) {
 	})
 }
 
+func (f *FieldScheduleController) GetHistory(c *gin.Context) {
+	result, err := f.service.GetFieldSchedule().GetHistory(c, c.Param("uuid"))
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
+func (f *FieldScheduleController) GetBoardAt(c *gin.Context) {
+	var params dto.FieldScheduleBoardRequestParam
+	err := c.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(params)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return
+	}
+	result, err := f.service.GetFieldSchedule().GetBoardAt(c, &params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
+
 func (f *FieldScheduleController) Create(c *gin.Context) {
 	var request dto.FieldScheduleRequest
 	// err := c.ShouldBindWith(&request, binding.FormMultipart)
@@ -324,3 +460,144 @@ func (f *FieldScheduleController) GenerateScheduleForOneMonth(c *gin.Context) {
 		Gin:     c,
 	})
 }
+
+// subscribe validates the stream request and subscribes to the field's
+// channel before reading the snapshot, so no change made in between is lost.
+func (f *FieldScheduleController) subscribe(c *gin.Context) ([]dto.FieldScheduleForBookingResponse, func(), <-chan []byte, bool) {
+	var params dto.FieldScheduleStreamRequestParam
+	err := c.ShouldBindQuery(&params)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return nil, nil, nil, false
+	}
+	validate := validator.New()
+	err = validate.Struct(params)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return nil, nil, nil, false
+	}
+	subscription, err := f.service.GetAvailability().Subscribe(c, c.Param("uuid"), params.Date)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return nil, nil, nil, false
+	}
+	snapshot, err := f.service.GetFieldSchedule().GetAllByFieldAndDate(c, c.Param("uuid"), params.Date)
+	if err != nil {
+		_ = subscription.Close()
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return nil, nil, nil, false
+	}
+	closeSubscription := func() {
+		_ = subscription.Close()
+	}
+	return snapshot, closeSubscription, subscription.Messages(), true
+}
+
+// Stream sends the schedules of a field and date as a "snapshot" event, then
+// an "availability" event for every status change, over Server-Sent Events.
+func (f *FieldScheduleController) Stream(c *gin.Context) {
+	snapshot, closeSubscription, messages, ok := f.subscribe(c)
+	if !ok {
+		return
+	}
+	defer closeSubscription()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("snapshot", snapshot)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case message, open := <-messages:
+			if !open {
+				return false
+			}
+			c.SSEvent("availability", json.RawMessage(message))
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": ping\n\n")
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// StreamWebSocket sends the same events as Stream over a WebSocket, each as a
+// JSON message of the form {"event": ..., "data": ...}.
+func (f *FieldScheduleController) StreamWebSocket(c *gin.Context) {
+	snapshot, closeSubscription, messages, ok := f.subscribe(c)
+	if !ok {
+		return
+	}
+	defer closeSubscription()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Clients only listen; reading detects when they go away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = conn.WriteJSON(gin.H{"event": "snapshot", "data": snapshot})
+	if err != nil {
+		return
+	}
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-c.Request.Context().Done():
+			return
+		case message, open := <-messages:
+			if !open {
+				return
+			}
+			err = conn.WriteJSON(gin.H{"event": "availability", "data": json.RawMessage(message)})
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"field-service/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	config.Config.PubSub.AllowedOrigins = []string{"https://app.example.com/"}
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "", want: true},
+		{origin: "https://app.example.com", want: true},
+		{origin: "https://APP.example.com", want: true},
+		{origin: "https://evil.example.com", want: false},
+		{origin: "http://app.example.com", want: false},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/field/schedule/ws/x", nil)
+		if tt.origin != "" {
+			request.Header.Set("Origin", tt.origin)
+		}
+		if got := checkOrigin(request); got != tt.want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	errValidation "field-service/common/error"
+	"field-service/common/response"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errImporter "field-service/constants/error/importer"
+	"field-service/domain/dto"
+	"field-service/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type ImporterController struct {
+	service services.IServiceRegistry
+}
+
+type IImporterController interface {
+	Import(*gin.Context)
+}
+
+func NewImporterController(service services.IServiceRegistry) IImporterController {
+	return &ImporterController{service: service}
+}
+
+func (i *ImporterController) Import(c *gin.Context) {
+	var request dto.ImportRequest
+	err := c.ShouldBindWith(&request, binding.FormMultipart)
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  err,
+			Gin:  c,
+		})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(request)
+	if err != nil {
+		errMessage := http.StatusText(http.StatusUnprocessableEntity)
+		errorResponse := errValidation.ErrValidationResponse(err)
+		response.HttpResponse(response.ParamHTTPResp{
+			Code:    http.StatusBadRequest,
+			Err:     err,
+			Message: &errMessage,
+			Data:    errorResponse,
+			Gin:     c,
+		})
+		return
+	}
+	if request.File.Size > constants.MaxImportFileSize {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  errConstant.ErrSizeTooBig,
+			Gin:  c,
+		})
+		return
+	}
+	file, err := request.File.Open()
+	if err != nil {
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: http.StatusBadRequest,
+			Err:  errImporter.ErrImportFileInvalid,
+			Gin:  c,
+		})
+		return
+	}
+	defer file.Close()
+
+	result, err := i.service.GetImporter().Import(c, request.Type, request.File.Filename, file, request.DryRun)
+	if err != nil {
+		// An import refused for invalid rows still returns its report, which
+		// lists them.
+		code := http.StatusBadRequest
+		if result != nil {
+			code = http.StatusUnprocessableEntity
+		}
+		response.HttpResponse(response.ParamHTTPResp{
+			Code: code,
+			Err:  err,
+			Data: result,
+			Gin:  c,
+		})
+		return
+	}
+	response.HttpResponse(response.ParamHTTPResp{
+		Code: http.StatusOK,
+		Data: result,
+		Gin:  c,
+	})
+}
@@ -1,9 +1,18 @@
 package controllers
 
 import (
+	analyticsControllers "field-service/controllers/analytics"
+	auditLogControllers "field-service/controllers/auditLog"
+	calendarControllers "field-service/controllers/calendar"
+	checkInControllers "field-service/controllers/checkIn"
+	dailySheetControllers "field-service/controllers/dailySheet"
+	exportControllers "field-service/controllers/export"
 	fieldControllers "field-service/controllers/field"
 	fieldSchedulecontrollers "field-service/controllers/fieldSchedule"
+	importerControllers "field-service/controllers/importer"
+	maintenanceWindowControllers "field-service/controllers/maintenanceWindow"
 	timeControllers "field-service/controllers/time"
+	webhookControllers "field-service/controllers/webhook"
 	"field-service/services"
 )
 
@@ -15,6 +24,15 @@ type IControllerRegistry interface {
 	GetField() fieldControllers.IFieldController
 	GetFieldSchedule() fieldSchedulecontrollers.IFieldScheduleController
 	GetTime() timeControllers.ITimeController
+	GetMaintenanceWindow() maintenanceWindowControllers.IMaintenanceWindowController
+	GetAuditLog() auditLogControllers.IAuditLogController
+	GetWebhook() webhookControllers.IWebhookController
+	GetCheckIn() checkInControllers.ICheckInController
+	GetCalendar() calendarControllers.ICalendarController
+	GetImporter() importerControllers.IImporterController
+	GetExport() exportControllers.IExportController
+	GetAnalytics() analyticsControllers.IAnalyticsController
+	GetDailySheet() dailySheetControllers.IDailySheetController
 }
 
 func NewControllerRegistry(service services.IServiceRegistry) IControllerRegistry {
@@ -35,3 +53,48 @@ func (r *Registry) GetTime() timeControllers.ITimeController {
 func (r *Registry) GetField() fieldControllers.IFieldController {
 	return fieldControllers.NewFieldController(r.service)
 }
+
+// GetMaintenanceWindow implements IControllerRegistry.
+func (r *Registry) GetMaintenanceWindow() maintenanceWindowControllers.IMaintenanceWindowController {
+	return maintenanceWindowControllers.NewMaintenanceWindowController(r.service)
+}
+
+// GetAuditLog implements IControllerRegistry.
+func (r *Registry) GetAuditLog() auditLogControllers.IAuditLogController {
+	return auditLogControllers.NewAuditLogController(r.service)
+}
+
+// GetWebhook implements IControllerRegistry.
+func (r *Registry) GetWebhook() webhookControllers.IWebhookController {
+	return webhookControllers.NewWebhookController(r.service)
+}
+
+// GetCheckIn implements IControllerRegistry.
+func (r *Registry) GetCheckIn() checkInControllers.ICheckInController {
+	return checkInControllers.NewCheckInController(r.service)
+}
+
+// GetCalendar implements IControllerRegistry.
+func (r *Registry) GetCalendar() calendarControllers.ICalendarController {
+	return calendarControllers.NewCalendarController(r.service)
+}
+
+// GetImporter implements IControllerRegistry.
+func (r *Registry) GetImporter() importerControllers.IImporterController {
+	return importerControllers.NewImporterController(r.service)
+}
+
+// GetExport implements IControllerRegistry.
+func (r *Registry) GetExport() exportControllers.IExportController {
+	return exportControllers.NewExportController(r.service)
+}
+
+// GetAnalytics implements IControllerRegistry.
+func (r *Registry) GetAnalytics() analyticsControllers.IAnalyticsController {
+	return analyticsControllers.NewAnalyticsController(r.service)
+}
+
+// GetDailySheet implements IControllerRegistry.
+func (r *Registry) GetDailySheet() dailySheetControllers.IDailySheetController {
+	return dailySheetControllers.NewDailySheetController(r.service)
+}
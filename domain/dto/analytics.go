@@ -0,0 +1,38 @@
+package dto
+
+import "github.com/google/uuid"
+
+// AnalyticsRequestParam selects the figures of one venue, which is the tenant
+// the request is scoped to: the tenant of the caller, or the one picked by
+// x-tenant-id for an admin without a tenant. Figures never span venues.
+type AnalyticsRequestParam struct {
+	StartDate   string  `form:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate     string  `form:"endDate" validate:"required,datetime=2006-01-02"`
+	FieldID     *string `form:"fieldID" validate:"omitempty,uuid"`
+	Granularity string  `form:"granularity" validate:"omitempty,oneof=day week month"`
+}
+
+type AnalyticsUtilizationRequestParam struct {
+	AnalyticsRequestParam
+	GroupBy string `form:"groupBy" validate:"required,oneof=field weekday hour"`
+}
+
+// AnalyticsRow is one bucket of an analytics query. Period is the first day
+// of the day, week or month it covers and Weekday runs from 1 for Monday to 7
+// for Sunday. Only the columns of the dimension grouped by are set.
+// Utilization is the percentage of the slots offered for booking that were
+// booked.
+type AnalyticsRow struct {
+	Period        string     `json:"period"`
+	FieldID       *uuid.UUID `json:"fieldID,omitempty"`
+	FieldName     string     `json:"fieldName,omitempty"`
+	Weekday       *int       `json:"weekday,omitempty"`
+	Hour          *int       `json:"hour,omitempty"`
+	Band          string     `json:"band,omitempty"`
+	SlotCount     int        `json:"slotCount"`
+	CapacityCount int        `json:"capacityCount"`
+	BookedCount   int        `json:"bookedCount"`
+	BookedMinutes int        `json:"bookedMinutes"`
+	Revenue       int64      `json:"revenue"`
+	Utilization   float64    `json:"utilization"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry describes one mutation. Before and After are snapshots of the
+// entity; either is nil when the entity was created or deleted.
+type AuditEntry struct {
+	Action     string
+	Entity     string
+	EntityUUID uuid.UUID
+	Before     interface{}
+	After      interface{}
+}
+
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLogRequestParam struct {
+	Page     int     `form:"page" validate:"required"`
+	Limit    int     `form:"limit" validate:"required"`
+	Entity   *string `form:"entity" validate:"omitempty,oneof=field time field_schedule"`
+	EntityID *string `form:"entityID" validate:"omitempty,uuid"`
+	ActorID  *string `form:"actorID" validate:"omitempty,uuid"`
+	Action   *string `form:"action" validate:"omitempty,oneof=create update update_status delete restore"`
+	From     *string `form:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To       *string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type AuditLogResponse struct {
+	UUID      uuid.UUID       `json:"uuid"`
+	ActorID   *uuid.UUID      `json:"actorID"`
+	ActorName string          `json:"actorName"`
+	ActorRole string          `json:"actorRole"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  uuid.UUID       `json:"entityID"`
+	Changes   json.RawMessage `json:"changes"`
+	RequestID string          `json:"requestID"`
+	IPAddress string          `json:"ipAddress"`
+	CreatedAt *time.Time      `json:"createdAt"`
+}
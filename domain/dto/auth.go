@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserLogin struct {
+	UUID        uuid.UUID `json:"uuid"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	PhoneNumber string    `json:"phoneNumber"`
+	TenantID    string    `json:"tenantID"`
+	ExpiresAt   time.Time `json:"-"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedResponse carries the feed URL, which is the only credential
+// needed to read the feed.
+type CalendarFeedResponse struct {
+	UUID      uuid.UUID  `json:"uuid"`
+	Type      string     `json:"type"`
+	FieldID   *uuid.UUID `json:"fieldID,omitempty"`
+	FieldName string     `json:"fieldName,omitempty"`
+	URL       string     `json:"url"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
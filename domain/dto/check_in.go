@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckInClaims are the booking details signed into a check-in code. A code
+// is only valid while the booking still matches them.
+type CheckInClaims struct {
+	ScheduleUUID uuid.UUID `json:"s"`
+	FieldUUID    uuid.UUID `json:"f"`
+	OrderID      string    `json:"o"`
+	Date         string    `json:"d"`
+	StartTime    string    `json:"st"`
+	EndTime      string    `json:"et"`
+}
+
+type CheckInCodeResponse struct {
+	Code      string    `json:"code"`
+	QRCode    string    `json:"qrCode"`
+	OpensAt   time.Time `json:"opensAt"`
+	ClosesAt  time.Time `json:"closesAt"`
+	FieldName string    `json:"fieldName"`
+	Date      string    `json:"date"`
+	Time      string    `json:"time"`
+}
+
+type CheckInRequest struct {
+	Code    string `json:"code" validate:"required"`
+	FieldID string `json:"fieldID" validate:"required,uuid"`
+}
+
+type CustomerNoShowResponse struct {
+	CustomerUUID   uuid.UUID `json:"customerUUID"`
+	NoShowCount    int64     `json:"noShowCount"`
+	LastNoShowDate *string   `json:"lastNoShowDate"`
+}
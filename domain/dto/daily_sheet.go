@@ -0,0 +1,6 @@
+package dto
+
+type DailySheetRequestParam struct {
+	Date    string  `form:"date" validate:"required,datetime=2006-01-02"`
+	FieldID *string `form:"fieldID" validate:"omitempty,uuid"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FieldScheduleEvent struct {
+	ScheduleUUID uuid.UUID `json:"scheduleUUID"`
+	FieldUUID    uuid.UUID `json:"fieldUUID"`
+	FieldName    string    `json:"fieldName"`
+	Date         string    `json:"date"`
+	StartTime    string    `json:"startTime"`
+	EndTime      string    `json:"endTime"`
+	Status       string    `json:"status"`
+	Reference    string    `json:"reference,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"field-service/constants"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldScheduleExportFilter narrows an export the way the pagination
+// endpoint is filtered. BookedOnly keeps the slots held by an order, whatever
+// their booking status.
+type FieldScheduleExportFilter struct {
+	FieldScheduleFilterParam
+	BookedOnly bool `form:"bookedOnly" json:"bookedOnly"`
+}
+
+type FieldScheduleExportRequestParam struct {
+	Format string `form:"format" json:"format" validate:"required,oneof=csv xlsx"`
+	FieldScheduleExportFilter
+}
+
+// FieldScheduleExportRow is one exported schedule. Price is what the slot was
+// booked at, and the booker columns are empty for schedules not held by an
+// order.
+type FieldScheduleExportRow struct {
+	UUID         uuid.UUID
+	FieldCode    string
+	FieldName    string
+	Date         time.Time
+	StartTime    string
+	EndTime      string
+	Status       constants.FieldScheduleStatus
+	PricePerHour int
+	Price        int
+	OrderID      string
+	BookerName   string
+	BookerEmail  string
+	BookerPhone  string
+}
+
+type ExportJobResponse struct {
+	UUID        uuid.UUID  `json:"uuid"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	RowCount    int        `json:"rowCount"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL *string    `json:"downloadURL,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
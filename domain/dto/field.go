@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"field-service/constants"
 	"mime/multipart"
 	"time"
 
@@ -12,6 +13,8 @@ type FieldRequest struct {
 	Code         string                 `form:"code" validate:"required"`
 	PricePerHour int                    `form:"pricePerHour" validate:"required"`
 	Images       []multipart.FileHeader `form:"images" validate:"required"`
+	ParentID     *string                `form:"parentID" validate:"omitempty,uuid"`
+	OwnerID      *string                `form:"ownerID" validate:"omitempty,uuid"`
 }
 
 type UpdateFieldRequest struct {
@@ -19,31 +22,45 @@ type UpdateFieldRequest struct {
 	Code         string                 `form:"code" validate:"required"`
 	PricePerHour int                    `form:"pricePerHour" validate:"required"`
 	Images       []multipart.FileHeader `form:"images"`
+	ParentID     *string                `form:"parentID" validate:"omitempty,uuid"`
+}
+
+type UpdateFieldStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=Active Inactive Maintenance"`
 }
 
 type FieldResponse struct {
-	UUID         uuid.UUID  `json:"uuid"`
-	Code         string     `json:"code"`
-	Name         string     `json:"name"`
-	PricePerHour int        `json:"pricePerHour"`
-	Images       []string   `json:"images"`
-	CreatedAt    *time.Time `json:"createdAt"`
-	UpdatedAt    *time.Time `json:"updatedAt"`
+	UUID         uuid.UUID                 `json:"uuid"`
+	Code         string                    `json:"code"`
+	Name         string                    `json:"name"`
+	PricePerHour int                       `json:"pricePerHour"`
+	Images       []string                  `json:"images"`
+	Status       constants.FieldStatusName `json:"status"`
+	ParentID     *uuid.UUID                `json:"parentID"`
+	OwnerID      *uuid.UUID                `json:"ownerID"`
+	CreatedAt    *time.Time                `json:"createdAt"`
+	UpdatedAt    *time.Time                `json:"updatedAt"`
+	DeletedAt    *time.Time                `json:"deletedAt,omitempty"`
 }
 
 type FieldDetailResponse struct {
-	UUID         uuid.UUID  `json:"uuid"`
-	Code         string     `json:"code"`
-	Name         string     `json:"name"`
-	PricePerHour int        `json:"pricePerHour"`
-	Images       []string   `json:"images"`
-	CreatedAt    *time.Time `json:"createdAt"`
-	UpdatedAt    *time.Time `json:"updatedAt"`
+	UUID         uuid.UUID                 `json:"uuid"`
+	Code         string                    `json:"code"`
+	Name         string                    `json:"name"`
+	PricePerHour int                       `json:"pricePerHour"`
+	Images       []string                  `json:"images"`
+	Status       constants.FieldStatusName `json:"status"`
+	CreatedAt    *time.Time                `json:"createdAt"`
+	UpdatedAt    *time.Time                `json:"updatedAt"`
 }
 
 type FieldRequestParam struct {
 	Page       int     `form:"page" validate:"required"`
 	Limit      int     `form:"limit" validate:"required"`
-	SortColumn *string `form:"sortColumn"`
-	SortOrder  *string `form:"sortOrder"`
+	SortColumn *string `form:"sortColumn" validate:"omitempty,oneof=name code pricePerHour createdAt updatedAt"`
+	SortOrder  *string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	Search     *string `form:"search"`
+	MinPrice   *int    `form:"minPrice" validate:"omitempty,min=0"`
+	MaxPrice   *int    `form:"maxPrice" validate:"omitempty,min=0"`
+	Status     *string `form:"status" validate:"omitempty,oneof=Active Inactive Maintenance"`
 }
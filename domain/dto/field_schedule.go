@@ -23,7 +23,9 @@ type UpdateFieldScheduleRequest struct {
 }
 
 type UpdateStatusFieldScheduleRequest struct {
-	FieldScheduleIDs []string `json:"fieldScheduleIDs" validate:"required"`
+	FieldScheduleIDs []string         `json:"fieldScheduleIDs" validate:"required"`
+	OrderID          string           `json:"orderID" validate:"omitempty,max=100"`
+	Customer         *BookingCustomer `json:"customer"`
 }
 
 type FieldScheduleResponse struct {
@@ -45,13 +47,54 @@ type FieldScheduleForBookingResponse struct {
 	Time         string                            `json:"time"`
 }
 
+type FieldScheduleFilterParam struct {
+	FieldID   *string `form:"fieldID" validate:"omitempty,uuid"`
+	StartDate *string `form:"startDate" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   *string `form:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	Status    *string `form:"status" validate:"omitempty,oneof=Available Booked Maintenance Blocked CheckedIn NoShow"`
+}
+
 type FieldScheduleRequestParam struct {
 	Page       int     `form:"page"  validate:"required"`
 	Limit      int     `form:"limit" validate:"required"`
-	SortColumn *string `form:"sortColumn"`
-	SortOrder  *string `form:"sortOrder"`
+	SortColumn *string `form:"sortColumn" validate:"omitempty,oneof=date status createdAt updatedAt"`
+	SortOrder  *string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	FieldScheduleFilterParam
+}
+
+type FieldScheduleCursorRequestParam struct {
+	Limit     int     `form:"limit" validate:"required,min=1,max=100"`
+	Cursor    *string `form:"cursor"`
+	WithTotal bool    `form:"withTotal"`
+	FieldScheduleFilterParam
+}
+
+// FieldScheduleCursor is the keyset position encoded into nextCursor and
+// prevCursor. Schedules are ordered by (date, start_time, id). Query is the
+// hash of the filter and order the cursor was issued for.
+type FieldScheduleCursor struct {
+	Date      string `json:"d"`
+	StartTime string `json:"t"`
+	ID        uint   `json:"i"`
+	Backward  bool   `json:"b"`
+	Query     string `json:"q"`
 }
 
 type FieldScheduleByFieldIDAndDateRequestParam struct {
 	Date string `json:"date" validate:"required"`
 }
+
+type FieldScheduleStreamRequestParam struct {
+	Date string `form:"date" validate:"required,datetime=2006-01-02"`
+}
+
+// FieldScheduleAvailabilityEvent is pushed to the streams of the schedule's
+// field and date when its status changes.
+type FieldScheduleAvailabilityEvent struct {
+	UUID       uuid.UUID                         `json:"uuid"`
+	Date       string                            `json:"date"`
+	Time       string                            `json:"time"`
+	Status     constants.FieldScheduleStatusName `json:"status"`
+	Event      string                            `json:"event"`
+	OccurredAt time.Time                         `json:"occurredAt"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"field-service/constants"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FieldScheduleHistoryResponse struct {
+	UUID       uuid.UUID                          `json:"uuid"`
+	Event      string                             `json:"event"`
+	FromStatus *constants.FieldScheduleStatusName `json:"fromStatus"`
+	Status     constants.FieldScheduleStatusName  `json:"status"`
+	Date       string                             `json:"date"`
+	Time       string                             `json:"time"`
+	Reference  string                             `json:"reference"`
+	ActorID    *uuid.UUID                         `json:"actorID"`
+	ActorName  string                             `json:"actorName"`
+	ActorRole  string                             `json:"actorRole"`
+	CreatedAt  *time.Time                         `json:"createdAt"`
+}
+
+type FieldScheduleBoardRequestParam struct {
+	FieldID string `form:"fieldID" validate:"required,uuid"`
+	Date    string `form:"date" validate:"required,datetime=2006-01-02"`
+	At      string `form:"at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type FieldScheduleBoardResponse struct {
+	UUID      uuid.UUID                         `json:"uuid"`
+	Date      string                            `json:"date"`
+	Time      string                            `json:"time"`
+	Status    constants.FieldScheduleStatusName `json:"status"`
+	Reference string                            `json:"reference"`
+	ChangedBy string                            `json:"changedBy"`
+	ChangedAt *time.Time                        `json:"changedAt"`
+}
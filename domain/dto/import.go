@@ -0,0 +1,29 @@
+package dto
+
+import "mime/multipart"
+
+type ImportRequest struct {
+	Type   string                `form:"type" validate:"required,oneof=fields times schedules"`
+	DryRun bool                  `form:"dryRun"`
+	File   *multipart.FileHeader `form:"file" validate:"required"`
+}
+
+// ImportRowError points at the spreadsheet row, and column when known, that
+// cannot be imported. Row 1 is the header.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportResponse struct {
+	Type      string           `json:"type"`
+	DryRun    bool             `json:"dryRun"`
+	Committed bool             `json:"committed"`
+	Total     int              `json:"total"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Failed    int              `json:"failed"`
+	Errors    []ImportRowError `json:"errors"`
+}
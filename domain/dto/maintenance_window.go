@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MaintenanceWindowRequest struct {
+	FieldID   string `json:"fieldID" validate:"required,uuid"`
+	StartDate string `json:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"endDate" validate:"required,datetime=2006-01-02"`
+	StartTime string `json:"startTime" validate:"required,datetime=15:04"`
+	EndTime   string `json:"endTime" validate:"required,datetime=15:04"`
+	Reason    string `json:"reason" validate:"required,max=255"`
+	Force     bool   `json:"force"`
+}
+
+type MaintenanceWindowRequestParam struct {
+	FieldID string `form:"fieldID" validate:"required,uuid"`
+}
+
+type MaintenanceWindowResponse struct {
+	UUID              uuid.UUID  `json:"uuid"`
+	FieldName         string     `json:"fieldName"`
+	StartDate         string     `json:"startDate"`
+	EndDate           string     `json:"endDate"`
+	StartTime         string     `json:"startTime"`
+	EndTime           string     `json:"endTime"`
+	Reason            string     `json:"reason"`
+	CancelledBookings int        `json:"cancelledBookings"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	UpdatedAt         *time.Time `json:"updatedAt"`
+}
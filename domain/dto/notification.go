@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/google/uuid"
+
+// BookingCustomer is the customer of an order, sent along with its booking so
+// that reminders can reach them.
+type BookingCustomer struct {
+	UUID        uuid.UUID `json:"uuid" validate:"required"`
+	Name        string    `json:"name" validate:"max=100"`
+	Email       string    `json:"email" validate:"omitempty,email,max=100"`
+	PhoneNumber string    `json:"phoneNumber" validate:"omitempty,max=20"`
+	Language    string    `json:"language" validate:"omitempty,oneof=id en"`
+}
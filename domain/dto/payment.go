@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentEvent is the payment result published by the payment service for an
+// order and the schedules it covers.
+type PaymentEvent struct {
+	OrderID     string           `json:"orderID" validate:"required,max=100"`
+	Status      string           `json:"status" validate:"required,oneof=paid expired failed refunded"`
+	ScheduleIDs []uuid.UUID      `json:"scheduleIDs"`
+	OccurredAt  time.Time        `json:"occurredAt" validate:"required"`
+	Customer    *BookingCustomer `json:"customer"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRequest struct {
+	FieldID    *string  `json:"fieldID" validate:"omitempty,uuid"`
+	TargetURL  string   `json:"targetURL" validate:"required,url,startswith=https://,max=500"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=field_schedule.booked field_schedule.cancelled field_schedule.released field_schedule.rescheduled field_schedule.checked_in field_schedule.no_show"`
+	Secret     *string  `json:"secret" validate:"omitempty,min=16,max=100"`
+}
+
+type UpdateWebhookRequest struct {
+	TargetURL  string   `json:"targetURL" validate:"required,url,startswith=https://,max=500"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=field_schedule.booked field_schedule.cancelled field_schedule.released field_schedule.rescheduled field_schedule.checked_in field_schedule.no_show"`
+	Active     bool     `json:"active"`
+}
+
+type WebhookDeliveryRequestParam struct {
+	Page   int     `form:"page" validate:"required"`
+	Limit  int     `form:"limit" validate:"required"`
+	Status *string `form:"status" validate:"omitempty,oneof=pending succeeded failed"`
+}
+
+// WebhookResponse only carries the secret when the webhook is created.
+type WebhookResponse struct {
+	UUID                uuid.UUID  `json:"uuid"`
+	FieldID             *uuid.UUID `json:"fieldID"`
+	FieldName           string     `json:"fieldName,omitempty"`
+	TargetURL           string     `json:"targetURL"`
+	EventTypes          []string   `json:"eventTypes"`
+	Secret              string     `json:"secret,omitempty"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt"`
+	CreatedAt           *time.Time `json:"createdAt"`
+	UpdatedAt           *time.Time `json:"updatedAt"`
+}
+
+type WebhookDeliveryResponse struct {
+	UUID           uuid.UUID       `json:"uuid"`
+	EventID        string          `json:"eventID"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	ResponseStatus int             `json:"responseStatus"`
+	LastError      string          `json:"lastError"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+	CreatedAt      *time.Time      `json:"createdAt"`
+}
+
+// WebhookPayload is the body posted to a webhook.
+type WebhookPayload struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
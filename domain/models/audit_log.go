@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is append-only: rows are never updated or deleted, which the
+// audit_logs_append_only trigger also enforces in the database.
+type AuditLog struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"`
+	UUID       uuid.UUID  `gorm:"type:uuid;not null"`
+	TenantID   string     `gorm:"type:varchar(50);not null;index"`
+	ActorUUID  *uuid.UUID `gorm:"type:uuid;index"`
+	ActorName  string     `gorm:"type:varchar(100)"`
+	ActorRole  string     `gorm:"type:varchar(20)"`
+	Action     string     `gorm:"type:varchar(30);not null"`
+	Entity     string     `gorm:"type:varchar(30);not null;index:idx_audit_logs_entity"`
+	EntityUUID uuid.UUID  `gorm:"type:uuid;not null;index:idx_audit_logs_entity"`
+	Changes    string     `gorm:"type:jsonb;not null"`
+	RequestID  string     `gorm:"type:varchar(100)"`
+	IPAddress  string     `gorm:"type:varchar(45)"`
+	CreatedAt  *time.Time `gorm:"index"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeed grants access to an ICS feed through its unguessable token:
+// the booked and maintenance slots of one field, or the bookings of one
+// customer.
+type CalendarFeed struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement"`
+	UUID         uuid.UUID  `gorm:"type:uuid;not null"`
+	TenantID     string     `gorm:"type:varchar(50);not null;index"`
+	Token        string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Type         string     `gorm:"type:varchar(20);not null"`
+	OwnerUUID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FieldID      *uint      `gorm:"type:int"`
+	CustomerUUID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+	DeletedAt    *gorm.DeletedAt
+	Field        *Field `gorm:"foreignKey:field_id; references:id"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportJob is a schedule export written to storage in the background, for
+// exports too large to stream in one request. OwnerUUID limits it to the
+// fields of that owner when the requester could only see their own.
+type ExportJob struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	UUID        uuid.UUID  `gorm:"type:uuid;not null"`
+	TenantID    string     `gorm:"type:varchar(50);not null;index"`
+	RequestedBy uuid.UUID  `gorm:"type:uuid;not null;index"`
+	OwnerUUID   *uuid.UUID `gorm:"type:uuid"`
+	Format      string     `gorm:"type:varchar(10);not null"`
+	Filter      string     `gorm:"type:jsonb;not null"`
+	Status      string     `gorm:"type:varchar(20);not null;index"`
+	RowCount    int        `gorm:"type:int;not null;default:0"`
+	ObjectName  string     `gorm:"type:varchar(255)"`
+	LastError   string     `gorm:"type:text"`
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"field-service/constants"
 	"time"
 
 	"github.com/lib/pq"
@@ -10,14 +11,20 @@ import (
 )
 
 type Field struct {
-	ID            uint           `gorm:"primaryKey;autoIncrement"`
-	UUID          uuid.UUID      `gorm:"type:uuid;not null"`
-	Code          string         `gorm:"type:varchar(15); not null"`
-	Name          string         `gorm:"type:varchar(100); not null"`
-	PricePerHour  int            `gorm:"type:int; not null"`
-	Images        pq.StringArray `gorm:"type:text[]; not null"`
+	ID            uint                  `gorm:"primaryKey;autoIncrement"`
+	UUID          uuid.UUID             `gorm:"type:uuid;not null"`
+	TenantID      string                `gorm:"type:varchar(50);not null;index"`
+	Code          string                `gorm:"type:varchar(15); not null"`
+	Name          string                `gorm:"type:varchar(100); not null"`
+	PricePerHour  int                   `gorm:"type:int; not null"`
+	Images        pq.StringArray        `gorm:"type:text[]; not null"`
+	Status        constants.FieldStatus `gorm:"type:int; not null; default:100"`
+	ParentID      *uint                 `gorm:"type:int"`
+	OwnerUUID     *uuid.UUID            `gorm:"type:uuid"`
 	CreatedAt     *time.Time
 	UpdatedAt     *time.Time
 	DeletedAt     *gorm.DeletedAt
 	FieldSchedule []FieldSchedule `gorm:"foreignKey:field_id; references:id; constraint:OnUpdate:CASCADE, OnDelete:CASCADE"`
+	Parent        *Field          `gorm:"foreignKey:parent_id; references:id; constraint:OnUpdate:CASCADE, OnDelete:SET NULL"`
+	Children      []Field         `gorm:"foreignKey:parent_id; references:id"`
 }
@@ -8,13 +8,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// FieldSchedule is one slot of a field on a date. Price is what the slot cost
+// when it was booked and is kept for as long as it stays booked.
 type FieldSchedule struct {
 	ID        uint                          `gorm:"primaryKey;autoIncrement"`
 	UUID      uuid.UUID                     `gorm:"type:uuid;not null"`
+	TenantID  string                        `gorm:"type:varchar(50);not null;index"`
 	FieldID   uint                          `gorm:"type:int;not null"`
 	TimeID    uint                          `gorm:"type:int;not null"`
 	Date      time.Time                     `gorm:"type:date;not null"`
 	Status    constants.FieldScheduleStatus `gorm:"type:int;not null"`
+	OrderID   string                        `gorm:"type:varchar(100);index"`
+	Price     int                           `gorm:"type:int;not null;default:0"`
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
 	DeletedAt *gorm.DeletedAt
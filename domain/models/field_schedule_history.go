@@ -0,0 +1,32 @@
+package models
+
+import (
+	"field-service/constants"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldScheduleHistory is one change of a schedule's status, date or time. It
+// keeps the resulting state, so the latest row before a moment is the state
+// of the schedule at that moment.
+type FieldScheduleHistory struct {
+	ID                uint                           `gorm:"primaryKey;autoIncrement"`
+	UUID              uuid.UUID                      `gorm:"type:uuid;not null"`
+	TenantID          string                         `gorm:"type:varchar(50);not null;index"`
+	FieldScheduleID   uint                           `gorm:"type:int;not null;index"`
+	FieldScheduleUUID uuid.UUID                      `gorm:"type:uuid;not null;index"`
+	FieldID           uint                           `gorm:"type:int;not null;index"`
+	TimeID            uint                           `gorm:"type:int;not null"`
+	Date              time.Time                      `gorm:"type:date;not null"`
+	Event             string                         `gorm:"type:varchar(30);not null"`
+	FromStatus        *constants.FieldScheduleStatus `gorm:"type:int"`
+	Status            constants.FieldScheduleStatus  `gorm:"type:int;not null"`
+	Reference         string                         `gorm:"type:varchar(100)"`
+	ActorUUID         *uuid.UUID                     `gorm:"type:uuid"`
+	ActorName         string                         `gorm:"type:varchar(100)"`
+	ActorRole         string                         `gorm:"type:varchar(20)"`
+	CreatedAt         *time.Time                     `gorm:"index"`
+	Field             Field                          `gorm:"foreignKey:field_id; references:id"`
+	Time              Time                           `gorm:"foreignKey:time_id; references:id"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// FieldScheduleRollup sums the schedules of a field starting in one hour of
+// one day. The analytics endpoints read these rows instead of the schedules.
+// UnavailableCount holds the slots in Maintenance or Blocked, which are not
+// offered for booking. Revenue sums the prices the slots were booked at.
+type FieldScheduleRollup struct {
+	ID               uint      `gorm:"primaryKey;autoIncrement"`
+	TenantID         string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_field_schedule_rollups_slot"`
+	FieldID          uint      `gorm:"type:int;not null;uniqueIndex:idx_field_schedule_rollups_slot"`
+	Date             time.Time `gorm:"type:date;not null;uniqueIndex:idx_field_schedule_rollups_slot;index"`
+	Hour             int       `gorm:"type:int;not null;uniqueIndex:idx_field_schedule_rollups_slot"`
+	SlotCount        int       `gorm:"type:int;not null"`
+	BookedCount      int       `gorm:"type:int;not null"`
+	UnavailableCount int       `gorm:"type:int;not null"`
+	BookedMinutes    int       `gorm:"type:int;not null"`
+	Revenue          int64     `gorm:"type:bigint;not null"`
+	RefreshedAt      time.Time `gorm:"not null;index"`
+}
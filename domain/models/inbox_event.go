@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// InboxEvent remembers an event received from a broker, so a redelivery of
+// the same event ID is recognised and skipped.
+type InboxEvent struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	EventID   string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	TenantID  string `gorm:"type:varchar(50);not null;index"`
+	Type      string `gorm:"type:varchar(50);not null"`
+	CreatedAt *time.Time
+}
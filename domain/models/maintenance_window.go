@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MaintenanceWindow struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UUID      uuid.UUID `gorm:"type:uuid;not null"`
+	TenantID  string    `gorm:"type:varchar(50);not null;index"`
+	FieldID   uint      `gorm:"type:int;not null"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	StartTime string    `gorm:"type:time without time zone;not null"`
+	EndTime   string    `gorm:"type:time without time zone;not null"`
+	Reason    string    `gorm:"type:varchar(255);not null"`
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+	DeletedAt *gorm.DeletedAt
+	Field     Field `gorm:"foreignKey:field_id; references:id; constraint:OnUpdate:CASCADE, OnDelete:CASCADE"`
+}
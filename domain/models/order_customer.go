@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderCustomer is the customer who placed an order, kept to send reminders
+// for the schedules the order books.
+type OrderCustomer struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	TenantID     string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_order_customers_order"`
+	OrderID      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_order_customers_order"`
+	CustomerUUID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name         string    `gorm:"type:varchar(100)"`
+	Email        string    `gorm:"type:varchar(100)"`
+	PhoneNumber  string    `gorm:"type:varchar(20)"`
+	Language     string    `gorm:"type:varchar(5)"`
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OutboxEvent is a domain event written in the transaction of the change it
+// announces and published afterwards by the outbox relay. UUID is the event ID
+// consumers de-duplicate on. PublishedSinks lists the sinks that already
+// accepted the event, so a retry only goes to the others.
+type OutboxEvent struct {
+	ID             uint           `gorm:"primaryKey;autoIncrement"`
+	UUID           uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex"`
+	TenantID       string         `gorm:"type:varchar(50);not null;index"`
+	Type           string         `gorm:"type:varchar(50);not null"`
+	Key            string         `gorm:"type:varchar(100);not null"`
+	Payload        string         `gorm:"type:jsonb;not null"`
+	Status         string         `gorm:"type:varchar(20);not null;index:idx_outbox_events_due"`
+	Attempts       int            `gorm:"type:int;not null;default:0"`
+	NextAttemptAt  time.Time      `gorm:"not null;index:idx_outbox_events_due"`
+	LastError      string         `gorm:"type:text"`
+	PublishedSinks pq.StringArray `gorm:"type:text[]"`
+	PublishedAt    *time.Time
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+}
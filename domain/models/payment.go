@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Payment is the latest payment result applied for an order. OccurredAt is
+// the time of that result, used to ignore older results arriving late.
+type Payment struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement"`
+	TenantID   string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_payments_order"`
+	OrderID    string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_payments_order"`
+	Status     string    `gorm:"type:varchar(20);not null"`
+	OccurredAt time.Time `gorm:"not null"`
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
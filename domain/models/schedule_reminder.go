@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleReminder is one reminder of a booked schedule through one channel.
+// It is unique per order, offset and channel, so a reminder is never sent
+// twice, while a slot booked again by another order gets its own reminders.
+type ScheduleReminder struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement"`
+	UUID            uuid.UUID `gorm:"type:uuid;not null"`
+	TenantID        string    `gorm:"type:varchar(50);not null;index"`
+	FieldScheduleID uint      `gorm:"type:int;not null;uniqueIndex:idx_schedule_reminders_sent"`
+	OrderID         string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_schedule_reminders_sent"`
+	OffsetMinute    int       `gorm:"type:int;not null;uniqueIndex:idx_schedule_reminders_sent"`
+	Channel         string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_schedule_reminders_sent"`
+	Status          string    `gorm:"type:varchar(20);not null;index:idx_schedule_reminders_due"`
+	Attempts        int       `gorm:"type:int;not null;default:0"`
+	NextAttemptAt   time.Time `gorm:"not null;index:idx_schedule_reminders_due"`
+	LastError       string    `gorm:"type:text"`
+	SentAt          *time.Time
+	CreatedAt       *time.Time
+	UpdatedAt       *time.Time
+	FieldSchedule   *FieldSchedule `gorm:"foreignKey:field_schedule_id; references:id"`
+}
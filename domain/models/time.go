@@ -9,6 +9,7 @@ import (
 type Time struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement"`
 	UUID      uuid.UUID `gorm:"type:uuid;not null"`
+	TenantID  string    `gorm:"type:varchar(50);not null;index"`
 	StartTime string    `gorm:"type:time without time zone; not null"`
 	EndTime   string    `gorm:"type:time without time zone; not null"`
 	CreatedAt *time.Time
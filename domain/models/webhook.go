@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Webhook subscribes a partner's URL to schedule events of one field, or of
+// every field the owner has when FieldID is nil.
+type Webhook struct {
+	ID                  uint           `gorm:"primaryKey;autoIncrement"`
+	UUID                uuid.UUID      `gorm:"type:uuid;not null"`
+	TenantID            string         `gorm:"type:varchar(50);not null;index"`
+	OwnerUUID           uuid.UUID      `gorm:"type:uuid;not null;index"`
+	FieldID             *uint          `gorm:"type:int;index"`
+	TargetURL           string         `gorm:"type:varchar(500);not null"`
+	EventTypes          pq.StringArray `gorm:"type:text[];not null"`
+	Secret              string         `gorm:"type:varchar(100);not null"`
+	Active              bool           `gorm:"not null;default:true"`
+	ConsecutiveFailures int            `gorm:"type:int;not null;default:0"`
+	DisabledAt          *time.Time
+	CreatedAt           *time.Time
+	UpdatedAt           *time.Time
+	DeletedAt           *gorm.DeletedAt
+	Field               *Field `gorm:"foreignKey:field_id; references:id"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDelivery is one event to send to one webhook, with the outcome of
+// its latest attempt. Payload is the exact body that is signed and sent.
+type WebhookDelivery struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement"`
+	UUID           uuid.UUID `gorm:"type:uuid;not null"`
+	TenantID       string    `gorm:"type:varchar(50);not null;index"`
+	WebhookID      uint      `gorm:"type:int;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string    `gorm:"type:varchar(50);not null"`
+	Payload        string    `gorm:"type:jsonb;not null"`
+	Status         string    `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due"`
+	Attempts       int       `gorm:"type:int;not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null;index:idx_webhook_deliveries_due"`
+	ResponseStatus int       `gorm:"type:int"`
+	ResponseBody   string    `gorm:"type:text"`
+	LastError      string    `gorm:"type:text"`
+	DeliveredAt    *time.Time
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+	Webhook        *Webhook `gorm:"foreignKey:webhook_id; references:id"`
+}
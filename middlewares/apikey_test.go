@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"field-service/common/util"
+	"field-service/config"
+	"field-service/constants"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func apiKeyContext(serviceName, apiKey, requestAt string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/internal", nil)
+	c.Request.Header.Set(constants.XServiceName, serviceName)
+	c.Request.Header.Set(constants.XApiKey, apiKey)
+	c.Request.Header.Set(constants.XRequestAt, requestAt)
+	return c
+}
+
+func TestValidateApiKey(t *testing.T) {
+	config.Config.InternalAuth.SignatureKeys = map[string]string{"order-service": "order-key"}
+	now := time.Now()
+	requestAt := strconv.FormatInt(now.Unix(), 10)
+	apiKey := util.GenerateInternalApiKey("order-service", "order-key", requestAt)
+
+	if err := validateApiKey(apiKeyContext("order-service", apiKey, requestAt)); err != nil {
+		t.Fatalf("signed request rejected: %v", err)
+	}
+	if err := validateApiKey(apiKeyContext("order-service", apiKey, requestAt)); err == nil {
+		t.Error("replayed request accepted")
+	}
+
+	otherAt := strconv.FormatInt(now.Add(time.Second).Unix(), 10)
+	if err := validateApiKey(apiKeyContext("order-service", apiKey, otherAt)); err == nil {
+		t.Error("signature accepted for another x-request-at")
+	}
+	staleAt := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+	staleKey := util.GenerateInternalApiKey("order-service", "order-key", staleAt)
+	if err := validateApiKey(apiKeyContext("order-service", staleKey, staleAt)); err == nil {
+		t.Error("stale request accepted")
+	}
+}
@@ -0,0 +1,136 @@
+package middlewares
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"field-service/config"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type Claims struct {
+	User *dto.UserLogin `json:"user"`
+	jwt.RegisteredClaims
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+var (
+	jwksOnce sync.Once
+	jwksKeys map[string]*rsa.PublicKey
+)
+
+// loadJWKS reads the RSA public keys from the JWKS file configured in
+// jwtJwksFile. Keys are loaded once; a missing file simply disables RS256.
+func loadJWKS() map[string]*rsa.PublicKey {
+	jwksOnce.Do(func() {
+		jwksKeys = map[string]*rsa.PublicKey{}
+		if config.Config.JwtJwksFile == "" {
+			return
+		}
+		data, err := os.ReadFile(config.Config.JwtJwksFile)
+		if err != nil {
+			logrus.Errorf("failed to read jwks file: %v", err)
+			return
+		}
+		var keySet jsonWebKeySet
+		err = json.Unmarshal(data, &keySet)
+		if err != nil {
+			logrus.Errorf("failed to parse jwks file: %v", err)
+			return
+		}
+		for _, key := range keySet.Keys {
+			if key.Kty != "RSA" {
+				continue
+			}
+			publicKey, err := parseRSAPublicKey(key)
+			if err != nil {
+				logrus.Errorf("failed to parse jwk %s: %v", key.Kid, err)
+				continue
+			}
+			jwksKeys[key.Kid] = publicKey
+		}
+	})
+	return jwksKeys
+}
+
+func parseRSAPublicKey(key jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if config.Config.JwtSecretKey == "" {
+			return nil, errConstant.ErrInvalidTokenMethod
+		}
+		return []byte(config.Config.JwtSecretKey), nil
+	case jwt.SigningMethodRS256.Alg():
+		keys := loadJWKS()
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, errConstant.ErrInvalidToken
+	default:
+		return nil, errConstant.ErrInvalidTokenMethod
+	}
+}
+
+// parseToken verifies the signature and expiry of a bearer token and maps
+// the jwt library errors to the service's own error values.
+func parseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyFunc, jwt.WithExpirationRequired())
+	if err != nil {
+		switch {
+		case errors.Is(err, errConstant.ErrInvalidTokenMethod):
+			return nil, errConstant.ErrInvalidTokenMethod
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return nil, errConstant.ErrTokenExpired
+		case errors.Is(err, jwt.ErrTokenMalformed):
+			return nil, errConstant.ErrTokenMalformed
+		default:
+			return nil, errConstant.ErrInvalidToken
+		}
+	}
+	if !token.Valid || claims.User == nil {
+		return nil, errConstant.ErrInvalidToken
+	}
+	claims.User.ExpiresAt = claims.ExpiresAt.Time
+	return claims, nil
+}
@@ -2,22 +2,24 @@ package middlewares
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"crypto/subtle"
 	"field-service/clients"
+	"field-service/common/policy"
 	"field-service/common/response"
+	"field-service/common/util"
 	"field-service/config"
 	"field-service/constants"
 	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
 
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
-
-	"github.com/didip/tollbooth"
-	"github.com/didip/tollbooth/limiter"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -47,20 +49,6 @@ func HandlePanic() gin.HandlerFunc {
 	}
 }
 
-func RateLimiter(lmt *limiter.Limiter) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		err := tollbooth.LimitByRequest(lmt, c.Writer, c.Request)
-		if err != nil {
-			c.JSON(http.StatusTooManyRequests, response.Response{
-				Status:  constants.Error,
-				Message: errConstant.ErrTooManyRequests.Error(),
-			})
-			c.Abort()
-		}
-		c.Next()
-	}
-}
-
 func extractBearerToken(token string) string {
 	arrayToken := strings.Split(token, " ")
 	if len(arrayToken) == 2 {
@@ -76,75 +64,147 @@ func responseUnauthorized(c *gin.Context, message string) {
 	c.Abort()
 }
 
+const defaultInternalAuthWindow = 5 * time.Minute
+
+func internalAuthWindow() time.Duration {
+	if config.Config.InternalAuth.WindowSecond > 0 {
+		return time.Duration(config.Config.InternalAuth.WindowSecond) * time.Second
+	}
+	return defaultInternalAuthWindow
+}
+
+// validateApiKey checks that x-api-key is the SHA256 of
+// "<x-service-name>:<signature key>:<x-request-at>" using the key configured
+// for the calling service, that x-request-at is within the freshness window
+// and that the service has not sent the same x-api-key and x-request-at
+// before.
 func validateApiKey(c *gin.Context) error {
 	apiKey := c.GetHeader(constants.XApiKey)
 	requestAt := c.GetHeader(constants.XRequestAt)
 	serviceName := c.GetHeader(constants.XServiceName)
-	signatureKey := config.Config.SignatureKey
+	if apiKey == "" || requestAt == "" || serviceName == "" {
+		return errConstant.ErrUnauthorized
+	}
+
+	signatureKey, ok := config.Config.InternalAuth.SignatureKeys[strings.ToLower(serviceName)]
+	if !ok || signatureKey == "" {
+		return errConstant.ErrUnknownService
+	}
 
-	validateKey := fmt.Sprintf("%s:%s:%s", serviceName, signatureKey, requestAt)
-	hash := sha256.New()
-	hash.Write([]byte(validateKey))
-	resultHash := hex.EncodeToString(hash.Sum(nil))
+	unixTime, err := strconv.ParseInt(requestAt, 10, 64)
+	if err != nil {
+		return errConstant.ErrUnauthorized
+	}
+	window := internalAuthWindow()
+	requestTime := time.Unix(unixTime, 0)
+	if time.Since(requestTime).Abs() > window {
+		return errConstant.ErrRequestExpired
+	}
 
-	if apiKey != resultHash {
+	resultHash := util.GenerateInternalApiKey(serviceName, signatureKey, requestAt)
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(resultHash)) != 1 {
+		return errConstant.ErrUnauthorized
+	}
+	// The nonce is kept for as long as x-request-at may still be accepted:
+	// until the end of the window after it.
+	ttl := time.Until(requestTime.Add(window))
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	fresh, err := loadNonceStore().Add(c.Request.Context(), fmt.Sprintf("%s:%s:%s", strings.ToLower(serviceName), apiKey, requestAt), ttl)
+	if err != nil {
+		logrus.Errorf("failed to store request nonce: %v", err)
 		return errConstant.ErrUnauthorized
 	}
+	if !fresh {
+		return errConstant.ErrRequestReplayed
+	}
 	return nil
 }
 
-// func validateBearerToken(c *gin.Context, token string) error {
-// 	if !strings.Contains(token, "Bearer") {
-// 		fmt.Println("tes validateBearerToken kentoken ", token)
-// 		return errConstant.ErrUnauthorized
-// 	}
-// 	tokenString := extractBearerToken(token)
-// 	fmt.Println("tes validateBearerToken extractBearerToken ", tokenString)
-
-// 	if tokenString == "" {
-// 		return errConstant.ErrUnauthorized
-// 	}
-// 	claims := &userServices.Claims{}
-// 	fmt.Println("tes validateBearerToken claims ", claims)
-
-// 	tokenJwt, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-// 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
-// 		fmt.Println("tes validateBearerToken ok ", ok)
-
-// 		if !ok {
-// 			return nil, errConstant.ErrInvalidToken
-// 		}
-// 		jwtSecret := []byte(config.Config.JwtSecretKey)
-// 		fmt.Println("tes validateBearerToken jwtSecret ", jwtSecret)
-
-// 		return jwtSecret, nil
-// 	})
-// 	fmt.Println("tes validateBearerToken tokenJwt ", tokenJwt, "err: ", err)
-// 	fmt.Println("tes validateBearerToken tokenJwt ", tokenJwt.Valid, "err: ", err)
-
-// 	if err != nil || !tokenJwt.Valid {
-// 		return errConstant.ErrUnauthorized
-// 	}
-// 	userLogin := c.Request.WithContext(context.WithValue(c.Request.Context(), constants.UserLogin, claims.User))
-// 	c.Request = userLogin
-// 	c.Set(constants.Token, token)
-// 	return nil
-// }
-
-func contains(roles []string, role string) bool {
-	for _, r := range roles {
-		if r == role {
-			return true
+// RequestID tags the request with the incoming x-request-id, or a new one, and
+// keeps the client IP next to it so both can be recorded in the audit trail.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(constants.XRequestID)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		ctx := context.WithValue(c.Request.Context(), constants.RequestID, requestID)
+		ctx = context.WithValue(ctx, constants.ClientIP, c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(constants.RequestID, requestID)
+		c.Set(constants.ClientIP, c.ClientIP())
+		c.Header(constants.XRequestID, requestID)
+		c.Next()
+	}
+}
+
+// setTenant stores the tenant every repository query is scoped to. An empty
+// tenant replaces the one set before, so queries fail with ErrTenantRequired
+// instead of running against it.
+func setTenant(c *gin.Context, tenantID string) {
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), constants.TenantID, tenantID))
+	c.Set(constants.TenantID, tenantID)
+}
+
+// pinTenant scopes the request of an authenticated user to the user's tenant.
+// Only a global admin, an admin without a tenant, keeps the tenant picked by
+// x-tenant-id. Any other user without a tenant gets defaultTenantID, whatever
+// the header says.
+func pinTenant(c *gin.Context, user *dto.UserLogin) {
+	switch {
+	case user.TenantID != "":
+		setTenant(c, user.TenantID)
+	case user.Role == constants.Admin:
+		// A global admin works on the tenant resolved from x-tenant-id.
+	default:
+		setTenant(c, config.Config.DefaultTenantID)
+	}
+}
+
+// ResolveTenant picks the tenant from the x-tenant-id header, falling back to
+// defaultTenantID. Authenticated requests are pinned later by Authenticate and
+// CheckPermission through pinTenant.
+func ResolveTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetHeader(constants.XTenantID)
+		if tenantID == "" {
+			tenantID = config.Config.DefaultTenantID
 		}
+		setTenant(c, tenantID)
+		c.Next()
+	}
+}
+
+func validateBearerToken(c *gin.Context, token string) error {
+	if !strings.HasPrefix(token, "Bearer ") {
+		return errConstant.ErrTokenMalformed
+	}
+	tokenString := extractBearerToken(token)
+	if tokenString == "" {
+		return errConstant.ErrTokenMalformed
+	}
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return err
 	}
-	return false
+	userLogin := c.Request.WithContext(context.WithValue(c.Request.Context(), constants.UserLogin, claims.User))
+	c.Request = userLogin
+	c.Set(constants.UserLogin, claims.User)
+	c.Set(constants.Token, token)
+	pinTenant(c, claims.User)
+	return nil
 }
 
-func CheckRole(roles []string, clients clients.IClientRegistry) gin.HandlerFunc {
+// CheckPermission resolves the user behind the token and lets the request
+// through when the user's role holds the permission. When the role only holds
+// it for owned resources, the services enforce ownership via the scope stored
+// in the context.
+func CheckPermission(permission constants.Permission, clients clients.IClientRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("Authorization")
+		token := c.GetHeader(constants.Authorization)
 		if token == "" {
-			fmt.Println("tes CheckRole: missing Authorization header")
 			responseUnauthorized(c, errConstant.ErrUnauthorized.Error())
 			return
 		}
@@ -154,46 +214,69 @@ func CheckRole(roles []string, clients clients.IClientRegistry) gin.HandlerFunc
 
 		user, err := clients.GetUser().GetUserByToken(ctx)
 		if err != nil {
-			fmt.Println("tes CheckRole: error getting user:", err)
+			logrus.Errorf("failed to get user by token: %v", err)
 			responseUnauthorized(c, errConstant.ErrUnauthorized.Error())
 			return
 		}
 
-		if !contains(roles, user.Role) {
-			fmt.Println("tes CheckRole: ga ada role ", roles, " user.Role ", user.Role)
-			responseUnauthorized(c, errConstant.ErrUnauthorized.Error())
+		scope, ok := policy.Authorize(user.Role, permission)
+		if !ok {
+			c.JSON(http.StatusForbidden, response.Response{
+				Status:  constants.Error,
+				Message: errConstant.ErrForbidden.Error(),
+			})
+			c.Abort()
 			return
 		}
 
+		userLogin := &dto.UserLogin{
+			UUID:        user.UUID,
+			Name:        user.Name,
+			Username:    user.Username,
+			Email:       user.Email,
+			Role:        user.Role,
+			PhoneNumber: user.PhoneNumber,
+			TenantID:    user.TenantID,
+		}
+		if claims, ok := c.Request.Context().Value(constants.UserLogin).(*dto.UserLogin); ok && claims != nil {
+			userLogin.ExpiresAt = claims.ExpiresAt
+		}
+		ctx = context.WithValue(ctx, constants.UserLogin, userLogin)
+		ctx = context.WithValue(ctx, constants.AccessScope, scope)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(constants.UserLogin, userLogin)
+		c.Set(constants.AccessScope, scope)
+		pinTenant(c, userLogin)
 		c.Next()
 	}
 }
 
 func Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// var err error
 		token := c.GetHeader(constants.Authorization)
-		fmt.Println("tes Authenticate kentoken ", token)
 		if token == "" {
-			fmt.Println("tes Authenticate token koshong ", token)
 			responseUnauthorized(c, errConstant.ErrUnauthorized.Error())
 			return
 		}
-		// err = validateBearerToken(c, token)
-		// fmt.Println("tes Authenticate validateBearerToken ", err)
-
-		// if err != nil {
-		// 	responseUnauthorized(c, err.Error())
-		// 	return
-		// }
-
-		// err = validateApiKey(c)
-		// fmt.Println("tes Authenticate validateApiKey ", err)
+		err := validateBearerToken(c, token)
+		if err != nil {
+			responseUnauthorized(c, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
 
-		// if err != nil {
-		// 	responseUnauthorized(c, err.Error())
-		// 	return
-		// }
+func AuthenticateInternal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := validateApiKey(c)
+		if err != nil {
+			responseUnauthorized(c, err.Error())
+			return
+		}
+		serviceName := c.GetHeader(constants.XServiceName)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), constants.ServiceName, serviceName))
+		c.Set(constants.ServiceName, serviceName)
 		c.Next()
 	}
 }
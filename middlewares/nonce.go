@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"field-service/common/nonce"
+	"field-service/config"
+	"field-service/constants"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	nonceOnce  sync.Once
+	nonceStore nonce.IStore
+)
+
+// loadNonceStore builds the store selected in the internalAuth config once.
+// Redis is used when configured so that a request accepted by one replica
+// cannot be replayed against another. It falls back to the rateLimit Redis
+// when internalAuth does not name its own.
+func loadNonceStore() nonce.IStore {
+	nonceOnce.Do(func() {
+		cfg := config.Config.InternalAuth
+		redisConfig := cfg.Redis
+		if redisConfig.Address == "" {
+			redisConfig = config.Config.RateLimit.Redis
+		}
+		if cfg.Store == constants.RateLimitStoreRedis && redisConfig.Address != "" {
+			client := redis.NewClient(&redis.Options{
+				Addr:     redisConfig.Address,
+				Password: redisConfig.Password,
+				DB:       redisConfig.DB,
+			})
+			nonceStore = nonce.NewRedisStore(client, fmt.Sprintf("%s:nonce:", config.Config.AppName))
+			return
+		}
+		nonceStore = nonce.NewMemoryStore()
+	})
+	return nonceStore
+}
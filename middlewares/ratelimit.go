@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"field-service/common/ratelimit"
+	"field-service/common/response"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	rateLimitOnce  sync.Once
+	rateLimitStore ratelimit.IStore
+)
+
+// defaultRateLimitPolicies apply to the policies missing from the rateLimit
+// config. The global policy keeps the old rateLimiterMaxRequest and
+// rateLimiterTimeSecond settings.
+func defaultRateLimitPolicies() map[string]config.RateLimitPolicy {
+	globalWindow := config.Config.RateLimiterTimeSecond
+	if globalWindow <= 0 {
+		globalWindow = 1
+	}
+	return map[string]config.RateLimitPolicy{
+		constants.RateLimitGlobal: {
+			KeyBy:        constants.RateLimitByIP,
+			MaxRequest:   int(config.Config.RateLimiterMaxRequest),
+			WindowSecond: globalWindow,
+		},
+		constants.RateLimitPublic:        {KeyBy: constants.RateLimitByIP, MaxRequest: 60, WindowSecond: 60},
+		constants.RateLimitAuthenticated: {KeyBy: constants.RateLimitByUser, MaxRequest: 300, WindowSecond: 60},
+		constants.RateLimitInternal:      {KeyBy: constants.RateLimitByService, MaxRequest: 1000, WindowSecond: 60},
+	}
+}
+
+// loadRateLimitStore builds the store selected in config once. Redis is used
+// when configured so that every replica shares the same counters.
+func loadRateLimitStore() ratelimit.IStore {
+	rateLimitOnce.Do(func() {
+		cfg := config.Config.RateLimit
+		if cfg.Store == constants.RateLimitStoreRedis && cfg.Redis.Address != "" {
+			client := redis.NewClient(&redis.Options{
+				Addr:     cfg.Redis.Address,
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.DB,
+			})
+			rateLimitStore = ratelimit.NewRedisStore(client, fmt.Sprintf("%s:ratelimit:", config.Config.AppName))
+			return
+		}
+		rateLimitStore = ratelimit.NewMemoryStore()
+	})
+	return rateLimitStore
+}
+
+func rateLimitPolicy(name string) config.RateLimitPolicy {
+	if policy, ok := config.Config.RateLimit.Policies[name]; ok {
+		return policy
+	}
+	return defaultRateLimitPolicies()[name]
+}
+
+// rateLimitKey identifies the client the policy counts against. User and
+// service keys fall back to the client IP when the request carries neither.
+func rateLimitKey(c *gin.Context, keyBy string) string {
+	switch keyBy {
+	case constants.RateLimitByUser:
+		if user, ok := c.Request.Context().Value(constants.UserLogin).(*dto.UserLogin); ok && user != nil {
+			return fmt.Sprintf("user:%s", user.UUID)
+		}
+	case constants.RateLimitByService:
+		if serviceName := c.GetHeader(constants.XServiceName); serviceName != "" {
+			return fmt.Sprintf("service:%s", serviceName)
+		}
+	}
+	return fmt.Sprintf("ip:%s", c.ClientIP())
+}
+
+// RateLimiter applies the named policy from the rateLimit config and reports
+// the window state in RateLimit-* headers. Requests are let through when the
+// store is unavailable rather than failing the whole API.
+func RateLimiter(name string) gin.HandlerFunc {
+	policy := rateLimitPolicy(name)
+	window := time.Duration(policy.WindowSecond) * time.Second
+	return func(c *gin.Context) {
+		if policy.MaxRequest <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprintf("%s:%s", name, rateLimitKey(c, policy.KeyBy))
+		result, err := loadRateLimitStore().Take(c.Request.Context(), key, policy.MaxRequest, window)
+		if err != nil {
+			logrus.Errorf("failed to take rate limit for %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		resetSecond := strconv.Itoa(int(math.Ceil(result.ResetIn.Seconds())))
+		c.Header(constants.RateLimitLimit, strconv.Itoa(result.Limit))
+		c.Header(constants.RateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Header(constants.RateLimitReset, resetSecond)
+		c.Header(constants.RateLimitPolicy, fmt.Sprintf("%d;w=%d", policy.MaxRequest, policy.WindowSecond))
+		if !result.Allowed {
+			c.Header(constants.RetryAfter, resetSecond)
+			c.JSON(http.StatusTooManyRequests, response.Response{
+				Status:  constants.Error,
+				Message: errConstant.ErrTooManyRequests.Error(),
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTenantRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	config.Config.JwtSecretKey = "test-secret"
+	config.Config.DefaultTenantID = "default"
+
+	echoTenant := func(c *gin.Context) {
+		tenantID, _ := c.Request.Context().Value(constants.TenantID).(string)
+		c.String(http.StatusOK, tenantID)
+	}
+	router := gin.New()
+	router.Use(ResolveTenant())
+	router.GET("/public", echoTenant)
+	router.GET("/private", Authenticate(), echoTenant)
+	return router
+}
+
+func signToken(t *testing.T, user *dto.UserLogin) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		User: user,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := token.SignedString([]byte(config.Config.JwtSecretKey))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return "Bearer " + signed
+}
+
+func TestTenantResolution(t *testing.T) {
+	router := newTenantRouter(t)
+	tests := []struct {
+		name   string
+		path   string
+		user   *dto.UserLogin
+		header string
+		want   string
+	}{
+		{name: "public request uses the header", path: "/public", header: "tenant-b", want: "tenant-b"},
+		{name: "public request without header uses the default", path: "/public", want: "default"},
+		{
+			name:   "header cannot override the user's tenant",
+			path:   "/private",
+			user:   &dto.UserLogin{UUID: uuid.New(), Role: constants.Partner, TenantID: "tenant-a"},
+			header: "tenant-b",
+			want:   "tenant-a",
+		},
+		{
+			name:   "user without tenant gets the default, not the header",
+			path:   "/private",
+			user:   &dto.UserLogin{UUID: uuid.New(), Role: constants.Customer},
+			header: "tenant-b",
+			want:   "default",
+		},
+		{
+			name:   "global admin picks the tenant with the header",
+			path:   "/private",
+			user:   &dto.UserLogin{UUID: uuid.New(), Role: constants.Admin},
+			header: "tenant-b",
+			want:   "tenant-b",
+		},
+		{
+			name:   "tenant admin stays in its tenant",
+			path:   "/private",
+			user:   &dto.UserLogin{UUID: uuid.New(), Role: constants.Admin, TenantID: "tenant-a"},
+			header: "tenant-b",
+			want:   "tenant-a",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if test.header != "" {
+				request.Header.Set(constants.XTenantID, test.header)
+			}
+			if test.user != nil {
+				request.Header.Set(constants.Authorization, signToken(t, test.user))
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body.String())
+			}
+			if got := recorder.Body.String(); got != test.want {
+				t.Errorf("tenant = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 CREATE TABLE public.field (
     id bigint PRIMARY KEY,
     uuid text NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
     code VARCHAR(15) NOT NULL,
     name VARCHAR(100) NOT NULL,
     price_per_hour INT NOT NULL,
     images TEXT[] NOT NULL,
+    status INT NOT NULL DEFAULT 100,
+    parent_id INT REFERENCES public.field (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    owner_uuid UUID,
     created_at TIMESTAMPTZ,
     updated_at TIMESTAMPTZ,
     deleted_at TIMESTAMPTZ
@@ -22,6 +26,7 @@ CREATE TABLE public.field (
 CREATE TABLE public.time (
       id bigint PRIMARY KEY,
     uuid text NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
     start_time TIME WITHOUT TIME ZONE NOT NULL,
     end_time TIME WITHOUT TIME ZONE NOT NULL,
     created_at TIMESTAMPTZ,
@@ -31,11 +36,151 @@ CREATE TABLE public.time (
 CREATE TABLE public.field_schedule (
     id bigint PRIMARY KEY,
     uuid text NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
     field_id INT NOT NULL,
     time_id INT NOT NULL,
     date DATE NOT NULL,
     status INT NOT NULL,
+    order_id VARCHAR(100),
+    price INT NOT NULL DEFAULT 0,
     created_at TIMESTAMPTZ,
     updated_at TIMESTAMPTZ,
     deleted_at TIMESTAMPTZ
-)
\ No newline at end of file
+)
+CREATE TABLE public.maintenance_windows (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
+    field_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    start_time TIME WITHOUT TIME ZONE NOT NULL,
+    end_time TIME WITHOUT TIME ZONE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE public.audit_logs (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
+    actor_uuid uuid,
+    actor_name VARCHAR(100),
+    actor_role VARCHAR(20),
+    action VARCHAR(30) NOT NULL,
+    entity VARCHAR(30) NOT NULL,
+    entity_uuid uuid NOT NULL,
+    changes JSONB NOT NULL,
+    request_id VARCHAR(100),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMPTZ
+);
+
+CREATE OR REPLACE FUNCTION prevent_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON public.audit_logs
+FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_change();
+
+CREATE TABLE public.field_schedule_histories (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
+    field_schedule_id INT NOT NULL,
+    field_schedule_uuid uuid NOT NULL,
+    field_id INT NOT NULL,
+    time_id INT NOT NULL,
+    date DATE NOT NULL,
+    event VARCHAR(30) NOT NULL,
+    from_status INT,
+    status INT NOT NULL,
+    reference VARCHAR(100),
+    actor_uuid uuid,
+    actor_name VARCHAR(100),
+    actor_role VARCHAR(20),
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_field_schedule_histories_board ON public.field_schedule_histories (field_id, date, field_schedule_id, created_at);
+
+CREATE TABLE public.outbox_events (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL UNIQUE,
+    tenant_id VARCHAR(50) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT,
+    published_sinks TEXT[],
+    published_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_events_due ON public.outbox_events (status, next_attempt_at);
+
+CREATE TABLE public.inbox_events (
+    id bigint PRIMARY KEY,
+    event_id VARCHAR(100) NOT NULL UNIQUE,
+    tenant_id VARCHAR(50) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE public.payments (
+    id bigint PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL,
+    order_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    UNIQUE (tenant_id, order_id)
+);
+
+CREATE TABLE public.webhooks (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
+    owner_uuid uuid NOT NULL,
+    field_id INT REFERENCES public.field (id),
+    target_url VARCHAR(500) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE public.webhook_deliveries (
+    id bigint PRIMARY KEY,
+    uuid uuid NOT NULL,
+    tenant_id VARCHAR(50) NOT NULL,
+    webhook_id INT NOT NULL REFERENCES public.webhooks (id),
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    response_status INT,
+    response_body TEXT,
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON public.webhook_deliveries (status, next_attempt_at);
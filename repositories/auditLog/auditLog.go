@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// IAuditLogRepository only appends and reads; audit records are never changed.
+type IAuditLogRepository interface {
+	FindAllWithPagination(context.Context, *dto.AuditLogRequestParam) ([]models.AuditLog, int64, error)
+	Create(context.Context, *gorm.DB, []models.AuditLog) error
+}
+
+func NewAuditLogRepository(db *gorm.DB) IAuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (a *AuditLogRepository) filter(param *dto.AuditLogRequestParam) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if param.Entity != nil && *param.Entity != "" {
+			db = db.Where("entity = ?", *param.Entity)
+		}
+		if param.EntityID != nil && *param.EntityID != "" {
+			db = db.Where("entity_uuid = ?", *param.EntityID)
+		}
+		if param.ActorID != nil && *param.ActorID != "" {
+			db = db.Where("actor_uuid = ?", *param.ActorID)
+		}
+		if param.Action != nil && *param.Action != "" {
+			db = db.Where("action = ?", *param.Action)
+		}
+		if param.From != nil && *param.From != "" {
+			db = db.Where("created_at >= ?", *param.From)
+		}
+		if param.To != nil && *param.To != "" {
+			db = db.Where("created_at <= ?", *param.To)
+		}
+		return db
+	}
+}
+
+func (a *AuditLogRepository) FindAllWithPagination(ctx context.Context, param *dto.AuditLogRequestParam) ([]models.AuditLog, int64, error) {
+	var (
+		auditLogs []models.AuditLog
+		total     int64
+	)
+	limit := param.Limit
+	offset := (param.Page - 1) * limit
+	err := a.db.WithContext(ctx).
+		Scopes(a.filter(param)).
+		Limit(limit).
+		Offset(offset).
+		Order("created_at desc, id desc").
+		Find(&auditLogs).Error
+	if err != nil {
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+
+	err = a.db.WithContext(ctx).Model(&models.AuditLog{}).Scopes(a.filter(param)).Count(&total).Error
+	if err != nil {
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return auditLogs, total, nil
+}
+
+// Create writes the audit records in the caller's transaction.
+func (a *AuditLogRepository) Create(ctx context.Context, tx *gorm.DB, auditLogs []models.AuditLog) error {
+	if len(auditLogs) == 0 {
+		return nil
+	}
+	err := tx.WithContext(ctx).CreateInBatches(&auditLogs, 100).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
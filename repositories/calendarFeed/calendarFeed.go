@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	errCalendar "field-service/constants/error/calendar"
+	"field-service/domain/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+type ICalendarFeedRepository interface {
+	FindAll(context.Context, *uuid.UUID) ([]models.CalendarFeed, error)
+	FindByUUID(context.Context, string) (*models.CalendarFeed, error)
+	FindByToken(context.Context, string) (*models.CalendarFeed, error)
+	Create(context.Context, *models.CalendarFeed) (*models.CalendarFeed, error)
+	Delete(context.Context, string) error
+}
+
+func NewCalendarFeedRepository(db *gorm.DB) ICalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// FindAll returns the feeds of the owner, or of everyone when ownerUUID is
+// nil.
+func (c *CalendarFeedRepository) FindAll(ctx context.Context, ownerUUID *uuid.UUID) ([]models.CalendarFeed, error) {
+	var feeds []models.CalendarFeed
+	query := c.db.WithContext(ctx).Preload("Field")
+	if ownerUUID != nil {
+		query = query.Where("owner_uuid = ?", *ownerUUID)
+	}
+	err := query.Order("created_at desc").Find(&feeds).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return feeds, nil
+}
+
+func (c *CalendarFeedRepository) FindByUUID(ctx context.Context, uuid string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := c.db.WithContext(ctx).
+		Preload("Field").
+		Where("uuid = ?", uuid).
+		First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errCalendar.ErrCalendarFeedNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &feed, nil
+}
+
+// FindByToken returns the feed the token grants access to. Feed requests
+// carry no tenant, so the token is looked up across tenants and the feed's
+// own tenant is used from there on.
+func (c *CalendarFeedRepository) FindByToken(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := c.db.WithContext(ctx).
+		Preload("Field").
+		Where("token = ?", token).
+		First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errCalendar.ErrCalendarFeedNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &feed, nil
+}
+
+func (c *CalendarFeedRepository) Create(ctx context.Context, req *models.CalendarFeed) (*models.CalendarFeed, error) {
+	req.UUID = uuid.New()
+	err := c.db.WithContext(ctx).Create(req).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
+
+func (c *CalendarFeedRepository) Delete(ctx context.Context, uuid string) error {
+	err := c.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&models.CalendarFeed{}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errExport "field-service/constants/error/export"
+	"field-service/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExportJobRepository struct {
+	db *gorm.DB
+}
+
+type IExportJobRepository interface {
+	FindByUUID(context.Context, string) (*models.ExportJob, error)
+	FindNext(context.Context, *gorm.DB, time.Time) (*models.ExportJob, error)
+	Create(context.Context, *models.ExportJob) (*models.ExportJob, error)
+	Update(context.Context, *gorm.DB, *models.ExportJob) error
+}
+
+func NewExportJobRepository(db *gorm.DB) IExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+func (e *ExportJobRepository) FindByUUID(ctx context.Context, uuid string) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := e.db.WithContext(ctx).Where("uuid = ?", uuid).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errExport.ErrExportJobNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &job, nil
+}
+
+// FindNext locks and returns the oldest pending job, or a running one whose
+// worker has not finished it since staleBefore and is presumed gone. It
+// returns nil when there is none.
+func (e *ExportJobRepository) FindNext(ctx context.Context, tx *gorm.DB, staleBefore time.Time) (*models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := tx.WithContext(ctx).
+		Where("status = ? OR (status = ? AND started_at < ?)", constants.ExportJobPending, constants.ExportJobRunning, staleBefore).
+		Order("id asc").
+		Limit(1).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+func (e *ExportJobRepository) Create(ctx context.Context, req *models.ExportJob) (*models.ExportJob, error) {
+	req.UUID = uuid.New()
+	req.Status = constants.ExportJobPending
+	err := e.db.WithContext(ctx).Create(req).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
+
+func (e *ExportJobRepository) Update(ctx context.Context, tx *gorm.DB, job *models.ExportJob) error {
+	err := tx.WithContext(ctx).
+		Model(&models.ExportJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":      job.Status,
+			"row_count":   job.RowCount,
+			"object_name": job.ObjectName,
+			"last_error":  job.LastError,
+			"started_at":  job.StartedAt,
+			"finished_at": job.FinishedAt,
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
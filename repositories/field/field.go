@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	errWrap "field-service/common/error"
+	"field-service/common/util"
+	"field-service/constants"
 	errConstant "field-service/constants/error"
 	errField "field-service/constants/error/field"
 	"field-service/domain/dto"
 	"field-service/domain/models"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -21,38 +24,78 @@ type FieldRepository struct {
 type IFieldRepository interface {
 	FindAllWithPagination(context.Context, *dto.FieldRequestParam) ([]models.Field, int64, error)
 	FindAllWithoutPagination(context.Context) ([]models.Field, error)
+	FindAllByStatus(context.Context, constants.FieldStatus) ([]models.Field, error)
+	FindAllDeleted(context.Context) ([]models.Field, error)
 	FindByUUID(context.Context, string) (*models.Field, error)
-	Create(context.Context, *models.Field) (*models.Field, error)
-	Update(context.Context, string, *models.Field) (*models.Field, error)
-	Delete(context.Context, string) error
+	Create(context.Context, *gorm.DB, *models.Field) (*models.Field, error)
+	Upsert(context.Context, *gorm.DB, *models.Field) (*models.Field, error)
+	Update(context.Context, *gorm.DB, string, *models.Field) (*models.Field, error)
+	UpdateStatus(context.Context, *gorm.DB, string, constants.FieldStatus) error
+	UpdateParent(context.Context, *gorm.DB, string, *uint) error
+	CountByParentID(context.Context, int) (int64, error)
+	Delete(context.Context, *gorm.DB, string) error
+	Restore(context.Context, *gorm.DB, string) (*models.Field, error)
 }
 
 func NewFieldRepository(db *gorm.DB) IFieldRepository {
 	return &FieldRepository{db: db}
 }
 
+var fieldSortColumns = map[string]string{
+	"name":         "name",
+	"code":         "code",
+	"pricePerHour": "price_per_hour",
+	"createdAt":    "created_at",
+	"updatedAt":    "updated_at",
+}
+
+// likeEscaper makes a search term match literally in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (f *FieldRepository) filter(param *dto.FieldRequestParam) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if param.Search != nil && *param.Search != "" {
+			search := fmt.Sprintf("%%%s%%", likeEscaper.Replace(*param.Search))
+			db = db.Where(`name ILIKE ? ESCAPE '\' OR code ILIKE ? ESCAPE '\'`, search, search)
+		}
+		if param.MinPrice != nil {
+			db = db.Where("price_per_hour >= ?", *param.MinPrice)
+		}
+		if param.MaxPrice != nil {
+			db = db.Where("price_per_hour <= ?", *param.MaxPrice)
+		}
+		if param.Status != nil && *param.Status != "" {
+			db = db.Where("status = ?", constants.FieldStatusName(*param.Status).GetStatusInt())
+		}
+		return db
+	}
+}
+
 func (f *FieldRepository) FindAllWithPagination(ctx context.Context, param *dto.FieldRequestParam) ([]models.Field, int64, error) {
 	var (
 		fields []models.Field
-		sort   string
 		total  int64
 	)
-	if param.SortColumn != nil {
-		sort = fmt.Sprintf("%s %s", *param.SortColumn, *param.SortOrder)
-	} else {
-		sort = "created_at desc"
+	sort, err := util.GenerateSortQuery(fieldSortColumns, param.SortColumn, param.SortOrder, "created_at desc")
+	if err != nil {
+		return nil, 0, errWrap.WrapError(err)
 	}
 
 	limit := param.Limit
 	offset := (param.Page - 1) * limit
 
-	err := f.db.WithContext(ctx).Limit(limit).Offset(offset).Order(sort).Find(&fields).Error
-
+	err = f.db.WithContext(ctx).
+		Preload("Parent").
+		Scopes(f.filter(param)).
+		Limit(limit).
+		Offset(offset).
+		Order(sort).
+		Find(&fields).Error
 	if err != nil {
 		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
 	}
 
-	err = f.db.WithContext(ctx).Model(&fields).Count(&total).Error
+	err = f.db.WithContext(ctx).Model(&models.Field{}).Scopes(f.filter(param)).Count(&total).Error
 	if err != nil {
 		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
 	}
@@ -68,9 +111,31 @@ func (f *FieldRepository) FindAllWithoutPagination(ctx context.Context) ([]model
 	return field, nil
 }
 
+func (f *FieldRepository) FindAllByStatus(ctx context.Context, status constants.FieldStatus) ([]models.Field, error) {
+	var fields []models.Field
+	err := f.db.WithContext(ctx).Preload("Parent").Where("status = ?", status).Find(&fields).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fields, nil
+}
+
+func (f *FieldRepository) FindAllDeleted(ctx context.Context) ([]models.Field, error) {
+	var fields []models.Field
+	err := f.db.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at desc").
+		Find(&fields).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fields, nil
+}
+
 func (f *FieldRepository) FindByUUID(ctx context.Context, uuid string) (*models.Field, error) {
 	var field models.Field
-	err := f.db.WithContext(ctx).Where("uuid = ?", uuid).First(&field).Error
+	err := f.db.WithContext(ctx).Preload("Parent").Where("uuid = ?", uuid).First(&field).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("%w: %v", errWrap.WrapError(errField.ErrFieldNotFound), err)
@@ -80,15 +145,18 @@ func (f *FieldRepository) FindByUUID(ctx context.Context, uuid string) (*models.
 	return &field, nil
 }
 
-func (f *FieldRepository) Create(ctx context.Context, req *models.Field) (*models.Field, error) {
+func (f *FieldRepository) Create(ctx context.Context, tx *gorm.DB, req *models.Field) (*models.Field, error) {
 	field := models.Field{
 		UUID:         uuid.New(),
 		Code:         req.Code,
 		Name:         req.Name,
 		PricePerHour: req.PricePerHour,
+		Status:       constants.FieldActive,
+		ParentID:     req.ParentID,
+		OwnerUUID:    req.OwnerUUID,
 		// Images:       req.Images,
 	}
-	err := f.db.WithContext(ctx).Create(&field).Error
+	err := tx.WithContext(ctx).Create(&field).Error
 
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errWrap.WrapError(errConstant.ErrSQLError), err)
@@ -96,24 +164,106 @@ func (f *FieldRepository) Create(ctx context.Context, req *models.Field) (*model
 	return &field, nil
 }
 
-func (f *FieldRepository) Update(ctx context.Context, uuid string, req *models.Field) (*models.Field, error) {
+// Upsert creates the field when it has no ID yet and otherwise overwrites its
+// code, name, price, parent and owner, in the caller's transaction.
+func (f *FieldRepository) Upsert(ctx context.Context, tx *gorm.DB, req *models.Field) (*models.Field, error) {
+	if req.ID == 0 {
+		req.UUID = uuid.New()
+		req.Status = constants.FieldActive
+		err := tx.WithContext(ctx).Create(req).Error
+		if err != nil {
+			return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		}
+		return req, nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.Field{}).
+		Where("id = ?", req.ID).
+		Updates(map[string]interface{}{
+			"code":           req.Code,
+			"name":           req.Name,
+			"price_per_hour": req.PricePerHour,
+			"parent_id":      req.ParentID,
+			"owner_uuid":     req.OwnerUUID,
+		}).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
+
+func (f *FieldRepository) Update(ctx context.Context, tx *gorm.DB, uuid string, req *models.Field) (*models.Field, error) {
 	field := models.Field{
 		Code:         req.Code,
 		Name:         req.Name,
 		PricePerHour: req.PricePerHour,
 		Images:       req.Images,
 	}
-	err := f.db.WithContext(ctx).Where("uuid=?", uuid).Updates(&field).Error
+	err := tx.WithContext(ctx).Where("uuid=?", uuid).Updates(&field).Error
 	if err != nil {
 		return nil, errWrap.WrapError(errConstant.ErrSQLError)
 	}
 	return &field, nil
 }
 
-func (f *FieldRepository) Delete(ctx context.Context, uuid string) error {
-	err := f.db.WithContext(ctx).Where("uuid=?", uuid).Delete(&models.Field{}).Error
+func (f *FieldRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, uuid string, status constants.FieldStatus) error {
+	err := tx.WithContext(ctx).
+		Model(&models.Field{}).
+		Where("uuid = ?", uuid).
+		Update("status", status).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (f *FieldRepository) UpdateParent(ctx context.Context, tx *gorm.DB, uuid string, parentID *uint) error {
+	err := tx.WithContext(ctx).
+		Model(&models.Field{}).
+		Where("uuid = ?", uuid).
+		Update("parent_id", parentID).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (f *FieldRepository) CountByParentID(ctx context.Context, parentID int) (int64, error) {
+	var total int64
+	err := f.db.WithContext(ctx).Model(&models.Field{}).Where("parent_id = ?", parentID).Count(&total).Error
+	if err != nil {
+		return 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return total, nil
+}
+
+func (f *FieldRepository) Delete(ctx context.Context, tx *gorm.DB, uuid string) error {
+	err := tx.WithContext(ctx).Where("uuid=?", uuid).Delete(&models.Field{}).Error
 	if err != nil {
 		return errWrap.WrapError(errConstant.ErrSQLError)
 	}
 	return err
 }
+
+// Restore undeletes the field in the caller's transaction and returns it as
+// restored.
+func (f *FieldRepository) Restore(ctx context.Context, tx *gorm.DB, uuid string) (*models.Field, error) {
+	result := tx.WithContext(ctx).
+		Unscoped().
+		Model(&models.Field{}).
+		Where("uuid = ?", uuid).
+		Where("deleted_at IS NOT NULL").
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errWrap.WrapError(errField.ErrDeletedFieldNotFound)
+	}
+	var field models.Field
+	err := tx.WithContext(ctx).Preload("Parent").Where("uuid = ?", uuid).First(&field).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &field, nil
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"field-service/common/tenant"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestRepository(t *testing.T) (IFieldRepository, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	if err = db.AutoMigrate(&models.Field{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewFieldRepository(db), db
+}
+
+func createField(t *testing.T, db *gorm.DB, tenantID, code string) *models.Field {
+	t.Helper()
+	field := &models.Field{
+		UUID:         uuid.New(),
+		Code:         code,
+		Name:         "Field " + code,
+		PricePerHour: 100000,
+		Images:       []string{},
+	}
+	err := db.WithContext(tenant.WithTenant(context.Background(), tenantID)).Create(field).Error
+	if err != nil {
+		t.Fatalf("failed to create field: %v", err)
+	}
+	return field
+}
+
+func TestFieldRepositoryTenantIsolation(t *testing.T) {
+	repository, db := newTestRepository(t)
+	tenantA := tenant.WithTenant(context.Background(), "tenant-a")
+	tenantB := tenant.WithTenant(context.Background(), "tenant-b")
+	fieldA := createField(t, db, "tenant-a", "A1")
+	fieldB := createField(t, db, "tenant-b", "B1")
+
+	if fieldA.TenantID != "tenant-a" || fieldB.TenantID != "tenant-b" {
+		t.Fatalf("tenants = %q, %q, want them taken from the context", fieldA.TenantID, fieldB.TenantID)
+	}
+
+	t.Run("read", func(t *testing.T) {
+		if _, err := repository.FindByUUID(tenantA, fieldB.UUID.String()); err == nil {
+			t.Error("tenant A found tenant B's field")
+		}
+		fields, err := repository.FindAllWithoutPagination(tenantA)
+		if err != nil {
+			t.Fatalf("failed to list fields: %v", err)
+		}
+		if len(fields) != 1 || fields[0].UUID != fieldA.UUID {
+			t.Errorf("tenant A listed %d fields, want only its own", len(fields))
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		_, err := repository.Update(tenantA, db, fieldB.UUID.String(), &models.Field{Code: "X1", Name: "Taken"})
+		if err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+		field, err := repository.FindByUUID(tenantB, fieldB.UUID.String())
+		if err != nil {
+			t.Fatalf("failed to find field: %v", err)
+		}
+		if field.Name != fieldB.Name {
+			t.Errorf("tenant A renamed tenant B's field to %q", field.Name)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := repository.Delete(tenantA, db, fieldB.UUID.String()); err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+		if _, err := repository.FindByUUID(tenantB, fieldB.UUID.String()); err != nil {
+			t.Errorf("tenant A deleted tenant B's field: %v", err)
+		}
+	})
+
+	t.Run("missing tenant", func(t *testing.T) {
+		_, err := repository.FindAllWithoutPagination(context.Background())
+		if err == nil {
+			t.Error("query without a tenant succeeded")
+		}
+		var count int64
+		err = db.WithContext(context.Background()).Model(&models.Field{}).Count(&count).Error
+		if !errors.Is(err, errConstant.ErrTenantRequired) {
+			t.Errorf("err = %v, want ErrTenantRequired", err)
+		}
+	})
+}
+
+func TestFieldSearchMatchesLiterally(t *testing.T) {
+	_, db := newTestRepository(t)
+	repository := &FieldRepository{db: db}
+	search := `50%_off\`
+	var fields []models.Field
+	statement := db.Session(&gorm.Session{DryRun: true}).
+		WithContext(tenant.WithTenant(context.Background(), "tenant-a")).
+		Scopes(repository.filter(&dto.FieldRequestParam{Search: &search})).
+		Find(&fields).Statement
+	if !strings.Contains(statement.SQL.String(), `ILIKE ? ESCAPE '\'`) {
+		t.Errorf("search clause %q has no escape character", statement.SQL.String())
+	}
+	want := `%50\%\_off\\%`
+	for _, value := range statement.Vars[:2] {
+		if value != want {
+			t.Errorf("search pattern = %v, want %s", value, want)
+		}
+	}
+}
@@ -2,16 +2,23 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	errWrap "field-service/common/error"
+	"field-service/common/policy"
+	"field-service/common/util"
 	"field-service/constants"
 	errConstant "field-service/constants/error"
 	errFieldSchedule "field-service/constants/error/fieldSchedule"
+	errTime "field-service/constants/error/time"
 	"field-service/domain/dto"
 	"field-service/domain/models"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FieldScheduleRepository struct {
@@ -20,37 +27,76 @@ type FieldScheduleRepository struct {
 
 type IFieldScheduleRepository interface {
 	FindAllWithPagination(context.Context, *dto.FieldScheduleRequestParam) ([]models.FieldSchedule, int64, error)
+	FindAllWithCursor(context.Context, *dto.FieldScheduleCursorRequestParam, *dto.FieldScheduleCursor) ([]models.FieldSchedule, error)
+	CountWithFilter(context.Context, *dto.FieldScheduleFilterParam) (int64, error)
 	FindAllByFieldIDAndDate(context.Context, int, string) ([]models.FieldSchedule, error)
 	FindByUUID(context.Context, string) (*models.FieldSchedule, error)
+	ExistsUpcomingBookedByFieldID(context.Context, int) (bool, error)
 	FindByDateAndTimeID(context.Context, string, int, int) (*models.FieldSchedule, error)
-	Create(context.Context, []models.FieldSchedule) error
-	Update(context.Context, string, *models.FieldSchedule) (*models.FieldSchedule, error)
-	UpdateStatus(context.Context, constants.FieldScheduleStatus, string) error
-	Delete(context.Context, string) error
+	FindAllByUUIDs(context.Context, *gorm.DB, []string) ([]models.FieldSchedule, error)
+	FindAllBookedByOrderID(context.Context, *gorm.DB, string) ([]models.FieldSchedule, error)
+	FindAllBookedStartedBefore(context.Context, time.Time, int) ([]models.FieldSchedule, error)
+	CountNoShowByCustomerUUID(context.Context, string) (*dto.CustomerNoShowResponse, error)
+	FindAllByFieldIDAndStatuses(context.Context, uint, []constants.FieldScheduleStatus, string, string) ([]models.FieldSchedule, error)
+	FindAllBookedByCustomerUUID(context.Context, uuid.UUID, string, string) ([]models.FieldSchedule, error)
+	FindAllByFieldIDsBetween(context.Context, []uint, string, string) ([]models.FieldSchedule, error)
+	CountForExport(context.Context, *dto.FieldScheduleExportFilter, *uuid.UUID) (int64, error)
+	StreamForExport(context.Context, *dto.FieldScheduleExportFilter, *uuid.UUID, func(*dto.FieldScheduleExportRow) error) error
+	CreateWithTx(context.Context, *gorm.DB, []models.FieldSchedule) error
+	Update(context.Context, *gorm.DB, string, *models.FieldSchedule) (*models.FieldSchedule, error)
+	UpdateStatus(context.Context, *gorm.DB, constants.FieldScheduleStatus, constants.FieldScheduleStatus, string, string) error
+	FindAllOverlappingMaintenance(context.Context, *gorm.DB, *models.MaintenanceWindow) ([]models.FieldSchedule, error)
+	UpdateStatusByIDs(context.Context, *gorm.DB, []uint, constants.FieldScheduleStatus, string) error
+	ReleaseMaintenance(context.Context, *gorm.DB, *models.MaintenanceWindow) error
+	Delete(context.Context, *gorm.DB, string) error
 }
 
 func NewFieldScheduleRepository(db *gorm.DB) IFieldScheduleRepository {
 	return &FieldScheduleRepository{db: db}
 }
 
+var fieldScheduleSortColumns = map[string]string{
+	"date":      "date",
+	"status":    "status",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+func (f *FieldScheduleRepository) filter(param *dto.FieldScheduleFilterParam) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if param.FieldID != nil && *param.FieldID != "" {
+			db = db.Where("field_schedules.field_id IN (?)", f.db.WithContext(db.Statement.Context).Model(&models.Field{}).Select("id").Where("uuid = ?", *param.FieldID))
+		}
+		if param.StartDate != nil && *param.StartDate != "" {
+			db = db.Where("field_schedules.date >= ?", *param.StartDate)
+		}
+		if param.EndDate != nil && *param.EndDate != "" {
+			db = db.Where("field_schedules.date <= ?", *param.EndDate)
+		}
+		if param.Status != nil && *param.Status != "" {
+			db = db.Where("field_schedules.status = ?", constants.FieldScheduleStatusName(*param.Status).GetStatusInt())
+		}
+		return db
+	}
+}
+
 func (f *FieldScheduleRepository) FindAllWithPagination(ctx context.Context, param *dto.FieldScheduleRequestParam) ([]models.FieldSchedule, int64, error) {
 	var (
 		fieldSchedules []models.FieldSchedule
-		sort           string
 		total          int64
 	)
-	if param.SortColumn != nil {
-		sort = fmt.Sprintf("%s %s", *param.SortColumn, *param.SortOrder)
-	} else {
-		sort = "created_at desc"
+	sort, err := util.GenerateSortQuery(fieldScheduleSortColumns, param.SortColumn, param.SortOrder, "created_at desc")
+	if err != nil {
+		return nil, 0, errWrap.WrapError(err)
 	}
 
 	limit := param.Limit
 	offset := (param.Page - 1) * limit
 
-	err := f.db.WithContext(ctx).
+	err = f.db.WithContext(ctx).
 		Preload("Field").
 		Preload("Time").
+		Scopes(f.filter(&param.FieldScheduleFilterParam)).
 		Limit(limit).
 		Offset(offset).
 		Order(sort).
@@ -60,13 +106,55 @@ func (f *FieldScheduleRepository) FindAllWithPagination(ctx context.Context, par
 		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
 	}
 
-	err = f.db.WithContext(ctx).Model(&fieldSchedules).Count(&total).Error
+	err = f.db.WithContext(ctx).Model(&models.FieldSchedule{}).Scopes(f.filter(&param.FieldScheduleFilterParam)).Count(&total).Error
 	if err != nil {
 		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
 	}
 	return fieldSchedules, total, nil
 }
 
+// FindAllWithCursor returns up to limit+1 schedules after (or before, when the
+// cursor points backward) the given keyset position, in query order. The extra
+// row tells the caller whether another page exists.
+func (f *FieldScheduleRepository) FindAllWithCursor(
+	ctx context.Context,
+	param *dto.FieldScheduleCursorRequestParam,
+	cursor *dto.FieldScheduleCursor,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	order := "field_schedules.date asc, times.start_time asc, field_schedules.id asc"
+	query := f.db.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Joins("JOIN times ON field_schedules.time_id = times.id").
+		Scopes(f.filter(&param.FieldScheduleFilterParam))
+	if cursor != nil {
+		comparator := ">"
+		if cursor.Backward {
+			comparator = "<"
+			order = "field_schedules.date desc, times.start_time desc, field_schedules.id desc"
+		}
+		query = query.Where(
+			fmt.Sprintf("(field_schedules.date, times.start_time, field_schedules.id) %s (?, ?, ?)", comparator),
+			cursor.Date, cursor.StartTime, cursor.ID,
+		)
+	}
+	err := query.Order(order).Limit(param.Limit + 1).Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+func (f *FieldScheduleRepository) CountWithFilter(ctx context.Context, param *dto.FieldScheduleFilterParam) (int64, error) {
+	var total int64
+	err := f.db.WithContext(ctx).Model(&models.FieldSchedule{}).Scopes(f.filter(param)).Count(&total).Error
+	if err != nil {
+		return 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return total, nil
+}
+
 func (f *FieldScheduleRepository) FindAllByFieldIDAndDate(ctx context.Context, fieldID int, date string) ([]models.FieldSchedule, error) {
 	var fieldSchedule []models.FieldSchedule
 	err := f.db.WithContext(ctx).
@@ -96,6 +184,20 @@ func (f *FieldScheduleRepository) FindByUUID(ctx context.Context, uuid string) (
 	return &fieldSchedule, nil
 }
 
+func (f *FieldScheduleRepository) ExistsUpcomingBookedByFieldID(ctx context.Context, fieldID int) (bool, error) {
+	var total int64
+	err := f.db.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Where("field_id = ?", fieldID).
+		Where("status = ?", constants.Booked).
+		Where("date >= ?", time.Now().Format(time.DateOnly)).
+		Count(&total).Error
+	if err != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return total > 0, nil
+}
+
 func (f *FieldScheduleRepository) FindByDateAndTimeID(ctx context.Context, date string, timeID int, fieldID int) (*models.FieldSchedule, error) {
 	var fieldSchedule models.FieldSchedule
 	err := f.db.WithContext(ctx).
@@ -112,54 +214,855 @@ func (f *FieldScheduleRepository) FindByDateAndTimeID(ctx context.Context, date
 	return &fieldSchedule, nil
 }
 
-func (f *FieldScheduleRepository) Create(ctx context.Context, req []models.FieldSchedule) error {
-	err := f.db.WithContext(ctx).Create(&req).Error
+// FindAllByUUIDs locks and returns the schedules with the given UUIDs.
+func (f *FieldScheduleRepository) FindAllByUUIDs(ctx context.Context, tx *gorm.DB, uuids []string) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := tx.WithContext(ctx).
+		Where("uuid IN ?", uuids).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// FindAllBookedByOrderID locks and returns the schedules the order holds.
+func (f *FieldScheduleRepository) FindAllBookedByOrderID(ctx context.Context, tx *gorm.DB, orderID string) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := tx.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Where("status = ?", constants.Booked).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// FindAllBookedStartedBefore returns up to limit schedules still Booked that
+// started before the given time, compared in local time like their date and
+// time.
+func (f *FieldScheduleRepository) FindAllBookedStartedBefore(ctx context.Context, before time.Time, limit int) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := f.db.WithContext(ctx).
+		Select("field_schedules.*").
+		Joins("JOIN times ON field_schedules.time_id = times.id").
+		Where("field_schedules.status = ?", constants.Booked).
+		Where("field_schedules.date + times.start_time <= ?", before.In(time.Local).Format(time.DateTime)).
+		Order("field_schedules.id asc").
+		Limit(limit).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// CountNoShowByCustomerUUID counts the bookings of the customer's orders that
+// ended up NoShow.
+func (f *FieldScheduleRepository) CountNoShowByCustomerUUID(ctx context.Context, customerUUID string) (*dto.CustomerNoShowResponse, error) {
+	var result struct {
+		Total    int64
+		LastDate *time.Time
+	}
+	err := f.db.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Select("COUNT(*) AS total, MAX(field_schedules.date) AS last_date").
+		Joins("JOIN order_customers ON order_customers.tenant_id = field_schedules.tenant_id AND order_customers.order_id = field_schedules.order_id").
+		Where("order_customers.customer_uuid = ?", customerUUID).
+		Where("field_schedules.status = ?", constants.NoShow).
+		Scan(&result).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	response := &dto.CustomerNoShowResponse{NoShowCount: result.Total}
+	if result.LastDate != nil {
+		lastDate := result.LastDate.Format(time.DateOnly)
+		response.LastNoShowDate = &lastDate
+	}
+	return response, nil
+}
+
+// FindAllByFieldIDAndStatuses returns the field's schedules in the statuses
+// dated between from and until, inclusive, in date and time order.
+func (f *FieldScheduleRepository) FindAllByFieldIDAndStatuses(
+	ctx context.Context,
+	fieldID uint,
+	statuses []constants.FieldScheduleStatus,
+	from string,
+	until string,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := f.db.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Joins("JOIN times ON field_schedules.time_id = times.id").
+		Where("field_schedules.field_id = ?", fieldID).
+		Where("field_schedules.status IN ?", statuses).
+		Where("field_schedules.date BETWEEN ? AND ?", from, until).
+		Order("field_schedules.date asc, times.start_time asc").
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// FindAllBookedByCustomerUUID returns the schedules booked by the customer's
+// orders dated between from and until, inclusive, in date and time order.
+func (f *FieldScheduleRepository) FindAllBookedByCustomerUUID(
+	ctx context.Context,
+	customerUUID uuid.UUID,
+	from string,
+	until string,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := f.db.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Joins("JOIN times ON field_schedules.time_id = times.id").
+		Joins("JOIN order_customers ON order_customers.tenant_id = field_schedules.tenant_id AND order_customers.order_id = field_schedules.order_id").
+		Where("order_customers.customer_uuid = ?", customerUUID).
+		Where("field_schedules.status IN ?", constants.BookingStatuses).
+		Where("field_schedules.date BETWEEN ? AND ?", from, until).
+		Order("field_schedules.date asc, times.start_time asc").
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// FindAllByFieldIDsBetween returns the schedules of the fields dated between
+// from and until, inclusive.
+func (f *FieldScheduleRepository) FindAllByFieldIDsBetween(
+	ctx context.Context,
+	fieldIDs []uint,
+	from string,
+	until string,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := f.db.WithContext(ctx).
+		Where("field_id IN ?", fieldIDs).
+		Where("date BETWEEN ? AND ?", from, until).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// exportFilter narrows an export like filter narrows a page, and to the
+// fields of ownerUUID when it is set.
+func (f *FieldScheduleRepository) exportFilter(param *dto.FieldScheduleExportFilter, ownerUUID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(f.filter(&param.FieldScheduleFilterParam))
+		if param.BookedOnly {
+			db = db.Where("field_schedules.status IN ?", constants.BookingStatuses)
+		}
+		if ownerUUID != nil {
+			db = db.Where("field_schedules.field_id IN (?)", f.db.WithContext(db.Statement.Context).Model(&models.Field{}).Select("id").Where("owner_uuid = ?", *ownerUUID))
+		}
+		return db
+	}
+}
+
+func (f *FieldScheduleRepository) CountForExport(ctx context.Context, param *dto.FieldScheduleExportFilter, ownerUUID *uuid.UUID) (int64, error) {
+	var total int64
+	err := f.db.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Scopes(f.exportFilter(param, ownerUUID)).
+		Count(&total).Error
+	if err != nil {
+		return 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return total, nil
+}
+
+// StreamForExport calls fn with each schedule matching the export filter, in
+// date and time order, reading them from the database one row at a time so
+// that an export of any size runs in constant memory.
+func (f *FieldScheduleRepository) StreamForExport(
+	ctx context.Context,
+	param *dto.FieldScheduleExportFilter,
+	ownerUUID *uuid.UUID,
+	fn func(*dto.FieldScheduleExportRow) error,
+) error {
+	rows, err := f.db.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Select(`field_schedules.uuid, fields.code AS field_code, fields.name AS field_name,
+			field_schedules.date, times.start_time, times.end_time, field_schedules.status,
+			fields.price_per_hour, field_schedules.price, field_schedules.order_id,
+			COALESCE(order_customers.name, '') AS booker_name,
+			COALESCE(order_customers.email, '') AS booker_email,
+			COALESCE(order_customers.phone_number, '') AS booker_phone`).
+		Joins("JOIN fields ON fields.id = field_schedules.field_id").
+		Joins("JOIN times ON times.id = field_schedules.time_id").
+		Joins(`LEFT JOIN order_customers ON order_customers.tenant_id = field_schedules.tenant_id
+			AND order_customers.order_id = field_schedules.order_id
+			AND field_schedules.order_id <> ''`).
+		Scopes(f.exportFilter(param, ownerUUID)).
+		Order("field_schedules.date asc, times.start_time asc, fields.name asc").
+		Rows()
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row dto.FieldScheduleExportRow
+		err = f.db.ScanRows(rows, &row)
+		if err != nil {
+			return errWrap.WrapError(errConstant.ErrSQLError)
+		}
+		err = fn(&row)
+		if err != nil {
+			return err
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+// CreateWithTx creates the schedules and their history rows in the caller's
+// transaction.
+func (f *FieldScheduleRepository) CreateWithTx(ctx context.Context, tx *gorm.DB, req []models.FieldSchedule) error {
+	if len(req) == 0 {
+		return nil
+	}
+	err := tx.WithContext(ctx).CreateInBatches(&req, 500).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	histories := make([]models.FieldScheduleHistory, 0, len(req))
+	for i := range req {
+		histories = append(histories, f.newHistory(&req[i], constants.FieldScheduleHistoryCreated, nil, ""))
+	}
+	return f.writeHistory(ctx, tx, histories)
+}
+
+func (f *FieldScheduleRepository) newHistory(
+	fieldSchedule *models.FieldSchedule,
+	event string,
+	fromStatus *constants.FieldScheduleStatus,
+	reference string,
+) models.FieldScheduleHistory {
+	return models.FieldScheduleHistory{
+		FieldScheduleID:   fieldSchedule.ID,
+		FieldScheduleUUID: fieldSchedule.UUID,
+		FieldID:           fieldSchedule.FieldID,
+		TimeID:            fieldSchedule.TimeID,
+		Date:              fieldSchedule.Date,
+		Event:             event,
+		FromStatus:        fromStatus,
+		Status:            fieldSchedule.Status,
+		Reference:         reference,
+	}
+}
+
+// writeHistory appends the history rows in the transaction of the change they
+// describe, attributed to the actor of the request.
+func (f *FieldScheduleRepository) writeHistory(ctx context.Context, tx *gorm.DB, histories []models.FieldScheduleHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	actor := policy.ActorFromContext(ctx)
+	for i := range histories {
+		histories[i].UUID = uuid.New()
+		histories[i].ActorUUID = actor.UUID
+		histories[i].ActorName = actor.Name
+		histories[i].ActorRole = actor.Role
+	}
+	err := tx.WithContext(ctx).CreateInBatches(&histories, 100).Error
 	if err != nil {
 		return fmt.Errorf("%w: %v", errWrap.WrapError(errConstant.ErrSQLError), err)
 	}
+	return f.writeEvents(ctx, tx, histories)
+}
+
+// eventType maps a history row to the domain event it announces, or "" when
+// nobody has an interest in the change.
+func (f *FieldScheduleRepository) eventType(history *models.FieldScheduleHistory) string {
+	switch history.Event {
+	case constants.FieldScheduleHistoryStatusChanged:
+		switch history.Status {
+		case constants.Booked:
+			return constants.FieldScheduleBooked
+		case constants.CheckedIn:
+			return constants.FieldScheduleCheckedIn
+		case constants.NoShow:
+			return constants.FieldScheduleNoShow
+		}
+		if history.FromStatus == nil || !history.FromStatus.IsBooking() {
+			return constants.FieldScheduleStatusChanged
+		}
+		if history.Status == constants.Available {
+			return constants.FieldScheduleReleased
+		}
+		return constants.FieldScheduleCancelled
+	case constants.FieldScheduleHistoryRescheduled:
+		if history.Status == constants.Booked {
+			return constants.FieldScheduleRescheduled
+		}
+	case constants.FieldScheduleHistoryDeleted:
+		if history.Status.IsBooking() {
+			return constants.FieldScheduleCancelled
+		}
+	}
+	return ""
+}
+
+// writeEvents adds the domain events announced by the history rows to the
+// outbox, in the same transaction, for the relay to publish once committed.
+// The event ID is the history row's UUID.
+func (f *FieldScheduleRepository) writeEvents(ctx context.Context, tx *gorm.DB, histories []models.FieldScheduleHistory) error {
+	announced := make([]models.FieldScheduleHistory, 0, len(histories))
+	fieldIDs := make([]uint, 0, len(histories))
+	timeIDs := make([]uint, 0, len(histories))
+	references := make([]string, 0, len(histories))
+	for _, history := range histories {
+		if f.eventType(&history) == "" {
+			continue
+		}
+		announced = append(announced, history)
+		fieldIDs = append(fieldIDs, history.FieldID)
+		timeIDs = append(timeIDs, history.TimeID)
+		if _, err := uuid.Parse(history.Reference); err == nil && history.Status == constants.Maintenance {
+			references = append(references, history.Reference)
+		}
+	}
+	if len(announced) == 0 {
+		return nil
+	}
+
+	var (
+		fields  []models.Field
+		times   []models.Time
+		windows []models.MaintenanceWindow
+	)
+	err := tx.WithContext(ctx).Unscoped().Where("id IN ?", fieldIDs).Find(&fields).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = tx.WithContext(ctx).Where("id IN ?", timeIDs).Find(&times).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if len(references) > 0 {
+		err = tx.WithContext(ctx).Where("uuid IN ?", references).Find(&windows).Error
+		if err != nil {
+			return errWrap.WrapError(errConstant.ErrSQLError)
+		}
+	}
+	fieldByID := make(map[uint]models.Field, len(fields))
+	for _, field := range fields {
+		fieldByID[field.ID] = field
+	}
+	timeByID := make(map[uint]models.Time, len(times))
+	for _, t := range times {
+		timeByID[t.ID] = t
+	}
+	reasonByReference := make(map[string]string, len(windows))
+	for _, window := range windows {
+		reasonByReference[window.UUID.String()] = window.Reason
+	}
+
+	now := time.Now()
+	events := make([]models.OutboxEvent, 0, len(announced))
+	for _, history := range announced {
+		field := fieldByID[history.FieldID]
+		payload, err := json.Marshal(dto.FieldScheduleEvent{
+			ScheduleUUID: history.FieldScheduleUUID,
+			FieldUUID:    field.UUID,
+			FieldName:    field.Name,
+			Date:         history.Date.Format(time.DateOnly),
+			StartTime:    timeByID[history.TimeID].StartTime,
+			EndTime:      timeByID[history.TimeID].EndTime,
+			Status:       string(history.Status.GetStatusString()),
+			Reference:    history.Reference,
+			Reason:       reasonByReference[history.Reference],
+			OccurredAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, models.OutboxEvent{
+			UUID:          history.UUID,
+			Type:          f.eventType(&history),
+			Key:           history.FieldScheduleUUID.String(),
+			Payload:       string(payload),
+			Status:        constants.OutboxPending,
+			NextAttemptAt: now,
+		})
+	}
+	err = tx.WithContext(ctx).CreateInBatches(&events, 100).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
 	return nil
 }
 
-func (f *FieldScheduleRepository) Update(ctx context.Context, uuid string, req *models.FieldSchedule) (*models.FieldSchedule, error) {
-	fieldSchedule, err := f.FindByUUID(ctx, uuid)
+// changeStatus moves the given schedules to status and records a history row
+// for each one that actually changed. A Booked schedule keeps the reference as
+// the order holding it, which stays through check-in or no-show; any other
+// status clears it.
+func (f *FieldScheduleRepository) changeStatus(
+	ctx context.Context,
+	tx *gorm.DB,
+	fieldSchedules []models.FieldSchedule,
+	status constants.FieldScheduleStatus,
+	reference string,
+) ([]models.FieldSchedule, error) {
+	ids := make([]uint, 0, len(fieldSchedules))
+	histories := make([]models.FieldScheduleHistory, 0, len(fieldSchedules))
+	changed := make([]models.FieldSchedule, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		if fieldSchedule.Status == status {
+			continue
+		}
+		fromStatus := fieldSchedule.Status
+		fieldSchedule.Status = status
+		ids = append(ids, fieldSchedule.ID)
+		histories = append(histories, f.newHistory(&fieldSchedule, constants.FieldScheduleHistoryStatusChanged, &fromStatus, reference))
+		changed = append(changed, fieldSchedule)
+	}
+	if len(ids) == 0 {
+		return changed, nil
+	}
+	columns := map[string]interface{}{"status": status}
+	idsByPrice := map[int][]uint{0: ids}
+	switch {
+	case status == constants.Booked:
+		columns["order_id"] = reference
+		var err error
+		idsByPrice, err = f.groupByPrice(ctx, tx, changed)
+		if err != nil {
+			return nil, err
+		}
+	case !status.IsBooking():
+		columns["order_id"] = ""
+		columns["price"] = 0
+	}
+	for price, priceIDs := range idsByPrice {
+		if status == constants.Booked {
+			columns["price"] = price
+		}
+		err := tx.WithContext(ctx).
+			Model(&models.FieldSchedule{}).
+			Where("id IN ?", priceIDs).
+			Updates(columns).Error
+		if err != nil {
+			return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		}
+	}
+	return changed, f.writeHistory(ctx, tx, histories)
+}
+
+// slotMinutes is the length of the slot in minutes, or 0 when its times
+// cannot be read. A slot ending at or before its start runs past midnight.
+func (f *FieldScheduleRepository) slotMinutes(t *models.Time) int {
+	startTime, startErr := time.Parse(time.TimeOnly, t.StartTime)
+	endTime, endErr := time.Parse(time.TimeOnly, t.EndTime)
+	if startErr != nil || endErr != nil {
+		return 0
+	}
+	if !endTime.After(startTime) {
+		endTime = endTime.Add(24 * time.Hour)
+	}
+	return int(endTime.Sub(startTime) / time.Minute)
+}
+
+// groupByPrice groups the IDs of the schedules by the price of their slot:
+// the price per hour of the field for the length of the slot, rounded to the
+// nearest unit.
+func (f *FieldScheduleRepository) groupByPrice(
+	ctx context.Context,
+	tx *gorm.DB,
+	fieldSchedules []models.FieldSchedule,
+) (map[int][]uint, error) {
+	fieldIDs := make([]uint, 0, len(fieldSchedules))
+	timeIDs := make([]uint, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		fieldIDs = append(fieldIDs, fieldSchedule.FieldID)
+		timeIDs = append(timeIDs, fieldSchedule.TimeID)
+	}
+	var (
+		fields []models.Field
+		times  []models.Time
+	)
+	err := tx.WithContext(ctx).Unscoped().Where("id IN ?", fieldIDs).Find(&fields).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = tx.WithContext(ctx).Where("id IN ?", timeIDs).Find(&times).Error
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotFound), err)
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	pricePerHour := make(map[uint]int, len(fields))
+	for _, field := range fields {
+		pricePerHour[field.ID] = field.PricePerHour
+	}
+	minutes := make(map[uint]int, len(times))
+	for _, t := range times {
+		minutes[t.ID] = f.slotMinutes(&t)
 	}
 
-	fieldSchedule.Date = req.Date
-	fieldSchedule.TimeID = req.TimeID
+	idsByPrice := map[int][]uint{}
+	for _, fieldSchedule := range fieldSchedules {
+		price := (pricePerHour[fieldSchedule.FieldID]*minutes[fieldSchedule.TimeID] + 30) / 60
+		idsByPrice[price] = append(idsByPrice[price], fieldSchedule.ID)
+	}
+	return idsByPrice, nil
+}
 
-	err = f.db.WithContext(ctx).
+// findForUpdate locks and returns the schedule with the given UUID.
+func (f *FieldScheduleRepository) findForUpdate(ctx context.Context, tx *gorm.DB, uuid string) (*models.FieldSchedule, error) {
+	var fieldSchedule models.FieldSchedule
+	err := tx.WithContext(ctx).
 		Where("uuid = ?", uuid).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&fieldSchedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &fieldSchedule, nil
+}
+
+// checkTarget makes sure the slot the schedule moves to is free: the field has
+// no other schedule there, no maintenance window of the field covers it and no
+// related field holds a booking for it. The slots of the related fields are
+// locked until the move commits.
+func (f *FieldScheduleRepository) checkTarget(
+	ctx context.Context,
+	tx *gorm.DB,
+	fieldSchedule *models.FieldSchedule,
+	date time.Time,
+	target *models.Time,
+) error {
+	relatedIDs, err := f.relatedFieldIDs(ctx, tx, fieldSchedule.FieldID)
+	if err != nil {
+		return err
+	}
+	var slots []models.FieldSchedule
+	err = tx.WithContext(ctx).
+		Where("field_id IN ?", append(relatedIDs, fieldSchedule.FieldID)).
+		Where("date = ?", date.Format(time.DateOnly)).
+		Where("time_id = ?", target.ID).
+		Where("id <> ?", fieldSchedule.ID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Find(&slots).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	for _, slot := range slots {
+		if slot.FieldID == fieldSchedule.FieldID {
+			return errWrap.WrapError(errFieldSchedule.ErrFieldScheduleIsExist)
+		}
+		if slot.Status.IsBooking() {
+			return errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotAvailable)
+		}
+	}
+	var windows int64
+	err = tx.WithContext(ctx).
+		Model(&models.MaintenanceWindow{}).
+		Where("field_id = ?", fieldSchedule.FieldID).
+		Where("deleted_at IS NULL").
+		Where("start_date <= ? AND end_date >= ?", date.Format(time.DateOnly), date.Format(time.DateOnly)).
+		Where("start_time < ? AND end_time > ?", target.EndTime, target.StartTime).
+		Count(&windows).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if windows > 0 {
+		return errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotAvailable)
+	}
+	return nil
+}
+
+// Update moves the schedule to another date or time. The target slot must be
+// free on the field and on its related fields. A booked schedule keeps the
+// rate it was booked at, so its price follows the length of the new slot. The
+// slots it leaves and the ones it takes are synced on the related fields in
+// the caller's transaction.
+func (f *FieldScheduleRepository) Update(ctx context.Context, tx *gorm.DB, uuid string, req *models.FieldSchedule) (*models.FieldSchedule, error) {
+	var fieldSchedule models.FieldSchedule
+	previous, err := f.findForUpdate(ctx, tx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	var times []models.Time
+	err = tx.WithContext(ctx).Where("id IN ?", []uint{previous.TimeID, req.TimeID}).Find(&times).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	timeByID := make(map[uint]*models.Time, len(times))
+	for i := range times {
+		timeByID[times[i].ID] = &times[i]
+	}
+	target, ok := timeByID[req.TimeID]
+	if !ok {
+		return nil, errWrap.WrapError(errTime.ErrTimeNotFound)
+	}
+	err = f.checkTarget(ctx, tx, previous, req.Date, target)
+	if err != nil {
+		return nil, err
+	}
+	price := previous.Price
+	if source, ok := timeByID[previous.TimeID]; ok && price > 0 {
+		fromMinutes, toMinutes := f.slotMinutes(source), f.slotMinutes(target)
+		if fromMinutes > 0 && fromMinutes != toMinutes {
+			price = (price*toMinutes + fromMinutes/2) / fromMinutes
+		}
+	}
+	err = tx.WithContext(ctx).
 		Model(&models.FieldSchedule{}).
+		Where("id = ?", previous.ID).
 		Updates(map[string]interface{}{
 			"date":    req.Date,
 			"time_id": req.TimeID,
+			"price":   price,
 		}).Error
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errWrap.WrapError(errConstant.ErrSQLError), err)
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = tx.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Where("id = ?", previous.ID).
+		First(&fieldSchedule).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = f.writeHistory(ctx, tx, []models.FieldScheduleHistory{
+		f.newHistory(&fieldSchedule, constants.FieldScheduleHistoryRescheduled, nil, ""),
+	})
+	if err != nil {
+		return nil, err
 	}
+	err = f.syncRelatedSlots(ctx, tx, previous)
+	if err != nil {
+		return nil, err
+	}
+	err = f.syncRelatedSlots(ctx, tx, &fieldSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldSchedule, nil
+}
 
-	return fieldSchedule, nil
+// UpdateStatus moves one schedule from the from status to status. The row is
+// locked and its status checked again in the caller's transaction, so a
+// concurrent change makes it fail with ErrFieldScheduleNotAvailable. The
+// reference, such as the order ID of a booking, is kept in the schedule's
+// history.
+func (f *FieldScheduleRepository) UpdateStatus(
+	ctx context.Context,
+	tx *gorm.DB,
+	from constants.FieldScheduleStatus,
+	status constants.FieldScheduleStatus,
+	uuid string,
+	reference string,
+) error {
+	fieldSchedule, err := f.findForUpdate(ctx, tx, uuid)
+	if err != nil {
+		return err
+	}
+	if fieldSchedule.Status != from {
+		return errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotAvailable)
+	}
+	return f.UpdateStatusByIDs(ctx, tx, []uint{fieldSchedule.ID}, status, reference)
 }
 
-func (f *FieldScheduleRepository) UpdateStatus(ctx context.Context, status constants.FieldScheduleStatus, uuid string) error {
-	fieldSchedule, err := f.FindByUUID(ctx, uuid)
+// freeStatus is the status of an unbooked slot of the field: Blocked while any
+// field related to it holds a booking for the same date and time, Available
+// otherwise.
+func (f *FieldScheduleRepository) freeStatus(
+	ctx context.Context,
+	tx *gorm.DB,
+	fieldID uint,
+	date time.Time,
+	timeID uint,
+) (constants.FieldScheduleStatus, error) {
+	ownerIDs, err := f.relatedFieldIDs(ctx, tx, fieldID)
 	if err != nil {
-		return fmt.Errorf("%w: %v", errWrap.WrapError(errFieldSchedule.ErrFieldScheduleNotFound), err)
+		return 0, err
 	}
-	fieldSchedule.Status = status
-	err = f.db.WithContext(ctx).Save(&fieldSchedule).Error
+	var booked int64
+	err = tx.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Where("field_id IN ?", ownerIDs).
+		Where("date = ?", date.Format(time.DateOnly)).
+		Where("time_id = ?", timeID).
+		Where("status IN ?", constants.BookingStatuses).
+		Count(&booked).Error
 	if err != nil {
-		return fmt.Errorf("%w: %v", errWrap.WrapError(errConstant.ErrSQLError), err)
+		return 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if booked > 0 {
+		return constants.Blocked, nil
+	}
+	return constants.Available, nil
+}
+
+// syncRelatedSlots recomputes the slot at the same date and time on every
+// field sharing physical space with the schedule's field (its parent and its
+// children). A related slot is Blocked while any field related to it holds a
+// booking for that slot, and becomes Available again once none does.
+func (f *FieldScheduleRepository) syncRelatedSlots(ctx context.Context, tx *gorm.DB, fieldSchedule *models.FieldSchedule) error {
+	relatedIDs, err := f.relatedFieldIDs(ctx, tx, fieldSchedule.FieldID)
+	if err != nil {
+		return err
+	}
+	for _, relatedID := range relatedIDs {
+		status, err := f.freeStatus(ctx, tx, relatedID, fieldSchedule.Date, fieldSchedule.TimeID)
+		if err != nil {
+			return err
+		}
+		var relatedSchedules []models.FieldSchedule
+		err = tx.WithContext(ctx).
+			Where("field_id = ?", relatedID).
+			Where("date = ?", fieldSchedule.Date.Format(time.DateOnly)).
+			Where("time_id = ?", fieldSchedule.TimeID).
+			Where("status IN ?", []constants.FieldScheduleStatus{constants.Available, constants.Blocked}).
+			Find(&relatedSchedules).Error
+		if err != nil {
+			return errWrap.WrapError(errConstant.ErrSQLError)
+		}
+		_, err = f.changeStatus(ctx, tx, relatedSchedules, status, fieldSchedule.UUID.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FieldScheduleRepository) relatedFieldIDs(ctx context.Context, tx *gorm.DB, fieldID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.WithContext(ctx).
+		Model(&models.Field{}).
+		Where("id = (SELECT parent_id FROM fields WHERE id = ?) OR parent_id = ?", fieldID, fieldID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return ids, nil
+}
+
+// FindAllOverlappingMaintenance locks and returns the schedules of the window's
+// field whose slot overlaps the window's daily time range within its dates.
+func (f *FieldScheduleRepository) FindAllOverlappingMaintenance(
+	ctx context.Context,
+	tx *gorm.DB,
+	window *models.MaintenanceWindow,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := tx.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Joins("JOIN times ON field_schedules.time_id = times.id").
+		Where("field_schedules.field_id = ?", window.FieldID).
+		Where("field_schedules.date BETWEEN ? AND ?", window.StartDate.Format(time.DateOnly), window.EndDate.Format(time.DateOnly)).
+		Where("times.start_time < ? AND times.end_time > ?", window.EndTime, window.StartTime).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "field_schedules"}}).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+func (f *FieldScheduleRepository) UpdateStatusByIDs(
+	ctx context.Context,
+	tx *gorm.DB,
+	ids []uint,
+	status constants.FieldScheduleStatus,
+	reference string,
+) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var fieldSchedules []models.FieldSchedule
+	err := tx.WithContext(ctx).Where("id IN ?", ids).Find(&fieldSchedules).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	changed, err := f.changeStatus(ctx, tx, fieldSchedules, status, reference)
+	if err != nil {
+		return err
+	}
+	for _, fieldSchedule := range changed {
+		err = f.syncRelatedSlots(ctx, tx, &fieldSchedule)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (f *FieldScheduleRepository) Delete(ctx context.Context, uuid string) error {
-	err := f.db.WithContext(ctx).Where("uuid=?", uuid).Delete(&models.FieldSchedule{}).Error
+// ReleaseMaintenance frees the schedules blocked by the window, except those
+// still covered by another maintenance window. A released slot becomes Blocked
+// while a related field holds a booking for it, and Available otherwise.
+func (f *FieldScheduleRepository) ReleaseMaintenance(ctx context.Context, tx *gorm.DB, window *models.MaintenanceWindow) error {
+	var fieldSchedules []models.FieldSchedule
+	err := tx.WithContext(ctx).
+		Where("field_id = ?", window.FieldID).
+		Where("status = ?", constants.Maintenance).
+		Where("date BETWEEN ? AND ?", window.StartDate.Format(time.DateOnly), window.EndDate.Format(time.DateOnly)).
+		Where("time_id IN (?)", tx.WithContext(ctx).Model(&models.Time{}).
+			Select("id").
+			Where("start_time < ? AND end_time > ?", window.EndTime, window.StartTime)).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM maintenance_windows mw JOIN times t ON t.id = field_schedules.time_id
+			WHERE mw.field_id = field_schedules.field_id
+			AND mw.id <> ?
+			AND mw.deleted_at IS NULL
+			AND field_schedules.date BETWEEN mw.start_date AND mw.end_date
+			AND t.start_time < mw.end_time AND t.end_time > mw.start_time
+		)`, window.ID).
+		Find(&fieldSchedules).Error
 	if err != nil {
 		return errWrap.WrapError(errConstant.ErrSQLError)
 	}
-	return err
+	byStatus := map[constants.FieldScheduleStatus][]models.FieldSchedule{}
+	for _, fieldSchedule := range fieldSchedules {
+		status, err := f.freeStatus(ctx, tx, fieldSchedule.FieldID, fieldSchedule.Date, fieldSchedule.TimeID)
+		if err != nil {
+			return err
+		}
+		byStatus[status] = append(byStatus[status], fieldSchedule)
+	}
+	for status, released := range byStatus {
+		_, err = f.changeStatus(ctx, tx, released, status, window.UUID.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the schedule and frees the slot it blocked on the related
+// fields, in the caller's transaction.
+func (f *FieldScheduleRepository) Delete(ctx context.Context, tx *gorm.DB, uuid string) error {
+	fieldSchedule, err := f.findForUpdate(ctx, tx, uuid)
+	if err != nil {
+		return err
+	}
+	err = tx.WithContext(ctx).Where("id = ?", fieldSchedule.ID).Delete(&models.FieldSchedule{}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = f.writeHistory(ctx, tx, []models.FieldScheduleHistory{
+		f.newHistory(fieldSchedule, constants.FieldScheduleHistoryDeleted, nil, ""),
+	})
+	if err != nil {
+		return err
+	}
+	return f.syncRelatedSlots(ctx, tx, fieldSchedule)
 }
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"field-service/common/tenant"
+	"field-service/constants"
+	errFieldSchedule "field-service/constants/error/fieldSchedule"
+	"field-service/domain/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sharedSpace is a parent field split into one child, with two one-hour slots
+// and a schedule for each field in each slot on day.
+type sharedSpace struct {
+	repository IFieldScheduleRepository
+	db         *gorm.DB
+	ctx        context.Context
+	day        time.Time
+	parent     models.Field
+	child      models.Field
+	morning    models.Time
+	evening    models.Time
+}
+
+func newSharedSpace(t *testing.T) *sharedSpace {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.Field{}, &models.Time{}, &models.FieldSchedule{}, &models.FieldScheduleHistory{},
+		&models.MaintenanceWindow{}, &models.OutboxEvent{},
+	)
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	// SQLite keeps a time.Time as a full timestamp. Trim the dates to what a
+	// DATE column holds, so they compare equal to the dates queried for.
+	for _, trigger := range []string{
+		`CREATE TRIGGER field_schedules_insert_date AFTER INSERT ON field_schedules BEGIN
+			UPDATE field_schedules SET date = substr(NEW.date, 1, 10) WHERE id = NEW.id; END`,
+		`CREATE TRIGGER field_schedules_update_date AFTER UPDATE OF date ON field_schedules
+			WHEN length(NEW.date) > 10 BEGIN
+			UPDATE field_schedules SET date = substr(NEW.date, 1, 10) WHERE id = NEW.id; END`,
+		`CREATE TRIGGER maintenance_windows_insert_date AFTER INSERT ON maintenance_windows BEGIN
+			UPDATE maintenance_windows SET start_date = substr(NEW.start_date, 1, 10),
+			end_date = substr(NEW.end_date, 1, 10) WHERE id = NEW.id; END`,
+	} {
+		if err = db.Exec(trigger).Error; err != nil {
+			t.Fatalf("failed to create trigger: %v", err)
+		}
+	}
+
+	s := &sharedSpace{
+		repository: NewFieldScheduleRepository(db),
+		db:         db,
+		ctx:        tenant.WithTenant(context.Background(), "tenant-a"),
+		day:        time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC),
+	}
+	s.parent = models.Field{UUID: uuid.New(), Code: "P", Name: "Parent", PricePerHour: 200000, Images: []string{}}
+	s.create(t, &s.parent)
+	s.child = models.Field{UUID: uuid.New(), Code: "C", Name: "Child", PricePerHour: 100000, Images: []string{}, ParentID: &s.parent.ID}
+	s.create(t, &s.child)
+	s.morning = models.Time{UUID: uuid.New(), StartTime: "08:00:00", EndTime: "09:00:00"}
+	s.create(t, &s.morning)
+	s.evening = models.Time{UUID: uuid.New(), StartTime: "18:00:00", EndTime: "20:00:00"}
+	s.create(t, &s.evening)
+	for _, field := range []models.Field{s.parent, s.child} {
+		for _, slot := range []models.Time{s.morning, s.evening} {
+			s.create(t, &models.FieldSchedule{
+				UUID:    uuid.New(),
+				FieldID: field.ID,
+				TimeID:  slot.ID,
+				Date:    s.day,
+				Status:  constants.Available,
+			})
+		}
+	}
+	return s
+}
+
+func (s *sharedSpace) create(t *testing.T, value interface{}) {
+	t.Helper()
+	if err := s.db.WithContext(s.ctx).Create(value).Error; err != nil {
+		t.Fatalf("failed to create %T: %v", value, err)
+	}
+}
+
+func (s *sharedSpace) slot(t *testing.T, field models.Field, slot models.Time) models.FieldSchedule {
+	t.Helper()
+	var fieldSchedule models.FieldSchedule
+	err := s.db.WithContext(s.ctx).
+		Where("field_id = ? AND time_id = ?", field.ID, slot.ID).
+		First(&fieldSchedule).Error
+	if err != nil {
+		t.Fatalf("failed to find schedule: %v", err)
+	}
+	return fieldSchedule
+}
+
+func (s *sharedSpace) book(t *testing.T, fieldSchedule models.FieldSchedule) {
+	t.Helper()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.repository.UpdateStatusByIDs(s.ctx, tx, []uint{fieldSchedule.ID}, constants.Booked, "order-1")
+	})
+	if err != nil {
+		t.Fatalf("failed to book: %v", err)
+	}
+}
+
+func (s *sharedSpace) release(t *testing.T, fieldSchedule models.FieldSchedule) {
+	t.Helper()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.repository.UpdateStatusByIDs(s.ctx, tx, []uint{fieldSchedule.ID}, constants.Available, "order-1")
+	})
+	if err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+}
+
+func (s *sharedSpace) reschedule(fieldSchedule models.FieldSchedule, slot models.Time) (*models.FieldSchedule, error) {
+	var moved *models.FieldSchedule
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		moved, txErr = s.repository.Update(s.ctx, tx, fieldSchedule.UUID.String(), &models.FieldSchedule{Date: s.day, TimeID: slot.ID})
+		return txErr
+	})
+	return moved, err
+}
+
+func (s *sharedSpace) assertStatus(t *testing.T, field models.Field, slot models.Time, want constants.FieldScheduleStatus) {
+	t.Helper()
+	if got := s.slot(t, field, slot).Status; got != want {
+		t.Errorf("%s at %s is %s, want %s", field.Name, slot.StartTime, got.GetStatusString(), want.GetStatusString())
+	}
+}
+
+func TestBookingBlocksRelatedSlots(t *testing.T) {
+	s := newSharedSpace(t)
+
+	s.book(t, s.slot(t, s.parent, s.morning))
+	s.assertStatus(t, s.child, s.morning, constants.Blocked)
+	s.assertStatus(t, s.child, s.evening, constants.Available)
+
+	s.release(t, s.slot(t, s.parent, s.morning))
+	s.assertStatus(t, s.child, s.morning, constants.Available)
+}
+
+func TestReleaseMaintenanceKeepsRelatedBookingsBlocked(t *testing.T) {
+	s := newSharedSpace(t)
+	window := models.MaintenanceWindow{
+		UUID: uuid.New(), FieldID: s.child.ID, StartDate: s.day, EndDate: s.day,
+		StartTime: "00:00:00", EndTime: "23:59:59", Reason: "resurfacing",
+	}
+	s.create(t, &window)
+	ids := []uint{s.slot(t, s.child, s.morning).ID, s.slot(t, s.child, s.evening).ID}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.repository.UpdateStatusByIDs(s.ctx, tx, ids, constants.Maintenance, window.UUID.String())
+	})
+	if err != nil {
+		t.Fatalf("failed to start maintenance: %v", err)
+	}
+	s.book(t, s.slot(t, s.parent, s.morning))
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if txErr := tx.WithContext(s.ctx).Delete(&window).Error; txErr != nil {
+			return txErr
+		}
+		return s.repository.ReleaseMaintenance(s.ctx, tx, &window)
+	})
+	if err != nil {
+		t.Fatalf("failed to release maintenance: %v", err)
+	}
+	s.assertStatus(t, s.child, s.morning, constants.Blocked)
+	s.assertStatus(t, s.child, s.evening, constants.Available)
+}
+
+func TestRescheduleRejectsSharedSpaceConflicts(t *testing.T) {
+	s := newSharedSpace(t)
+	childEvening := s.slot(t, s.child, s.evening)
+	s.book(t, childEvening)
+	// Free the child's morning slot so the booking could move into it.
+	s.db.WithContext(s.ctx).Delete(&models.FieldSchedule{}, s.slot(t, s.child, s.morning).ID)
+
+	s.book(t, s.slot(t, s.parent, s.morning))
+	_, err := s.reschedule(childEvening, s.morning)
+	if !errors.Is(err, errFieldSchedule.ErrFieldScheduleNotAvailable) {
+		t.Fatalf("moved into a slot booked on the parent: %v", err)
+	}
+
+	s.release(t, s.slot(t, s.parent, s.morning))
+	window := models.MaintenanceWindow{
+		UUID: uuid.New(), FieldID: s.child.ID, StartDate: s.day, EndDate: s.day,
+		StartTime: "07:00:00", EndTime: "10:00:00", Reason: "lights",
+	}
+	s.create(t, &window)
+	_, err = s.reschedule(childEvening, s.morning)
+	if !errors.Is(err, errFieldSchedule.ErrFieldScheduleNotAvailable) {
+		t.Fatalf("moved into a maintenance window: %v", err)
+	}
+	s.db.WithContext(s.ctx).Delete(&window)
+
+	moved, err := s.reschedule(childEvening, s.morning)
+	if err != nil {
+		t.Fatalf("failed to reschedule: %v", err)
+	}
+	// Booked for two hours at 100000 an hour, then moved to a one-hour slot.
+	if moved.Price != 100000 {
+		t.Errorf("rescheduled price = %d, want 100000", moved.Price)
+	}
+	s.assertStatus(t, s.parent, s.morning, constants.Blocked)
+	s.assertStatus(t, s.parent, s.evening, constants.Available)
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FieldScheduleHistoryRepository struct {
+	db *gorm.DB
+}
+
+// IFieldScheduleHistoryRepository reads the history written by
+// FieldScheduleRepository alongside every change.
+type IFieldScheduleHistoryRepository interface {
+	FindAllByFieldScheduleUUID(context.Context, string) ([]models.FieldScheduleHistory, error)
+	FindBoardAt(context.Context, uint, string, time.Time) ([]models.FieldScheduleHistory, error)
+}
+
+func NewFieldScheduleHistoryRepository(db *gorm.DB) IFieldScheduleHistoryRepository {
+	return &FieldScheduleHistoryRepository{db: db}
+}
+
+func (f *FieldScheduleHistoryRepository) FindAllByFieldScheduleUUID(ctx context.Context, uuid string) ([]models.FieldScheduleHistory, error) {
+	var histories []models.FieldScheduleHistory
+	err := f.db.WithContext(ctx).
+		Preload("Field").
+		Preload("Time").
+		Where("field_schedule_uuid = ?", uuid).
+		Order("created_at asc, id asc").
+		Find(&histories).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return histories, nil
+}
+
+// FindBoardAt returns, for every schedule of the field, the latest history row
+// written at or before the given moment, keeping only the schedules that were
+// on the given date and not deleted at that moment.
+func (f *FieldScheduleHistoryRepository) FindBoardAt(ctx context.Context, fieldID uint, date string, at time.Time) ([]models.FieldScheduleHistory, error) {
+	var histories []models.FieldScheduleHistory
+	latest := f.db.WithContext(ctx).
+		Model(&models.FieldScheduleHistory{}).
+		Select("DISTINCT ON (field_schedule_id) *").
+		Where("field_id = ?", fieldID).
+		Where("created_at <= ?", at).
+		Order("field_schedule_id, created_at desc, id desc")
+	err := f.db.WithContext(ctx).
+		Table("(?) AS field_schedule_histories", latest).
+		Preload("Time").
+		Where("date = ?", date).
+		Where("event <> ?", constants.FieldScheduleHistoryDeleted).
+		Find(&histories).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return histories, nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FieldScheduleRollupRepository struct {
+	db *gorm.DB
+}
+
+type IFieldScheduleRollupRepository interface {
+	FindLatestRefreshedAt(context.Context) (*time.Time, error)
+	FindDays(context.Context, *time.Time) (map[string][]time.Time, error)
+	Refresh(context.Context, *gorm.DB, []time.Time, time.Time) error
+	Aggregate(context.Context, *dto.AnalyticsRequestParam, *uuid.UUID, ...string) ([]dto.AnalyticsRow, error)
+}
+
+func NewFieldScheduleRollupRepository(db *gorm.DB) IFieldScheduleRollupRepository {
+	return &FieldScheduleRollupRepository{db: db}
+}
+
+// slotMinutes is the length of a slot in minutes. A slot ending at or before
+// its start runs past midnight.
+const slotMinutes = `EXTRACT(EPOCH FROM CASE
+	WHEN times.end_time > times.start_time THEN times.end_time - times.start_time
+	ELSE times.end_time - times.start_time + INTERVAL '24 hours'
+END) / 60`
+
+var (
+	unavailableStatuses = []constants.FieldScheduleStatus{constants.Maintenance, constants.Blocked}
+
+	// aggregateColumns and aggregateGroups are the columns and GROUP BY
+	// expressions of each analytics dimension.
+	aggregateColumns = map[string]string{
+		constants.AnalyticsByField:   "fields.uuid AS field_id, fields.name AS field_name",
+		constants.AnalyticsByWeekday: "EXTRACT(ISODOW FROM field_schedule_rollups.date)::int AS weekday",
+		constants.AnalyticsByHour:    "field_schedule_rollups.hour",
+	}
+	aggregateGroups = map[string]string{
+		constants.AnalyticsByField:   "fields.uuid, fields.name",
+		constants.AnalyticsByWeekday: "weekday",
+		constants.AnalyticsByHour:    "field_schedule_rollups.hour",
+	}
+)
+
+func (f *FieldScheduleRollupRepository) FindLatestRefreshedAt(ctx context.Context) (*time.Time, error) {
+	var latest sql.NullTime
+	err := f.db.WithContext(ctx).
+		Model(&models.FieldScheduleRollup{}).
+		Select("MAX(refreshed_at)").
+		Row().
+		Scan(&latest)
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
+
+// FindDays returns, by tenant, the days whose rollups may be out of date: the
+// days any schedule changed since was ever on, or every day with schedules
+// when since is nil. Old days of rescheduled schedules are included through
+// their earlier history.
+func (f *FieldScheduleRollupRepository) FindDays(ctx context.Context, since *time.Time) (map[string][]time.Time, error) {
+	var (
+		days []models.FieldScheduleHistory
+		err  error
+	)
+	if since == nil {
+		err = f.db.WithContext(ctx).
+			Model(&models.FieldSchedule{}).
+			Distinct("tenant_id", "date").
+			Scan(&days).Error
+	} else {
+		err = f.db.WithContext(ctx).
+			Model(&models.FieldScheduleHistory{}).
+			Distinct("tenant_id", "date").
+			Where("field_schedule_id IN (?)", f.db.WithContext(ctx).
+				Model(&models.FieldScheduleHistory{}).
+				Select("field_schedule_id").
+				Where("created_at >= ?", *since)).
+			Scan(&days).Error
+	}
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	daysByTenant := map[string][]time.Time{}
+	for _, day := range days {
+		daysByTenant[day.TenantID] = append(daysByTenant[day.TenantID], day.Date)
+	}
+	return daysByTenant, nil
+}
+
+// Refresh recomputes the rollups of the days from their schedules. Days left
+// without schedules lose their rollups.
+func (f *FieldScheduleRollupRepository) Refresh(ctx context.Context, tx *gorm.DB, dates []time.Time, refreshedAt time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+	var rollups []models.FieldScheduleRollup
+	err := tx.WithContext(ctx).
+		Model(&models.FieldSchedule{}).
+		Select(`field_schedules.field_id, field_schedules.date,
+			EXTRACT(HOUR FROM times.start_time)::int AS hour,
+			COUNT(*) AS slot_count,
+			COUNT(*) FILTER (WHERE field_schedules.status IN ?) AS booked_count,
+			COUNT(*) FILTER (WHERE field_schedules.status IN ?) AS unavailable_count,
+			COALESCE(SUM(`+slotMinutes+`) FILTER (WHERE field_schedules.status IN ?), 0)::int AS booked_minutes,
+			COALESCE(SUM(field_schedules.price) FILTER (WHERE field_schedules.status IN ?), 0)::bigint AS revenue`,
+			constants.BookingStatuses, unavailableStatuses, constants.BookingStatuses, constants.BookingStatuses).
+		Joins("JOIN times ON times.id = field_schedules.time_id").
+		Where("field_schedules.date IN ?", dates).
+		Group("field_schedules.field_id, field_schedules.date, hour").
+		Scan(&rollups).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = tx.WithContext(ctx).Where("date IN ?", dates).Delete(&models.FieldScheduleRollup{}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	if len(rollups) == 0 {
+		return nil
+	}
+	for i := range rollups {
+		rollups[i].RefreshedAt = refreshedAt
+	}
+	err = tx.WithContext(ctx).CreateInBatches(rollups, 500).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+// Aggregate sums the rollups in the date range by period of the requested
+// granularity and by the given dimensions, limited to the fields of the owner
+// when ownerUUID is set.
+func (f *FieldScheduleRollupRepository) Aggregate(
+	ctx context.Context,
+	param *dto.AnalyticsRequestParam,
+	ownerUUID *uuid.UUID,
+	dimensions ...string,
+) ([]dto.AnalyticsRow, error) {
+	columns := []string{"to_char(date_trunc(?, field_schedule_rollups.date), 'YYYY-MM-DD') AS period"}
+	groups := []string{"period"}
+	for _, dimension := range dimensions {
+		columns = append(columns, aggregateColumns[dimension])
+		groups = append(groups, aggregateGroups[dimension])
+	}
+	columns = append(columns,
+		"SUM(field_schedule_rollups.slot_count)::int AS slot_count",
+		"SUM(field_schedule_rollups.slot_count - field_schedule_rollups.unavailable_count)::int AS capacity_count",
+		"SUM(field_schedule_rollups.booked_count)::int AS booked_count",
+		"SUM(field_schedule_rollups.booked_minutes)::int AS booked_minutes",
+		"SUM(field_schedule_rollups.revenue)::bigint AS revenue",
+	)
+
+	query := f.db.WithContext(ctx).
+		Model(&models.FieldScheduleRollup{}).
+		Select(strings.Join(columns, ", "), param.Granularity).
+		Joins("JOIN fields ON fields.id = field_schedule_rollups.field_id").
+		Where("field_schedule_rollups.date BETWEEN ? AND ?", param.StartDate, param.EndDate)
+	if param.FieldID != nil && *param.FieldID != "" {
+		query = query.Where("fields.uuid = ?", *param.FieldID)
+	}
+	if ownerUUID != nil {
+		query = query.Where("fields.owner_uuid = ?", *ownerUUID)
+	}
+
+	var rows []dto.AnalyticsRow
+	err := query.
+		Group(strings.Join(groups, ", ")).
+		Order(strings.Join(groups, ", ")).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return rows, nil
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InboxRepository struct {
+	db *gorm.DB
+}
+
+type IInboxRepository interface {
+	Record(context.Context, *gorm.DB, *models.InboxEvent) (bool, error)
+}
+
+func NewInboxRepository(db *gorm.DB) IInboxRepository {
+	return &InboxRepository{db: db}
+}
+
+// Record stores the event in the transaction handling it and reports whether
+// it is new. False means the event ID was already handled.
+func (i *InboxRepository) Record(ctx context.Context, tx *gorm.DB, event *models.InboxEvent) (bool, error) {
+	result := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).
+		Create(event)
+	if result.Error != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	errMaintenanceWindow "field-service/constants/error/maintenanceWindow"
+	"field-service/domain/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MaintenanceWindowRepository struct {
+	db *gorm.DB
+}
+
+type IMaintenanceWindowRepository interface {
+	FindAllByFieldID(context.Context, int) ([]models.MaintenanceWindow, error)
+	FindByUUID(context.Context, string) (*models.MaintenanceWindow, error)
+	Create(context.Context, *gorm.DB, *models.MaintenanceWindow) (*models.MaintenanceWindow, error)
+	Delete(context.Context, *gorm.DB, string) error
+}
+
+func NewMaintenanceWindowRepository(db *gorm.DB) IMaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{db: db}
+}
+
+func (m *MaintenanceWindowRepository) FindAllByFieldID(ctx context.Context, fieldID int) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	err := m.db.WithContext(ctx).
+		Preload("Field").
+		Where("field_id = ?", fieldID).
+		Order("start_date asc, start_time asc").
+		Find(&windows).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return windows, nil
+}
+
+func (m *MaintenanceWindowRepository) FindByUUID(ctx context.Context, uuid string) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	err := m.db.WithContext(ctx).
+		Preload("Field").
+		Where("uuid = ?", uuid).
+		First(&window).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errMaintenanceWindow.ErrMaintenanceWindowNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &window, nil
+}
+
+func (m *MaintenanceWindowRepository) Create(ctx context.Context, tx *gorm.DB, req *models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	req.UUID = uuid.New()
+	err := tx.WithContext(ctx).Create(req).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
+
+func (m *MaintenanceWindowRepository) Delete(ctx context.Context, tx *gorm.DB, uuid string) error {
+	err := tx.WithContext(ctx).Where("uuid = ?", uuid).Delete(&models.MaintenanceWindow{}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderCustomerRepository struct {
+	db *gorm.DB
+}
+
+type IOrderCustomerRepository interface {
+	FindByOrderID(context.Context, *gorm.DB, string, string) (*models.OrderCustomer, error)
+	Save(context.Context, *gorm.DB, *models.OrderCustomer) error
+}
+
+func NewOrderCustomerRepository(db *gorm.DB) IOrderCustomerRepository {
+	return &OrderCustomerRepository{db: db}
+}
+
+// FindByOrderID returns the customer of the tenant's order, or nil when the
+// order came without one. The tenant is given explicitly for the workers
+// reading across tenants.
+func (o *OrderCustomerRepository) FindByOrderID(ctx context.Context, tx *gorm.DB, tenantID string, orderID string) (*models.OrderCustomer, error) {
+	var customer models.OrderCustomer
+	err := tx.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Where("order_id = ?", orderID).
+		First(&customer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &customer, nil
+}
+
+// Save stores the customer of the order, replacing the contact details sent
+// with an earlier booking of the same order.
+func (o *OrderCustomerRepository) Save(ctx context.Context, tx *gorm.DB, customer *models.OrderCustomer) error {
+	err := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "order_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"customer_uuid", "name", "email", "phone_number", "language", "updated_at"}),
+		}).
+		Create(customer).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// IOutboxRepository is used by the outbox relay. Events are written by the
+// repositories making the change, inside their own transaction.
+type IOutboxRepository interface {
+	FindAllDue(context.Context, *gorm.DB, int) ([]models.OutboxEvent, error)
+	Claim(context.Context, *gorm.DB, []uint, time.Time) error
+	MarkPublished(context.Context, *gorm.DB, []uint) error
+	MarkRetry(context.Context, *gorm.DB, *models.OutboxEvent) error
+}
+
+func NewOutboxRepository(db *gorm.DB) IOutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// FindAllDue locks up to limit pending events whose next attempt is due, in
+// the order they were written. Rows locked by another relay are skipped, so
+// replicas never publish the same event concurrently.
+func (o *OutboxRepository) FindAllDue(ctx context.Context, tx *gorm.DB, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := tx.WithContext(ctx).
+		Where("status = ?", constants.OutboxPending).
+		Where("next_attempt_at <= ?", time.Now()).
+		Order("id asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&events).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return events, nil
+}
+
+// Claim pushes the next attempt of the events to until, so that no relay picks
+// them up again while they are being published.
+func (o *OutboxRepository) Claim(ctx context.Context, tx *gorm.DB, ids []uint, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (o *OutboxRepository) MarkPublished(ctx context.Context, tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":       constants.OutboxPublished,
+			"published_at": time.Now(),
+			"last_error":   "",
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+// MarkRetry saves the attempt count, status, next attempt, error and accepting
+// sinks of an event whose publication failed.
+func (o *OutboxRepository) MarkRetry(ctx context.Context, tx *gorm.DB, event *models.OutboxEvent) error {
+	err := tx.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"status":          event.Status,
+			"attempts":        event.Attempts,
+			"next_attempt_at": event.NextAttemptAt,
+			"last_error":      event.LastError,
+			"published_sinks": event.PublishedSinks,
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+type IPaymentRepository interface {
+	FindByOrderID(context.Context, *gorm.DB, string) (*models.Payment, error)
+	Save(context.Context, *gorm.DB, *models.Payment) error
+}
+
+func NewPaymentRepository(db *gorm.DB) IPaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// FindByOrderID locks and returns the payment of the order, or nil when no
+// result was applied for it yet.
+func (p *PaymentRepository) FindByOrderID(ctx context.Context, tx *gorm.DB, orderID string) (*models.Payment, error) {
+	var payment models.Payment
+	err := tx.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &payment, nil
+}
+
+func (p *PaymentRepository) Save(ctx context.Context, tx *gorm.DB, payment *models.Payment) error {
+	err := tx.WithContext(ctx).Save(payment).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
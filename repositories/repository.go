@@ -1,9 +1,22 @@
 package repositories
 
 import (
+	auditLogRepo "field-service/repositories/auditLog"
+	calendarFeedRepo "field-service/repositories/calendarFeed"
+	exportJobRepo "field-service/repositories/exportJob"
 	fieldRepo "field-service/repositories/field"
 	fieldScheduleRepo "field-service/repositories/fieldSchedule"
+	fieldScheduleHistoryRepo "field-service/repositories/fieldScheduleHistory"
+	fieldScheduleRollupRepo "field-service/repositories/fieldScheduleRollup"
+	inboxRepo "field-service/repositories/inbox"
+	maintenanceWindowRepo "field-service/repositories/maintenanceWindow"
+	orderCustomerRepo "field-service/repositories/orderCustomer"
+	outboxRepo "field-service/repositories/outbox"
+	paymentRepo "field-service/repositories/payment"
+	scheduleReminderRepo "field-service/repositories/scheduleReminder"
 	timeRepo "field-service/repositories/time"
+	webhookRepo "field-service/repositories/webhook"
+	webhookDeliveryRepo "field-service/repositories/webhookDelivery"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +29,20 @@ type IRepositoryRegistry interface {
 	GetField() fieldRepo.IFieldRepository
 	GetFieldSchedule() fieldScheduleRepo.IFieldScheduleRepository
 	GetTime() timeRepo.ITimeRepository
+	GetMaintenanceWindow() maintenanceWindowRepo.IMaintenanceWindowRepository
+	GetAuditLog() auditLogRepo.IAuditLogRepository
+	GetFieldScheduleHistory() fieldScheduleHistoryRepo.IFieldScheduleHistoryRepository
+	GetOutbox() outboxRepo.IOutboxRepository
+	GetInbox() inboxRepo.IInboxRepository
+	GetPayment() paymentRepo.IPaymentRepository
+	GetWebhook() webhookRepo.IWebhookRepository
+	GetWebhookDelivery() webhookDeliveryRepo.IWebhookDeliveryRepository
+	GetOrderCustomer() orderCustomerRepo.IOrderCustomerRepository
+	GetScheduleReminder() scheduleReminderRepo.IScheduleReminderRepository
+	GetCalendarFeed() calendarFeedRepo.ICalendarFeedRepository
+	GetExportJob() exportJobRepo.IExportJobRepository
+	GetFieldScheduleRollup() fieldScheduleRollupRepo.IFieldScheduleRollupRepository
+	GetTx() *gorm.DB
 }
 
 func NewRepositoryRegistry(db *gorm.DB) IRepositoryRegistry {
@@ -33,3 +60,59 @@ func (r *Registry) GetFieldSchedule() fieldScheduleRepo.IFieldScheduleRepository
 func (r *Registry) GetTime() timeRepo.ITimeRepository {
 	return timeRepo.NewTimeRepository(r.db)
 }
+
+func (r *Registry) GetMaintenanceWindow() maintenanceWindowRepo.IMaintenanceWindowRepository {
+	return maintenanceWindowRepo.NewMaintenanceWindowRepository(r.db)
+}
+
+func (r *Registry) GetAuditLog() auditLogRepo.IAuditLogRepository {
+	return auditLogRepo.NewAuditLogRepository(r.db)
+}
+
+func (r *Registry) GetFieldScheduleHistory() fieldScheduleHistoryRepo.IFieldScheduleHistoryRepository {
+	return fieldScheduleHistoryRepo.NewFieldScheduleHistoryRepository(r.db)
+}
+
+func (r *Registry) GetOutbox() outboxRepo.IOutboxRepository {
+	return outboxRepo.NewOutboxRepository(r.db)
+}
+
+func (r *Registry) GetInbox() inboxRepo.IInboxRepository {
+	return inboxRepo.NewInboxRepository(r.db)
+}
+
+func (r *Registry) GetPayment() paymentRepo.IPaymentRepository {
+	return paymentRepo.NewPaymentRepository(r.db)
+}
+
+func (r *Registry) GetWebhook() webhookRepo.IWebhookRepository {
+	return webhookRepo.NewWebhookRepository(r.db)
+}
+
+func (r *Registry) GetWebhookDelivery() webhookDeliveryRepo.IWebhookDeliveryRepository {
+	return webhookDeliveryRepo.NewWebhookDeliveryRepository(r.db)
+}
+
+func (r *Registry) GetOrderCustomer() orderCustomerRepo.IOrderCustomerRepository {
+	return orderCustomerRepo.NewOrderCustomerRepository(r.db)
+}
+
+func (r *Registry) GetScheduleReminder() scheduleReminderRepo.IScheduleReminderRepository {
+	return scheduleReminderRepo.NewScheduleReminderRepository(r.db)
+}
+
+func (r *Registry) GetCalendarFeed() calendarFeedRepo.ICalendarFeedRepository {
+	return calendarFeedRepo.NewCalendarFeedRepository(r.db)
+}
+
+func (r *Registry) GetExportJob() exportJobRepo.IExportJobRepository {
+	return exportJobRepo.NewExportJobRepository(r.db)
+}
+
+func (r *Registry) GetFieldScheduleRollup() fieldScheduleRollupRepo.IFieldScheduleRollupRepository {
+	return fieldScheduleRollupRepo.NewFieldScheduleRollupRepository(r.db)
+}
+
+func (r *Registry) GetTx() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleReminderRepository struct {
+	db *gorm.DB
+}
+
+type IScheduleReminderRepository interface {
+	FindAllUnreminded(context.Context, time.Time, time.Time, int, int) ([]models.FieldSchedule, error)
+	FindAllDue(context.Context, *gorm.DB, int) ([]models.ScheduleReminder, error)
+	Create(context.Context, []models.ScheduleReminder) error
+	Update(context.Context, *gorm.DB, *models.ScheduleReminder) error
+}
+
+func NewScheduleReminderRepository(db *gorm.DB) IScheduleReminderRepository {
+	return &ScheduleReminderRepository{db: db}
+}
+
+// FindAllUnreminded returns up to limit booked schedules starting after from
+// and no later than until which have no reminder at the offset yet for the
+// order holding them. Start times are compared in local time, the time zone
+// the dates and times of the schedules are kept in.
+func (s *ScheduleReminderRepository) FindAllUnreminded(
+	ctx context.Context,
+	from time.Time,
+	until time.Time,
+	offsetMinute int,
+	limit int,
+) ([]models.FieldSchedule, error) {
+	var fieldSchedules []models.FieldSchedule
+	err := s.db.WithContext(ctx).
+		Select("field_schedules.*").
+		Joins("JOIN times ON times.id = field_schedules.time_id").
+		Where("field_schedules.status = ?", constants.Booked).
+		Where("field_schedules.date + times.start_time > ?", from.In(time.Local).Format(time.DateTime)).
+		Where("field_schedules.date + times.start_time <= ?", until.In(time.Local).Format(time.DateTime)).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM schedule_reminders sr
+			WHERE sr.field_schedule_id = field_schedules.id
+			AND sr.order_id = field_schedules.order_id
+			AND sr.offset_minute = ?
+		)`, offsetMinute).
+		Order("field_schedules.id asc").
+		Limit(limit).
+		Find(&fieldSchedules).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return fieldSchedules, nil
+}
+
+// FindAllDue locks up to limit pending reminders whose next attempt is due,
+// with their schedule, skipping the ones another worker holds.
+func (s *ScheduleReminderRepository) FindAllDue(ctx context.Context, tx *gorm.DB, limit int) ([]models.ScheduleReminder, error) {
+	var reminders []models.ScheduleReminder
+	err := tx.WithContext(ctx).
+		Preload("FieldSchedule").
+		Preload("FieldSchedule.Field").
+		Preload("FieldSchedule.Time").
+		Where("status = ?", constants.ReminderPending).
+		Where("next_attempt_at <= ?", time.Now()).
+		Order("id asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&reminders).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return reminders, nil
+}
+
+// Create queues the reminders. A reminder that already exists is ignored, so
+// workers racing on the same schedule queue it once.
+func (s *ScheduleReminderRepository) Create(ctx context.Context, reminders []models.ScheduleReminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "field_schedule_id"}, {Name: "order_id"}, {Name: "offset_minute"}, {Name: "channel"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(&reminders, 100).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (s *ScheduleReminderRepository) Update(ctx context.Context, tx *gorm.DB, reminder *models.ScheduleReminder) error {
+	err := tx.WithContext(ctx).
+		Model(&models.ScheduleReminder{}).
+		Where("id = ?", reminder.ID).
+		Updates(map[string]interface{}{
+			"status":          reminder.Status,
+			"attempts":        reminder.Attempts,
+			"next_attempt_at": reminder.NextAttemptAt,
+			"last_error":      reminder.LastError,
+			"sent_at":         reminder.SentAt,
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
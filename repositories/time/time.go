@@ -21,7 +21,8 @@ type ITimeRepository interface {
 	FindAll(context.Context) ([]models.Time, error)
 	FindByUUID(context.Context, string) (*models.Time, error)
 	FindByID(context.Context, int) (*models.Time, error)
-	Create(context.Context, *models.Time) (*models.Time, error)
+	Create(context.Context, *gorm.DB, *models.Time) (*models.Time, error)
+	Upsert(context.Context, *gorm.DB, *models.Time) (*models.Time, error)
 }
 
 func NewTimeRepository(db *gorm.DB) ITimeRepository {
@@ -58,11 +59,35 @@ func (t *TimeRepository) FindByID(ctx context.Context, id int) (*models.Time, er
 	return &time, nil
 }
 
-func (t *TimeRepository) Create(ctx context.Context, req *models.Time) (*models.Time, error) {
+func (t *TimeRepository) Create(ctx context.Context, tx *gorm.DB, req *models.Time) (*models.Time, error) {
 	req.UUID = uuid.New()
-	err := t.db.WithContext(ctx).Create(&req).Error
+	err := tx.WithContext(ctx).Create(&req).Error
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errWrap.WrapError(errConstant.ErrSQLError), err)
 	}
 	return req, nil
 }
+
+// Upsert creates the time when it has no ID yet and otherwise overwrites its
+// start and end, in the caller's transaction.
+func (t *TimeRepository) Upsert(ctx context.Context, tx *gorm.DB, req *models.Time) (*models.Time, error) {
+	if req.ID == 0 {
+		req.UUID = uuid.New()
+		err := tx.WithContext(ctx).Create(req).Error
+		if err != nil {
+			return nil, errWrap.WrapError(errConstant.ErrSQLError)
+		}
+		return req, nil
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.Time{}).
+		Where("id = ?", req.ID).
+		Updates(map[string]interface{}{
+			"start_time": req.StartTime,
+			"end_time":   req.EndTime,
+		}).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
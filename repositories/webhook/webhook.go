@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	errConstant "field-service/constants/error"
+	errWebhook "field-service/constants/error/webhook"
+	"field-service/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+type IWebhookRepository interface {
+	FindAll(context.Context, *uuid.UUID) ([]models.Webhook, error)
+	FindByUUID(context.Context, string) (*models.Webhook, error)
+	FindAllSubscribed(context.Context, string, *models.Field, string) ([]models.Webhook, error)
+	Create(context.Context, *models.Webhook) (*models.Webhook, error)
+	Update(context.Context, string, *models.Webhook) (*models.Webhook, error)
+	Delete(context.Context, string) error
+	RecordSuccess(context.Context, *gorm.DB, uint) error
+	RecordFailure(context.Context, *gorm.DB, uint, int) (bool, error)
+}
+
+func NewWebhookRepository(db *gorm.DB) IWebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// FindAll returns the webhooks of the owner, or of everyone when ownerUUID is
+// nil.
+func (w *WebhookRepository) FindAll(ctx context.Context, ownerUUID *uuid.UUID) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	query := w.db.WithContext(ctx).Preload("Field")
+	if ownerUUID != nil {
+		query = query.Where("owner_uuid = ?", *ownerUUID)
+	}
+	err := query.Order("created_at desc").Find(&webhooks).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return webhooks, nil
+}
+
+func (w *WebhookRepository) FindByUUID(ctx context.Context, uuid string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := w.db.WithContext(ctx).
+		Preload("Field").
+		Where("uuid = ?", uuid).
+		First(&webhook).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errWebhook.ErrWebhookNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &webhook, nil
+}
+
+// FindAllSubscribed returns the active webhooks of the tenant subscribed to
+// the event type on the field, either directly or through its owner. The
+// tenant is explicit since the outbox relay runs unscoped.
+func (w *WebhookRepository) FindAllSubscribed(ctx context.Context, tenantID string, field *models.Field, eventType string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	query := w.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Where("active = ?", true).
+		Where("? = ANY(event_types)", eventType)
+	if field.OwnerUUID != nil {
+		query = query.Where("field_id = ? OR (field_id IS NULL AND owner_uuid = ?)", field.ID, *field.OwnerUUID)
+	} else {
+		query = query.Where("field_id = ?", field.ID)
+	}
+	err := query.Find(&webhooks).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return webhooks, nil
+}
+
+func (w *WebhookRepository) Create(ctx context.Context, req *models.Webhook) (*models.Webhook, error) {
+	req.UUID = uuid.New()
+	req.Active = true
+	err := w.db.WithContext(ctx).Create(req).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return req, nil
+}
+
+// Update changes the target and event types. Re-activating a webhook also
+// clears its failure streak.
+func (w *WebhookRepository) Update(ctx context.Context, uuid string, req *models.Webhook) (*models.Webhook, error) {
+	webhook, err := w.FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	webhook.TargetURL = req.TargetURL
+	webhook.EventTypes = req.EventTypes
+	if req.Active && !webhook.Active {
+		webhook.ConsecutiveFailures = 0
+		webhook.DisabledAt = nil
+	}
+	webhook.Active = req.Active
+	err = w.db.WithContext(ctx).
+		Model(&models.Webhook{}).
+		Where("id = ?", webhook.ID).
+		Updates(map[string]interface{}{
+			"target_url":           webhook.TargetURL,
+			"event_types":          webhook.EventTypes,
+			"active":               webhook.Active,
+			"consecutive_failures": webhook.ConsecutiveFailures,
+			"disabled_at":          webhook.DisabledAt,
+		}).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return webhook, nil
+}
+
+func (w *WebhookRepository) Delete(ctx context.Context, uuid string) error {
+	err := w.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&models.Webhook{}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (w *WebhookRepository) RecordSuccess(ctx context.Context, tx *gorm.DB, id uint) error {
+	err := tx.WithContext(ctx).
+		Model(&models.Webhook{}).
+		Where("id = ?", id).
+		Update("consecutive_failures", 0).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+// RecordFailure extends the webhook's failure streak and disables it once
+// the streak reaches threshold. It reports whether the webhook was disabled.
+func (w *WebhookRepository) RecordFailure(ctx context.Context, tx *gorm.DB, id uint, threshold int) (bool, error) {
+	err := tx.WithContext(ctx).
+		Model(&models.Webhook{}).
+		Where("id = ?", id).
+		Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+	if err != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	result := tx.WithContext(ctx).
+		Model(&models.Webhook{}).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Where("consecutive_failures >= ?", threshold).
+		Updates(map[string]interface{}{
+			"active":      false,
+			"disabled_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errWebhook "field-service/constants/error/webhook"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+type IWebhookDeliveryRepository interface {
+	FindAllWithPagination(context.Context, uint, *dto.WebhookDeliveryRequestParam) ([]models.WebhookDelivery, int64, error)
+	FindByUUID(context.Context, string) (*models.WebhookDelivery, error)
+	FindAllDue(context.Context, *gorm.DB, int) ([]models.WebhookDelivery, error)
+	Create(context.Context, []models.WebhookDelivery) error
+	Update(context.Context, *gorm.DB, *models.WebhookDelivery) error
+	Redeliver(context.Context, uint) error
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) IWebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (w *WebhookDeliveryRepository) FindAllWithPagination(
+	ctx context.Context,
+	webhookID uint,
+	param *dto.WebhookDeliveryRequestParam,
+) ([]models.WebhookDelivery, int64, error) {
+	var (
+		deliveries []models.WebhookDelivery
+		total      int64
+	)
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("webhook_id = ?", webhookID)
+		if param.Status != nil && *param.Status != "" {
+			db = db.Where("status = ?", *param.Status)
+		}
+		return db
+	}
+	err := w.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Scopes(filter).Count(&total).Error
+	if err != nil {
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	err = w.db.WithContext(ctx).
+		Scopes(filter).
+		Order("id desc").
+		Limit(param.Limit).
+		Offset((param.Page - 1) * param.Limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return deliveries, total, nil
+}
+
+func (w *WebhookDeliveryRepository) FindByUUID(ctx context.Context, uuid string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := w.db.WithContext(ctx).
+		Preload("Webhook").
+		Where("uuid = ?", uuid).
+		First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWrap.WrapError(errWebhook.ErrWebhookDeliveryNotFound)
+		}
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return &delivery, nil
+}
+
+// FindAllDue locks up to limit pending deliveries whose next attempt is due,
+// skipping the ones another dispatcher holds.
+func (w *WebhookDeliveryRepository) FindAllDue(ctx context.Context, tx *gorm.DB, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := tx.WithContext(ctx).
+		Preload("Webhook").
+		Where("status = ?", constants.WebhookDeliveryPending).
+		Where("next_attempt_at <= ?", time.Now()).
+		Order("id asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return deliveries, nil
+}
+
+// Create queues the deliveries. A delivery of an event the webhook already
+// has is ignored, so an event relayed twice is delivered once.
+func (w *WebhookDeliveryRepository) Create(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := w.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(&deliveries).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+func (w *WebhookDeliveryRepository) Update(ctx context.Context, tx *gorm.DB, delivery *models.WebhookDelivery) error {
+	err := tx.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"response_body":   delivery.ResponseBody,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
+
+// Redeliver queues the delivery again for an immediate attempt, with a fresh
+// attempt count.
+func (w *WebhookDeliveryRepository) Redeliver(ctx context.Context, id uint) error {
+	err := w.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          constants.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+	if err != nil {
+		return errWrap.WrapError(errConstant.ErrSQLError)
+	}
+	return nil
+}
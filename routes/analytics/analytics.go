@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IAnalyticsRoute interface {
+	Run()
+}
+
+func NewAnalyticsRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IAnalyticsRoute {
+	return &AnalyticsRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (a *AnalyticsRoute) Run() {
+	group := a.group.Group("/analytics")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("/utilization", middlewares.CheckPermission(constants.AnalyticsRead, a.client), a.controller.GetAnalytics().GetUtilization)
+	group.GET("/revenue", middlewares.CheckPermission(constants.AnalyticsRead, a.client), a.controller.GetAnalytics().GetRevenue)
+	group.GET("/peak", middlewares.CheckPermission(constants.AnalyticsRead, a.client), a.controller.GetAnalytics().GetPeak)
+}
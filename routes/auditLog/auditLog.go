@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IAuditLogRoute interface {
+	Run()
+}
+
+func NewAuditLogRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IAuditLogRoute {
+	return &AuditLogRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (a *AuditLogRoute) Run() {
+	group := a.group.Group("/audit-log")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.AuditRead, a.client), a.controller.GetAuditLog().GetAllWithPagination)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type ICalendarRoute interface {
+	Run()
+}
+
+func NewCalendarRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) ICalendarRoute {
+	return &CalendarRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (ca *CalendarRoute) Run() {
+	group := ca.group.Group("/calendar")
+	group.GET("/feed/:token", middlewares.AuthenticateWithoutToken(), middlewares.RateLimiter(constants.RateLimitPublic), ca.controller.GetCalendar().Feed)
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.CalendarSubscribe, ca.client), ca.controller.GetCalendar().GetAll)
+	group.POST("/field/:uuid", middlewares.CheckPermission(constants.CalendarManage, ca.client), ca.controller.GetCalendar().CreateFieldFeed)
+	group.POST("/bookings", middlewares.CheckPermission(constants.CalendarSubscribe, ca.client), ca.controller.GetCalendar().CreateBookingFeed)
+	group.DELETE("/delete/:uuid", middlewares.CheckPermission(constants.CalendarSubscribe, ca.client), ca.controller.GetCalendar().Delete)
+}
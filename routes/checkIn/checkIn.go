@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type ICheckInRoute interface {
+	Run()
+}
+
+func NewCheckInRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) ICheckInRoute {
+	return &CheckInRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (ci *CheckInRoute) Run() {
+	internal := ci.group.Group("/internal/check-in")
+	internal.Use(middlewares.AuthenticateInternal(), middlewares.RateLimiter(constants.RateLimitInternal))
+	internal.GET("/no-show/:uuid", ci.controller.GetCheckIn().GetNoShow)
+
+	group := ci.group.Group("/check-in")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("/code/:uuid", middlewares.CheckPermission(constants.BookingCode, ci.client), ci.controller.GetCheckIn().GetCode)
+	group.POST("", middlewares.CheckPermission(constants.BookingCheckIn, ci.client), ci.controller.GetCheckIn().CheckIn)
+	group.GET("/no-show/:uuid", middlewares.CheckPermission(constants.NoShowRead, ci.client), ci.controller.GetCheckIn().GetNoShow)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DailySheetRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IDailySheetRoute interface {
+	Run()
+}
+
+func NewDailySheetRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IDailySheetRoute {
+	return &DailySheetRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (d *DailySheetRoute) Run() {
+	group := d.group.Group("/field/schedule/daily-sheet")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.ScheduleSheet, d.client), d.controller.GetDailySheet().Render)
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IExportRoute interface {
+	Run()
+}
+
+func NewExportRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IExportRoute {
+	return &ExportRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (e *ExportRoute) Run() {
+	group := e.group.Group("/field/schedule/export")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.ScheduleExport, e.client), e.controller.GetExport().Export)
+	group.POST("/jobs", middlewares.CheckPermission(constants.ScheduleExport, e.client), e.controller.GetExport().CreateJob)
+	group.GET("/jobs/:uuid", middlewares.CheckPermission(constants.ScheduleExport, e.client), e.controller.GetExport().GetJob)
+}
@@ -29,24 +29,15 @@ func NewFieldRoute(group *gin.RouterGroup, controller controllers.IControllerReg
 
 func (f *FieldRoute) Run() {
 	group := f.group.Group("/field")
-	group.GET("", middlewares.AuthenticateWithoutToken(), f.controller.GetField().GetAllWithoutPagination)
-	group.GET("/:uuid", middlewares.AuthenticateWithoutToken(), f.controller.GetField().GetByUUID)
-	group.Use(middlewares.Authenticate())
-	group.GET("/pagination", middlewares.CheckRole([]string{
-		constants.Admin,
-		constants.Customer,
-	}, f.client), f.controller.GetField().GetAllWithPagination)
-	// group.GET("/pagination", f.controller.GetField().GetAllWithPagination)
-	group.POST("/create", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetField().Create)
-	// group.POST("/create", f.controller.GetField().Create)
-	group.PUT("/update/:uuid", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetField().Update)
-	// group.PUT("/update/:uuid", f.controller.GetField().Update)
-	group.DELETE("/delete/:uuid", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetField().Update)
-	// group.DELETE("/delete/:uuid", f.controller.GetField().Delete)
+	group.GET("", middlewares.AuthenticateWithoutToken(), middlewares.RateLimiter(constants.RateLimitPublic), f.controller.GetField().GetAllWithoutPagination)
+	group.GET("/:uuid", middlewares.AuthenticateWithoutToken(), middlewares.RateLimiter(constants.RateLimitPublic), f.controller.GetField().GetActiveByUUID)
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("/pagination", middlewares.CheckPermission(constants.FieldRead, f.client), f.controller.GetField().GetAllWithPagination)
+	group.GET("/detail/:uuid", middlewares.CheckPermission(constants.FieldRead, f.client), f.controller.GetField().GetByUUID)
+	group.POST("/create", middlewares.CheckPermission(constants.FieldCreate, f.client), f.controller.GetField().Create)
+	group.PUT("/update/:uuid", middlewares.CheckPermission(constants.FieldUpdate, f.client), f.controller.GetField().Update)
+	group.PATCH("/update-status/:uuid", middlewares.CheckPermission(constants.FieldUpdate, f.client), f.controller.GetField().UpdateStatus)
+	group.DELETE("/delete/:uuid", middlewares.CheckPermission(constants.FieldDelete, f.client), f.controller.GetField().Delete)
+	group.GET("/deleted", middlewares.CheckPermission(constants.FieldRestore, f.client), f.controller.GetField().GetAllDeleted)
+	group.PATCH("/restore/:uuid", middlewares.CheckPermission(constants.FieldRestore, f.client), f.controller.GetField().Restore)
 }
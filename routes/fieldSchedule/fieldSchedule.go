@@ -28,33 +28,24 @@ func NewFieldScheduleRoute(group *gin.RouterGroup, controller controllers.IContr
 }
 
 func (f *FieldScheduleRoute) Run() {
+	internal := f.group.Group("/internal/field/schedule")
+	internal.Use(middlewares.AuthenticateInternal(), middlewares.RateLimiter(constants.RateLimitInternal))
+	internal.PATCH("/update-status", f.controller.GetFieldSchedule().UpdateStatus)
+
+	public := f.group.Group("/field/schedule")
+	public.Use(middlewares.AuthenticateWithoutToken(), middlewares.RateLimiter(constants.RateLimitPublic))
+	public.GET("/stream/:uuid", f.controller.GetFieldSchedule().Stream)
+	public.GET("/ws/:uuid", f.controller.GetFieldSchedule().StreamWebSocket)
+
 	group := f.group.Group("/field/schedule")
-	group.PATCH("/update-status", middlewares.AuthenticateWithoutToken(), f.controller.GetFieldSchedule().UpdateStatus)
-	group.Use(middlewares.Authenticate())
-	group.GET("/pagination", middlewares.CheckRole([]string{
-		constants.Admin,
-		constants.Customer,
-	}, f.client), f.controller.GetFieldSchedule().GetAllWithPagination)
-	// group.GET("/pagination", f.controller.GetFieldSchedule().GetAllWithPagination)
-	group.GET("/:uuid", middlewares.CheckRole([]string{
-		constants.Admin,
-		constants.Customer,
-	}, f.client), f.controller.GetFieldSchedule().GetByUUID)
-	// group.GET("/:uuid", f.controller.GetFieldSchedule().GetByUUID)
-	group.POST("/generate-one-month", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetFieldSchedule().GenerateScheduleForOneMonth)
-	// group.POST("/generate-one-month", f.controller.GetFieldSchedule().GenerateScheduleForOneMonth)
-	group.POST("/create", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetFieldSchedule().Create)
-	// group.POST("/create", f.controller.GetFieldSchedule().Create)
-	group.PUT("/update", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetFieldSchedule().Update)
-	// group.PUT("/update/:uuid", f.controller.GetFieldSchedule().Update)
-	group.DELETE("/delete/:uuid", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetFieldSchedule().Delete)
-	// group.DELETE("/delete/:uuid", f.controller.GetFieldSchedule().Delete)
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("/pagination", middlewares.CheckPermission(constants.ScheduleRead, f.client), f.controller.GetFieldSchedule().GetAllWithPagination)
+	group.GET("/cursor", middlewares.CheckPermission(constants.ScheduleRead, f.client), f.controller.GetFieldSchedule().GetAllWithCursor)
+	group.GET("/board", middlewares.CheckPermission(constants.ScheduleHistory, f.client), f.controller.GetFieldSchedule().GetBoardAt)
+	group.GET("/:uuid", middlewares.CheckPermission(constants.ScheduleRead, f.client), f.controller.GetFieldSchedule().GetByUUID)
+	group.GET("/:uuid/history", middlewares.CheckPermission(constants.ScheduleHistory, f.client), f.controller.GetFieldSchedule().GetHistory)
+	group.POST("/generate-one-month", middlewares.CheckPermission(constants.ScheduleGenerate, f.client), f.controller.GetFieldSchedule().GenerateScheduleForOneMonth)
+	group.POST("/create", middlewares.CheckPermission(constants.ScheduleCreate, f.client), f.controller.GetFieldSchedule().Create)
+	group.PUT("/update/:uuid", middlewares.CheckPermission(constants.ScheduleUpdate, f.client), f.controller.GetFieldSchedule().Update)
+	group.DELETE("/delete/:uuid", middlewares.CheckPermission(constants.ScheduleDelete, f.client), f.controller.GetFieldSchedule().Delete)
 }
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImporterRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IImporterRoute interface {
+	Run()
+}
+
+func NewImporterRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IImporterRoute {
+	return &ImporterRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (i *ImporterRoute) Run() {
+	group := i.group.Group("/import")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.POST("", middlewares.CheckPermission(constants.ImportWrite, i.client), i.controller.GetImporter().Import)
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceWindowRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IMaintenanceWindowRoute interface {
+	Run()
+}
+
+func NewMaintenanceWindowRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IMaintenanceWindowRoute {
+	return &MaintenanceWindowRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (m *MaintenanceWindowRoute) Run() {
+	group := m.group.Group("/field/maintenance")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.MaintenanceRead, m.client), m.controller.GetMaintenanceWindow().GetAllByField)
+	group.GET("/:uuid", middlewares.CheckPermission(constants.MaintenanceRead, m.client), m.controller.GetMaintenanceWindow().GetByUUID)
+	group.POST("/create", middlewares.CheckPermission(constants.MaintenanceManage, m.client), m.controller.GetMaintenanceWindow().Create)
+	group.DELETE("/delete/:uuid", middlewares.CheckPermission(constants.MaintenanceManage, m.client), m.controller.GetMaintenanceWindow().Delete)
+}
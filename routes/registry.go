@@ -2,9 +2,18 @@ package routes
 
 import (
 	"field-service/clients"
+	analyticsRoute "field-service/routes/analytics"
+	auditLogRoute "field-service/routes/auditLog"
+	calendarRoute "field-service/routes/calendar"
+	checkInRoute "field-service/routes/checkIn"
+	dailySheetRoute "field-service/routes/dailySheet"
+	exportRoute "field-service/routes/export"
 	fieldRoute "field-service/routes/field"
 	fieldScheduleRoute "field-service/routes/fieldSchedule"
+	importerRoute "field-service/routes/importer"
+	maintenanceWindowRoute "field-service/routes/maintenanceWindow"
 	timeRoute "field-service/routes/time"
+	webhookRoute "field-service/routes/webhook"
 
 	"field-service/controllers"
 
@@ -41,8 +50,53 @@ func (r *Registry) timeRoute() timeRoute.ITimeRoute {
 	return timeRoute.NewTimeRoute(r.group, r.controller, r.client)
 }
 
+func (r *Registry) maintenanceWindowRoute() maintenanceWindowRoute.IMaintenanceWindowRoute {
+	return maintenanceWindowRoute.NewMaintenanceWindowRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) auditLogRoute() auditLogRoute.IAuditLogRoute {
+	return auditLogRoute.NewAuditLogRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) webhookRoute() webhookRoute.IWebhookRoute {
+	return webhookRoute.NewWebhookRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) checkInRoute() checkInRoute.ICheckInRoute {
+	return checkInRoute.NewCheckInRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) calendarRoute() calendarRoute.ICalendarRoute {
+	return calendarRoute.NewCalendarRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) importerRoute() importerRoute.IImporterRoute {
+	return importerRoute.NewImporterRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) exportRoute() exportRoute.IExportRoute {
+	return exportRoute.NewExportRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) analyticsRoute() analyticsRoute.IAnalyticsRoute {
+	return analyticsRoute.NewAnalyticsRoute(r.group, r.controller, r.client)
+}
+
+func (r *Registry) dailySheetRoute() dailySheetRoute.IDailySheetRoute {
+	return dailySheetRoute.NewDailySheetRoute(r.group, r.controller, r.client)
+}
+
 func (r *Registry) Serve() {
 	r.fieldRoute().Run()
 	r.fieldScheduleRoute().Run()
 	r.timeRoute().Run()
+	r.maintenanceWindowRoute().Run()
+	r.auditLogRoute().Run()
+	r.webhookRoute().Run()
+	r.checkInRoute().Run()
+	r.calendarRoute().Run()
+	r.importerRoute().Run()
+	r.exportRoute().Run()
+	r.analyticsRoute().Run()
+	r.dailySheetRoute().Run()
 }
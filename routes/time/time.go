@@ -29,17 +29,8 @@ func NewTimeRoute(group *gin.RouterGroup, controller controllers.IControllerRegi
 
 func (f *TimeRoute) Run() {
 	group := f.group.Group("/time")
-	group.Use(middlewares.Authenticate())
-	group.GET("", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetTime().GetAll)
-	// group.GET("", f.controller.GetTime().GetAll)
-	group.GET("/:uuid", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetTime().GetByUUID)
-	// group.GET("/:uuid", f.controller.GetTime().GetByUUID)
-	group.POST("/create", middlewares.CheckRole([]string{
-		constants.Admin,
-	}, f.client), f.controller.GetTime().Create)
-	// group.POST("/create", f.controller.GetTime().Create)
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.TimeRead, f.client), f.controller.GetTime().GetAll)
+	group.GET("/:uuid", middlewares.CheckPermission(constants.TimeRead, f.client), f.controller.GetTime().GetByUUID)
+	group.POST("/create", middlewares.CheckPermission(constants.TimeCreate, f.client), f.controller.GetTime().Create)
 }
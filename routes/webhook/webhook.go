@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"field-service/clients"
+	"field-service/constants"
+	"field-service/controllers"
+	"field-service/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRoute struct {
+	controller controllers.IControllerRegistry
+	client     clients.IClientRegistry
+	group      *gin.RouterGroup
+}
+
+type IWebhookRoute interface {
+	Run()
+}
+
+func NewWebhookRoute(group *gin.RouterGroup, controller controllers.IControllerRegistry, client clients.IClientRegistry) IWebhookRoute {
+	return &WebhookRoute{
+		controller: controller,
+		group:      group,
+		client:     client,
+	}
+}
+
+func (w *WebhookRoute) Run() {
+	group := w.group.Group("/webhook")
+	group.Use(middlewares.Authenticate(), middlewares.RateLimiter(constants.RateLimitAuthenticated))
+	group.GET("", middlewares.CheckPermission(constants.WebhookRead, w.client), w.controller.GetWebhook().GetAll)
+	group.GET("/:uuid", middlewares.CheckPermission(constants.WebhookRead, w.client), w.controller.GetWebhook().GetByUUID)
+	group.GET("/:uuid/deliveries", middlewares.CheckPermission(constants.WebhookRead, w.client), w.controller.GetWebhook().GetDeliveries)
+	group.POST("/create", middlewares.CheckPermission(constants.WebhookManage, w.client), w.controller.GetWebhook().Create)
+	group.PUT("/update/:uuid", middlewares.CheckPermission(constants.WebhookManage, w.client), w.controller.GetWebhook().Update)
+	group.DELETE("/delete/:uuid", middlewares.CheckPermission(constants.WebhookManage, w.client), w.controller.GetWebhook().Delete)
+	group.POST("/deliveries/:uuid/redeliver", middlewares.CheckPermission(constants.WebhookManage, w.client), w.controller.GetWebhook().Redeliver)
+}
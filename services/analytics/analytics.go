@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"field-service/common/policy"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errAnalytics "field-service/constants/error/analytics"
+	"field-service/domain/dto"
+	"field-service/repositories"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnalyticsService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type IAnalyticsService interface {
+	GetUtilization(context.Context, *dto.AnalyticsUtilizationRequestParam) ([]dto.AnalyticsRow, error)
+	GetRevenue(context.Context, *dto.AnalyticsRequestParam) ([]dto.AnalyticsRow, error)
+	GetPeak(context.Context, *dto.AnalyticsRequestParam) ([]dto.AnalyticsRow, error)
+}
+
+func NewAnalyticsService(repository repositories.IRepositoryRegistry) IAnalyticsService {
+	return &AnalyticsService{repository: repository}
+}
+
+// prepare checks the date range, fills in the default granularity and
+// returns the owner the figures are limited to, if any.
+func (a *AnalyticsService) prepare(ctx context.Context, param *dto.AnalyticsRequestParam, granularity string) (*uuid.UUID, error) {
+	startDate, _ := time.Parse(time.DateOnly, param.StartDate)
+	endDate, _ := time.Parse(time.DateOnly, param.EndDate)
+	if endDate.Before(startDate) {
+		return nil, errAnalytics.ErrInvalidAnalyticsRange
+	}
+	if endDate.Sub(startDate) >= constants.MaxAnalyticsRangeDays*24*time.Hour {
+		return nil, errAnalytics.ErrAnalyticsRangeTooLong
+	}
+	if param.Granularity == "" {
+		param.Granularity = granularity
+	}
+	if !policy.IsOwnScope(ctx) {
+		return nil, nil
+	}
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	return &user.UUID, nil
+}
+
+// withUtilization sets the utilization of every row, in percent rounded to
+// two decimals.
+func (a *AnalyticsService) withUtilization(rows []dto.AnalyticsRow) []dto.AnalyticsRow {
+	for i := range rows {
+		if rows[i].CapacityCount > 0 {
+			rows[i].Utilization = math.Round(float64(rows[i].BookedCount)*10000/float64(rows[i].CapacityCount)) / 100
+		}
+	}
+	return rows
+}
+
+func (a *AnalyticsService) GetUtilization(ctx context.Context, param *dto.AnalyticsUtilizationRequestParam) ([]dto.AnalyticsRow, error) {
+	ownerUUID, err := a.prepare(ctx, &param.AnalyticsRequestParam, constants.AnalyticsDay)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.repository.GetFieldScheduleRollup().Aggregate(ctx, &param.AnalyticsRequestParam, ownerUUID, param.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	return a.withUtilization(rows), nil
+}
+
+func (a *AnalyticsService) GetRevenue(ctx context.Context, param *dto.AnalyticsRequestParam) ([]dto.AnalyticsRow, error) {
+	ownerUUID, err := a.prepare(ctx, param, constants.AnalyticsMonth)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.repository.GetFieldScheduleRollup().Aggregate(ctx, param, ownerUUID, constants.AnalyticsByField)
+	if err != nil {
+		return nil, err
+	}
+	return a.withUtilization(rows), nil
+}
+
+// band tells whether a slot starting at the hour of the ISO weekday is peak.
+func (a *AnalyticsService) band(weekday int, hour int) string {
+	cfg := config.Config.Analytics
+	startHour, endHour := cfg.PeakStartHour, cfg.PeakEndHour
+	if startHour == 0 && endHour == 0 {
+		startHour, endHour = constants.DefaultAnalyticsPeakStartHour, constants.DefaultAnalyticsPeakEndHour
+	}
+	if (cfg.WeekendPeak && weekday >= 6) || (hour >= startHour && hour < endHour) {
+		return constants.AnalyticsPeak
+	}
+	return constants.AnalyticsOffPeak
+}
+
+// GetPeak compares the peak and off-peak slots of every period. The rollups
+// are summed by weekday and hour and folded into the two bands here, so a
+// change of peak hours applies to past periods too.
+func (a *AnalyticsService) GetPeak(ctx context.Context, param *dto.AnalyticsRequestParam) ([]dto.AnalyticsRow, error) {
+	ownerUUID, err := a.prepare(ctx, param, constants.AnalyticsMonth)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.repository.GetFieldScheduleRollup().Aggregate(
+		ctx, param, ownerUUID, constants.AnalyticsByWeekday, constants.AnalyticsByHour)
+	if err != nil {
+		return nil, err
+	}
+
+	bandRows := make([]dto.AnalyticsRow, 0)
+	index := map[[2]string]int{}
+	for _, row := range rows {
+		key := [2]string{row.Period, a.band(*row.Weekday, *row.Hour)}
+		i, ok := index[key]
+		if !ok {
+			i = len(bandRows)
+			index[key] = i
+			bandRows = append(bandRows, dto.AnalyticsRow{Period: key[0], Band: key[1]})
+		}
+		bandRows[i].SlotCount += row.SlotCount
+		bandRows[i].CapacityCount += row.CapacityCount
+		bandRows[i].BookedCount += row.BookedCount
+		bandRows[i].BookedMinutes += row.BookedMinutes
+		bandRows[i].Revenue += row.Revenue
+	}
+	return a.withUtilization(bandRows), nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/policy"
+	"field-service/common/util"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type IAuditLogService interface {
+	GetAllWithPagination(context.Context, *dto.AuditLogRequestParam) (*util.PaginationResult, error)
+	Record(context.Context, *gorm.DB, ...dto.AuditEntry) error
+}
+
+func NewAuditLogService(repository repositories.IRepositoryRegistry) IAuditLogService {
+	return &AuditLogService{repository: repository}
+}
+
+// ignoredAuditKeys are bookkeeping columns that change on every write and
+// would only add noise to the diff.
+var ignoredAuditKeys = map[string]bool{
+	"ID":        true,
+	"TenantID":  true,
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+// snapshot flattens an entity into its non-null columns. Associations (nested
+// objects and lists of objects) are left out.
+func (a *AuditLogService) snapshot(entity interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	if entity == nil || reflect.ValueOf(entity).IsZero() {
+		return result
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return result
+	}
+	var columns map[string]interface{}
+	if json.Unmarshal(data, &columns) != nil {
+		return result
+	}
+	for key, value := range columns {
+		if ignoredAuditKeys[key] || value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			continue
+		case []interface{}:
+			if len(v) > 0 {
+				if _, isObject := v[0].(map[string]interface{}); isObject {
+					continue
+				}
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// diff keeps only the columns whose value differs between the snapshots.
+func (a *AuditLogService) diff(before, after interface{}) map[string]dto.AuditChange {
+	beforeColumns := a.snapshot(before)
+	afterColumns := a.snapshot(after)
+	changes := map[string]dto.AuditChange{}
+	for key, value := range beforeColumns {
+		if !reflect.DeepEqual(value, afterColumns[key]) {
+			changes[key] = dto.AuditChange{Before: value, After: afterColumns[key]}
+		}
+	}
+	for key, value := range afterColumns {
+		if _, ok := beforeColumns[key]; !ok {
+			changes[key] = dto.AuditChange{Before: nil, After: value}
+		}
+	}
+	return changes
+}
+
+// Record appends an audit record for every entry in the caller's transaction,
+// so the change and its audit trail are committed or rolled back together.
+func (a *AuditLogService) Record(ctx context.Context, tx *gorm.DB, entries ...dto.AuditEntry) error {
+	requestID, _ := ctx.Value(constants.RequestID).(string)
+	clientIP, _ := ctx.Value(constants.ClientIP).(string)
+	auditLogs := make([]models.AuditLog, 0, len(entries))
+	for _, entry := range entries {
+		changes, err := json.Marshal(a.diff(entry.Before, entry.After))
+		if err != nil {
+			return err
+		}
+		auditLog := models.AuditLog{
+			UUID:       uuid.New(),
+			Action:     entry.Action,
+			Entity:     entry.Entity,
+			EntityUUID: entry.EntityUUID,
+			Changes:    string(changes),
+			RequestID:  requestID,
+			IPAddress:  clientIP,
+		}
+		actor := policy.ActorFromContext(ctx)
+		auditLog.ActorUUID = actor.UUID
+		auditLog.ActorName = actor.Name
+		auditLog.ActorRole = actor.Role
+		auditLogs = append(auditLogs, auditLog)
+	}
+	return a.repository.GetAuditLog().Create(ctx, tx, auditLogs)
+}
+
+func (a *AuditLogService) GetAllWithPagination(ctx context.Context, param *dto.AuditLogRequestParam) (*util.PaginationResult, error) {
+	auditLogs, total, err := a.repository.GetAuditLog().FindAllWithPagination(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	auditLogResults := make([]dto.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogResults = append(auditLogResults, dto.AuditLogResponse{
+			UUID:      auditLog.UUID,
+			ActorID:   auditLog.ActorUUID,
+			ActorName: auditLog.ActorName,
+			ActorRole: auditLog.ActorRole,
+			Action:    auditLog.Action,
+			Entity:    auditLog.Entity,
+			EntityID:  auditLog.EntityUUID,
+			Changes:   json.RawMessage(auditLog.Changes),
+			RequestID: auditLog.RequestID,
+			IPAddress: auditLog.IPAddress,
+			CreatedAt: auditLog.CreatedAt,
+		})
+	}
+	pagination := &util.PaginationParam{
+		Count: total,
+		Page:  param.Page,
+		Limit: param.Limit,
+		Data:  auditLogResults,
+	}
+	response := util.GeneratePagination(*pagination)
+	return &response, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/event"
+	"field-service/common/pubsub"
+	"field-service/constants"
+	errField "field-service/constants/error/field"
+	"field-service/domain/dto"
+	"field-service/repositories"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type AvailabilityService struct {
+	repository repositories.IRepositoryRegistry
+	pubSub     pubsub.IPubSub
+}
+
+// IAvailabilityService streams schedule status changes per field and date.
+// The outbox relay publishes to it, so updates follow committed changes.
+type IAvailabilityService interface {
+	Subscribe(context.Context, string, string) (pubsub.ISubscription, error)
+	Publish(context.Context, event.Event) error
+}
+
+func NewAvailabilityService(repository repositories.IRepositoryRegistry, pubSub pubsub.IPubSub) IAvailabilityService {
+	return &AvailabilityService{repository: repository, pubSub: pubSub}
+}
+
+func (a *AvailabilityService) channel(fieldUUID string, date string) string {
+	return fmt.Sprintf("availability:%s:%s", fieldUUID, date)
+}
+
+func (a *AvailabilityService) Subscribe(ctx context.Context, fieldUUID string, date string) (pubsub.ISubscription, error) {
+	field, err := a.repository.GetField().FindByUUID(ctx, fieldUUID)
+	if err != nil {
+		return nil, err
+	}
+	if field.Status != constants.FieldActive {
+		return nil, errField.ErrFieldNotActive
+	}
+	return a.pubSub.Subscribe(ctx, a.channel(field.UUID.String(), date))
+}
+
+// Publish forwards a schedule event to the streams of its field and date.
+// Streams are best effort: a failure is logged rather than holding up the
+// outbox.
+func (a *AvailabilityService) Publish(ctx context.Context, ev event.Event) error {
+	data, err := json.Marshal(ev.Payload)
+	if err != nil {
+		return err
+	}
+	var scheduleEvent dto.FieldScheduleEvent
+	if json.Unmarshal(data, &scheduleEvent) != nil || scheduleEvent.FieldUUID == uuid.Nil {
+		return nil
+	}
+	message, err := json.Marshal(dto.FieldScheduleAvailabilityEvent{
+		UUID:       scheduleEvent.ScheduleUUID,
+		Date:       scheduleEvent.Date,
+		Time:       scheduleEvent.StartTime,
+		Status:     constants.FieldScheduleStatusName(scheduleEvent.Status),
+		Event:      ev.Type,
+		OccurredAt: ev.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+	err = a.pubSub.Publish(ctx, a.channel(scheduleEvent.FieldUUID.String(), scheduleEvent.Date), message)
+	if err != nil {
+		logrus.Errorf("failed to stream availability of schedule %s: %v", scheduleEvent.ScheduleUUID, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/event"
+	"field-service/common/pubsub"
+	"field-service/common/tenant"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestService(t *testing.T) (IAvailabilityService, *gorm.DB, context.Context) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	if err = db.AutoMigrate(&models.Field{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), "tenant-a")
+	return NewAvailabilityService(repositories.NewRepositoryRegistry(db), pubsub.NewMemoryPubSub()), db, ctx
+}
+
+func createField(t *testing.T, db *gorm.DB, ctx context.Context, status constants.FieldStatus) *models.Field {
+	t.Helper()
+	field := &models.Field{UUID: uuid.New(), Code: "A1", Name: "Field A1", Status: status, Images: []string{}}
+	if err := db.WithContext(ctx).Create(field).Error; err != nil {
+		t.Fatalf("failed to create field: %v", err)
+	}
+	return field
+}
+
+func scheduleEvent(field *models.Field, date string) event.Event {
+	return event.Event{
+		ID:   uuid.NewString(),
+		Type: constants.FieldScheduleBooked,
+		Payload: json.RawMessage(mustMarshal(dto.FieldScheduleEvent{
+			ScheduleUUID: uuid.New(),
+			FieldUUID:    field.UUID,
+			Date:         date,
+			StartTime:    "08:00:00",
+			Status:       string(constants.BookedString),
+		})),
+		OccurredAt: time.Now(),
+	}
+}
+
+func mustMarshal(value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func receive(t *testing.T, subscription pubsub.ISubscription) *dto.FieldScheduleAvailabilityEvent {
+	t.Helper()
+	select {
+	case message := <-subscription.Messages():
+		var availability dto.FieldScheduleAvailabilityEvent
+		if err := json.Unmarshal(message, &availability); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+		return &availability
+	case <-time.After(time.Second):
+		t.Fatal("no update received")
+		return nil
+	}
+}
+
+func TestPublishFansOutToStreamsOfFieldAndDate(t *testing.T) {
+	service, db, ctx := newTestService(t)
+	field := createField(t, db, ctx, constants.FieldActive)
+	first, err := service.Subscribe(ctx, field.UUID.String(), "2026-10-20")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer first.Close()
+	second, _ := service.Subscribe(ctx, field.UUID.String(), "2026-10-20")
+	defer second.Close()
+	otherDate, _ := service.Subscribe(ctx, field.UUID.String(), "2026-10-21")
+	defer otherDate.Close()
+
+	ev := scheduleEvent(field, "2026-10-20")
+	if err = service.Publish(ctx, ev); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	for _, subscription := range []pubsub.ISubscription{first, second} {
+		update := receive(t, subscription)
+		if update.Status != constants.BookedString || update.Event != constants.FieldScheduleBooked || update.Time != "08:00:00" {
+			t.Errorf("update = %+v, want the booked slot", update)
+		}
+	}
+	select {
+	case message := <-otherDate.Messages():
+		t.Errorf("stream of another date got %s", message)
+	default:
+	}
+}
+
+func TestSubscribeRejectsInactiveField(t *testing.T) {
+	service, db, ctx := newTestService(t)
+	field := createField(t, db, ctx, constants.FieldInactive)
+
+	if _, err := service.Subscribe(ctx, field.UUID.String(), "2026-10-20"); err == nil {
+		t.Error("subscribed to an inactive field")
+	}
+}
+
+func TestPublishIgnoresOtherEvents(t *testing.T) {
+	service, _, ctx := newTestService(t)
+
+	err := service.Publish(ctx, event.Event{ID: "1", Type: "order.created", Payload: map[string]string{"orderID": "1"}})
+	if err != nil {
+		t.Errorf("err = %v, want events without a field ignored", err)
+	}
+}
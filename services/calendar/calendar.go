@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"field-service/common/calendar"
+	"field-service/common/policy"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errCalendar "field-service/constants/error/calendar"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CalendarService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type ICalendarService interface {
+	GetAll(context.Context) ([]dto.CalendarFeedResponse, error)
+	CreateFieldFeed(context.Context, string) (*dto.CalendarFeedResponse, error)
+	CreateBookingFeed(context.Context) (*dto.CalendarFeedResponse, error)
+	Delete(context.Context, string) error
+	Render(context.Context, string) ([]byte, error)
+}
+
+func NewCalendarService(repository repositories.IRepositoryRegistry) ICalendarService {
+	return &CalendarService{repository: repository}
+}
+
+func (c *CalendarService) generateToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func (c *CalendarService) toResponse(feed *models.CalendarFeed) dto.CalendarFeedResponse {
+	response := dto.CalendarFeedResponse{
+		UUID:      feed.UUID,
+		Type:      feed.Type,
+		URL:       fmt.Sprintf("%s/api/v1/calendar/feed/%s.ics", strings.TrimSuffix(config.Config.Calendar.BaseURL, "/"), feed.Token),
+		CreatedAt: feed.CreatedAt,
+	}
+	if feed.Field != nil {
+		response.FieldID = &feed.Field.UUID
+		response.FieldName = feed.Field.Name
+	}
+	return response
+}
+
+func (c *CalendarService) GetAll(ctx context.Context) ([]dto.CalendarFeedResponse, error) {
+	var ownerUUID *uuid.UUID
+	if policy.IsOwnScope(ctx) {
+		user := policy.UserFromContext(ctx)
+		if user == nil {
+			return nil, errConstant.ErrForbidden
+		}
+		ownerUUID = &user.UUID
+	}
+	feeds, err := c.repository.GetCalendarFeed().FindAll(ctx, ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+	feedResults := make([]dto.CalendarFeedResponse, 0, len(feeds))
+	for _, feed := range feeds {
+		feedResults = append(feedResults, c.toResponse(&feed))
+	}
+	return feedResults, nil
+}
+
+func (c *CalendarService) create(ctx context.Context, feed *models.CalendarFeed) (*dto.CalendarFeedResponse, error) {
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	token, err := c.generateToken()
+	if err != nil {
+		return nil, err
+	}
+	feed.Token = token
+	feed.OwnerUUID = user.UUID
+	feed, err = c.repository.GetCalendarFeed().Create(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+	response := c.toResponse(feed)
+	return &response, nil
+}
+
+// CreateFieldFeed hands out a new feed of the field's booked and maintenance
+// slots.
+func (c *CalendarService) CreateFieldFeed(ctx context.Context, fieldUUID string) (*dto.CalendarFeedResponse, error) {
+	field, err := c.repository.GetField().FindByUUID(ctx, fieldUUID)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	return c.create(ctx, &models.CalendarFeed{
+		Type:    constants.CalendarFeedField,
+		FieldID: &field.ID,
+		Field:   field,
+	})
+}
+
+// CreateBookingFeed hands out a new feed of the user's own bookings.
+func (c *CalendarService) CreateBookingFeed(ctx context.Context) (*dto.CalendarFeedResponse, error) {
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	customerUUID := user.UUID
+	return c.create(ctx, &models.CalendarFeed{
+		Type:         constants.CalendarFeedBookings,
+		CustomerUUID: &customerUUID,
+	})
+}
+
+// Delete revokes the feed; its URL stops working at once.
+func (c *CalendarService) Delete(ctx context.Context, uuid string) error {
+	feed, err := c.repository.GetCalendarFeed().FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	err = policy.CheckOwnership(ctx, &feed.OwnerUUID)
+	if err != nil {
+		return err
+	}
+	return c.repository.GetCalendarFeed().Delete(ctx, uuid)
+}
+
+// dateRange returns the first and last date a feed carries slots for.
+func (c *CalendarService) dateRange() (string, string) {
+	pastDays := config.Config.Calendar.PastDays
+	if pastDays <= 0 {
+		pastDays = constants.DefaultCalendarPastDays
+	}
+	futureDays := config.Config.Calendar.FutureDays
+	if futureDays <= 0 {
+		futureDays = constants.DefaultCalendarFutureDays
+	}
+	today := time.Now()
+	return today.AddDate(0, 0, -pastDays).Format(time.DateOnly), today.AddDate(0, 0, futureDays).Format(time.DateOnly)
+}
+
+// event turns a schedule into a calendar event whose UID is derived from the
+// schedule UUID, so a moved or changed slot updates the event in place.
+func (c *CalendarService) event(fieldSchedule *models.FieldSchedule, summary string, description string) (calendar.Event, error) {
+	date := fieldSchedule.Date.Format(time.DateOnly)
+	start, err := time.ParseInLocation(time.DateTime, fmt.Sprintf("%s %s", date, fieldSchedule.Time.StartTime), time.Local)
+	if err != nil {
+		return calendar.Event{}, err
+	}
+	end, err := time.ParseInLocation(time.DateTime, fmt.Sprintf("%s %s", date, fieldSchedule.Time.EndTime), time.Local)
+	if err != nil {
+		return calendar.Event{}, err
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	event := calendar.Event{
+		UID:         fmt.Sprintf("%s@%s", fieldSchedule.UUID, config.Config.AppName),
+		Start:       start,
+		End:         end,
+		Summary:     summary,
+		Description: description,
+		Location:    fieldSchedule.Field.Name,
+	}
+	if fieldSchedule.UpdatedAt != nil {
+		event.LastModified = *fieldSchedule.UpdatedAt
+	}
+	return event, nil
+}
+
+// Render builds the ICS document of the feed the token grants access to. The
+// token may carry the .ics extension of the feed URL.
+func (c *CalendarService) Render(ctx context.Context, token string) ([]byte, error) {
+	token = strings.TrimSuffix(token, ".ics")
+	feed, err := c.repository.GetCalendarFeed().FindByToken(tenant.Unscoped(ctx), token)
+	if err != nil {
+		return nil, err
+	}
+	ctx = tenant.WithTenant(ctx, feed.TenantID)
+	from, until := c.dateRange()
+
+	document := calendar.Calendar{Location: time.Local}
+	var fieldSchedules []models.FieldSchedule
+	switch feed.Type {
+	case constants.CalendarFeedField:
+		if feed.Field == nil || feed.FieldID == nil {
+			return nil, errCalendar.ErrCalendarFeedNotFound
+		}
+		document.Name = feed.Field.Name
+		statuses := append([]constants.FieldScheduleStatus{constants.Maintenance}, constants.BookingStatuses...)
+		fieldSchedules, err = c.repository.GetFieldSchedule().FindAllByFieldIDAndStatuses(ctx, *feed.FieldID, statuses, from, until)
+	case constants.CalendarFeedBookings:
+		if feed.CustomerUUID == nil {
+			return nil, errCalendar.ErrCalendarFeedNotFound
+		}
+		document.Name = "My bookings"
+		fieldSchedules, err = c.repository.GetFieldSchedule().FindAllBookedByCustomerUUID(ctx, *feed.CustomerUUID, from, until)
+	default:
+		return nil, errCalendar.ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	document.Events = make([]calendar.Event, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		summary := fmt.Sprintf("%s: %s", fieldSchedule.Status.GetStatusString(), fieldSchedule.Field.Name)
+		description := ""
+		if feed.Type == constants.CalendarFeedField && fieldSchedule.OrderID != "" {
+			description = fmt.Sprintf("Order %s", fieldSchedule.OrderID)
+		}
+		if feed.Type == constants.CalendarFeedBookings {
+			summary = fmt.Sprintf("Booking: %s", fieldSchedule.Field.Name)
+		}
+		event, err := c.event(&fieldSchedule, summary, description)
+		if err != nil {
+			return nil, err
+		}
+		document.Events = append(document.Events, event)
+	}
+	return document.Render(), nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"field-service/common/policy"
+	"field-service/common/tenant"
+	"field-service/common/util"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errCheckIn "field-service/constants/error/checkIn"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	auditLogService "field-service/services/auditLog"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+const qrCodeSize = 256
+
+type CheckInService struct {
+	repository repositories.IRepositoryRegistry
+	audit      auditLogService.IAuditLogService
+}
+
+type ICheckInService interface {
+	GetCode(context.Context, string) (*dto.CheckInCodeResponse, error)
+	CheckIn(context.Context, *dto.CheckInRequest) (*dto.FieldScheduleResponse, error)
+	GetNoShow(context.Context, string) (*dto.CustomerNoShowResponse, error)
+}
+
+func NewCheckInService(repository repositories.IRepositoryRegistry, audit auditLogService.IAuditLogService) ICheckInService {
+	return &CheckInService{repository: repository, audit: audit}
+}
+
+// window returns when check-in of a schedule opens and closes. After it
+// closes the booking is marked NoShow.
+func (c *CheckInService) window(fieldSchedule *models.FieldSchedule) (time.Time, time.Time, error) {
+	startAt, err := time.ParseInLocation(
+		time.DateTime,
+		fmt.Sprintf("%s %s", fieldSchedule.Date.Format(time.DateOnly), fieldSchedule.Time.StartTime),
+		time.Local,
+	)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	early := config.Config.CheckIn.EarlyMinutes
+	if early <= 0 {
+		early = constants.DefaultCheckInEarlyMinutes
+	}
+	noShowAfter := config.Config.CheckIn.NoShowAfterMinutes
+	if noShowAfter <= 0 {
+		noShowAfter = constants.DefaultNoShowAfterMinutes
+	}
+	return startAt.Add(-time.Duration(early) * time.Minute), startAt.Add(time.Duration(noShowAfter) * time.Minute), nil
+}
+
+func (c *CheckInService) claims(fieldSchedule *models.FieldSchedule) dto.CheckInClaims {
+	return dto.CheckInClaims{
+		ScheduleUUID: fieldSchedule.UUID,
+		FieldUUID:    fieldSchedule.Field.UUID,
+		OrderID:      fieldSchedule.OrderID,
+		Date:         fieldSchedule.Date.Format(time.DateOnly),
+		StartTime:    fieldSchedule.Time.StartTime,
+		EndTime:      fieldSchedule.Time.EndTime,
+	}
+}
+
+// sign encodes the claims as "<version>.<claims>.<signature>", where the
+// signature is the HMAC-SHA256 of "<version>.<claims>".
+func (c *CheckInService) sign(claims dto.CheckInClaims) (string, error) {
+	secretKey := config.Config.CheckIn.SecretKey
+	if secretKey == "" {
+		return "", errCheckIn.ErrCheckInNotConfigured
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := fmt.Sprintf("%s.%s", constants.CheckInCodeVersion, base64.RawURLEncoding.EncodeToString(data))
+	return fmt.Sprintf("%s.%s", payload, util.GenerateHMACSHA256(secretKey, payload)), nil
+}
+
+func (c *CheckInService) verify(code string) (*dto.CheckInClaims, error) {
+	secretKey := config.Config.CheckIn.SecretKey
+	if secretKey == "" {
+		return nil, errCheckIn.ErrCheckInNotConfigured
+	}
+	index := strings.LastIndex(code, ".")
+	if index < 0 || !strings.HasPrefix(code, constants.CheckInCodeVersion+".") {
+		return nil, errCheckIn.ErrCheckInCodeInvalid
+	}
+	payload, signature := code[:index], code[index+1:]
+	if !hmac.Equal([]byte(signature), []byte(util.GenerateHMACSHA256(secretKey, payload))) {
+		return nil, errCheckIn.ErrCheckInCodeInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(payload, constants.CheckInCodeVersion+"."))
+	if err != nil {
+		return nil, errCheckIn.ErrCheckInCodeInvalid
+	}
+	var claims dto.CheckInClaims
+	if json.Unmarshal(data, &claims) != nil {
+		return nil, errCheckIn.ErrCheckInCodeInvalid
+	}
+	return &claims, nil
+}
+
+// GetCode returns the signed check-in code of a booked schedule, and the same
+// code as a PNG QR code data URL. A customer only gets the codes of their own
+// bookings.
+func (c *CheckInService) GetCode(ctx context.Context, uuid string) (*dto.CheckInCodeResponse, error) {
+	fieldSchedule, err := c.repository.GetFieldSchedule().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fieldSchedule.Status != constants.Booked {
+		return nil, errCheckIn.ErrCheckInNotBooked
+	}
+	if policy.IsOwnScope(ctx) {
+		tenantID, _ := tenant.FromContext(ctx)
+		customer, err := c.repository.GetOrderCustomer().FindByOrderID(ctx, c.repository.GetTx(), tenantID, fieldSchedule.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if customer == nil {
+			return nil, errConstant.ErrForbidden
+		}
+		err = policy.CheckOwnership(ctx, &customer.CustomerUUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := c.sign(c.claims(fieldSchedule))
+	if err != nil {
+		return nil, err
+	}
+	png, err := qrcode.Encode(code, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	opensAt, closesAt, err := c.window(fieldSchedule)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CheckInCodeResponse{
+		Code:      code,
+		QRCode:    "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		OpensAt:   opensAt,
+		ClosesAt:  closesAt,
+		FieldName: fieldSchedule.Field.Name,
+		Date:      fieldSchedule.Date.Format(time.DateOnly),
+		Time:      fmt.Sprintf("%s - %s", fieldSchedule.Time.StartTime, fieldSchedule.Time.EndTime),
+	}, nil
+}
+
+// CheckIn verifies the scanned code against the field the staff is at and
+// the booking as it is now, then marks the booking CheckedIn. A code issued
+// before the booking was moved or rebooked no longer matches and is refused.
+func (c *CheckInService) CheckIn(ctx context.Context, request *dto.CheckInRequest) (*dto.FieldScheduleResponse, error) {
+	claims, err := c.verify(request.Code)
+	if err != nil {
+		return nil, err
+	}
+	fieldSchedule, err := c.repository.GetFieldSchedule().FindByUUID(ctx, claims.ScheduleUUID.String())
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, fieldSchedule.Field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.FieldUUID.String() != request.FieldID || fieldSchedule.Field.UUID.String() != request.FieldID {
+		return nil, errCheckIn.ErrCheckInWrongField
+	}
+	switch fieldSchedule.Status {
+	case constants.Booked:
+	case constants.CheckedIn:
+		return nil, errCheckIn.ErrAlreadyCheckedIn
+	default:
+		return nil, errCheckIn.ErrCheckInNotBooked
+	}
+	if c.claims(fieldSchedule) != *claims {
+		return nil, errCheckIn.ErrCheckInCodeOutdated
+	}
+	opensAt, closesAt, err := c.window(fieldSchedule)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(opensAt) {
+		return nil, errCheckIn.ErrCheckInTooEarly
+	}
+	if now.After(closesAt) {
+		return nil, errCheckIn.ErrCheckInClosed
+	}
+
+	checkedIn := *fieldSchedule
+	checkedIn.Status = constants.CheckedIn
+	err = c.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := c.repository.GetFieldSchedule().UpdateStatus(ctx, tx, constants.Booked, constants.CheckedIn, fieldSchedule.UUID.String(), fieldSchedule.OrderID)
+		if txErr != nil {
+			return txErr
+		}
+		return c.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditCheckIn,
+			Entity:     constants.AuditEntityFieldSchedule,
+			EntityUUID: fieldSchedule.UUID,
+			Before:     fieldSchedule,
+			After:      &checkedIn,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FieldScheduleResponse{
+		UUID:         checkedIn.UUID,
+		FieldName:    checkedIn.Field.Name,
+		PricePerHour: checkedIn.Field.PricePerHour,
+		Date:         checkedIn.Date.Format(time.DateOnly),
+		Status:       checkedIn.Status.GetStatusString(),
+		Time:         fmt.Sprintf("%s - %s", checkedIn.Time.StartTime, checkedIn.Time.EndTime),
+		CreatedAt:    checkedIn.CreatedAt,
+		UpdatedAt:    checkedIn.UpdatedAt,
+	}, nil
+}
+
+// GetNoShow counts the customer's bookings that were not checked in, for
+// other services to base booking policies on.
+func (c *CheckInService) GetNoShow(ctx context.Context, customerUUID string) (*dto.CustomerNoShowResponse, error) {
+	parsedUUID, err := uuid.Parse(customerUUID)
+	if err != nil {
+		return nil, errCheckIn.ErrInvalidCustomerID
+	}
+	err = policy.CheckOwnership(ctx, &parsedUUID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.repository.GetFieldSchedule().CountNoShowByCustomerUUID(ctx, parsedUUID.String())
+	if err != nil {
+		return nil, err
+	}
+	result.CustomerUUID = parsedUUID
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"field-service/common/dailysheet"
+	"field-service/common/policy"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	"field-service/domain/dto"
+	"field-service/repositories"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DailySheetService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type IDailySheetService interface {
+	Render(context.Context, *dto.DailySheetRequestParam) ([]byte, error)
+}
+
+func NewDailySheetService(repository repositories.IRepositoryRegistry) IDailySheetService {
+	return &DailySheetService{repository: repository}
+}
+
+// clock trims the seconds off a time of day.
+func (d *DailySheetService) clock(value string) string {
+	if len(value) > 5 {
+		return value[:5]
+	}
+	return value
+}
+
+// venueName is the configured name of the venue of the tenant, or the tenant
+// ID when the venue has no name configured.
+func (d *DailySheetService) venueName(ctx context.Context) string {
+	tenantID, _ := tenant.FromContext(ctx)
+	if name := config.Config.VenueNames[tenantID]; name != "" {
+		return name
+	}
+	return tenantID
+}
+
+// Render lays out the slots of the venue's fields on the date as a PDF, one
+// page per field with schedules on that date. Partners only get the pages of
+// their own fields.
+func (d *DailySheetService) Render(ctx context.Context, param *dto.DailySheetRequestParam) ([]byte, error) {
+	var ownerUUID *uuid.UUID
+	if policy.IsOwnScope(ctx) {
+		user := policy.UserFromContext(ctx)
+		if user == nil {
+			return nil, errConstant.ErrForbidden
+		}
+		ownerUUID = &user.UUID
+	}
+	date, _ := time.ParseInLocation(time.DateOnly, param.Date, time.Local)
+	filter := dto.FieldScheduleExportFilter{
+		FieldScheduleFilterParam: dto.FieldScheduleFilterParam{
+			FieldID:   param.FieldID,
+			StartDate: &param.Date,
+			EndDate:   &param.Date,
+		},
+	}
+
+	pages := make([]dailysheet.Page, 0)
+	pageIndex := map[string]int{}
+	err := d.repository.GetFieldSchedule().StreamForExport(ctx, &filter, ownerUUID, func(row *dto.FieldScheduleExportRow) error {
+		key := row.FieldCode + "\x00" + row.FieldName
+		i, ok := pageIndex[key]
+		if !ok {
+			i = len(pages)
+			pageIndex[key] = i
+			pages = append(pages, dailysheet.Page{FieldCode: row.FieldCode, FieldName: row.FieldName})
+		}
+		pages[i].Slots = append(pages[i].Slots, dailysheet.Slot{
+			StartTime:   d.clock(row.StartTime),
+			EndTime:     d.clock(row.EndTime),
+			Status:      string(row.Status.GetStatusString()),
+			BookerName:  row.BookerName,
+			BookerPhone: row.BookerPhone,
+			CheckedIn:   row.Status == constants.CheckedIn,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		if !strings.EqualFold(pages[i].FieldName, pages[j].FieldName) {
+			return strings.ToLower(pages[i].FieldName) < strings.ToLower(pages[j].FieldName)
+		}
+		return pages[i].FieldCode < pages[j].FieldCode
+	})
+
+	sheet := dailysheet.Sheet{
+		Venue:     d.venueName(ctx),
+		Date:      date,
+		PrintedAt: time.Now(),
+		Pages:     pages,
+	}
+	return sheet.Render()
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/gcs"
+	"field-service/common/policy"
+	"field-service/common/spreadsheet"
+	"field-service/config"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errExport "field-service/constants/error/export"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportService struct {
+	repository repositories.IRepositoryRegistry
+	gcs        gcs.IGCSClient
+}
+
+type IExportService interface {
+	CheckSyncSize(context.Context, *dto.FieldScheduleExportRequestParam) error
+	Write(context.Context, *dto.FieldScheduleExportRequestParam, io.Writer) error
+	CreateJob(context.Context, *dto.FieldScheduleExportRequestParam) (*dto.ExportJobResponse, error)
+	GetJob(context.Context, string) (*dto.ExportJobResponse, error)
+	RunJob(context.Context, *models.ExportJob) (int, error)
+}
+
+func NewExportService(repository repositories.IRepositoryRegistry, gcs gcs.IGCSClient) IExportService {
+	return &ExportService{repository: repository, gcs: gcs}
+}
+
+var exportHeader = []interface{}{
+	"Schedule ID", "Field Code", "Field", "Date", "Start", "End", "Status",
+	"Price Per Hour", "Price", "Order ID", "Booker Name", "Booker Email", "Booker Phone",
+}
+
+// ownerFromContext limits the export to the fields of the user when they were
+// only granted their own.
+func (e *ExportService) ownerFromContext(ctx context.Context) (*uuid.UUID, error) {
+	if !policy.IsOwnScope(ctx) {
+		return nil, nil
+	}
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	return &user.UUID, nil
+}
+
+func (e *ExportService) maxSyncRows() int64 {
+	if config.Config.Export.MaxSyncRows > 0 {
+		return int64(config.Config.Export.MaxSyncRows)
+	}
+	return constants.DefaultExportMaxSyncRows
+}
+
+func (e *ExportService) linkExpiry() time.Duration {
+	if config.Config.Export.LinkExpiryMinute > 0 {
+		return time.Duration(config.Config.Export.LinkExpiryMinute) * time.Minute
+	}
+	return constants.DefaultExportLinkExpiryMinute * time.Minute
+}
+
+// CheckSyncSize rejects exports too large to stream in a single request, so
+// the caller can answer before any of the file has been sent.
+func (e *ExportService) CheckSyncSize(ctx context.Context, param *dto.FieldScheduleExportRequestParam) error {
+	ownerUUID, err := e.ownerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	total, err := e.repository.GetFieldSchedule().CountForExport(ctx, &param.FieldScheduleExportFilter, ownerUUID)
+	if err != nil {
+		return err
+	}
+	if total > e.maxSyncRows() {
+		return errExport.ErrExportTooLarge
+	}
+	return nil
+}
+
+func (e *ExportService) Write(ctx context.Context, param *dto.FieldScheduleExportRequestParam, writer io.Writer) error {
+	ownerUUID, err := e.ownerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = e.write(ctx, param.Format, &param.FieldScheduleExportFilter, ownerUUID, writer)
+	return err
+}
+
+// write streams the header and every matching schedule into writer in the
+// given format and returns the number of schedules written.
+func (e *ExportService) write(
+	ctx context.Context,
+	format string,
+	filter *dto.FieldScheduleExportFilter,
+	ownerUUID *uuid.UUID,
+	writer io.Writer,
+) (int, error) {
+	sheet, err := spreadsheet.NewWriter(format, writer)
+	if err != nil {
+		return 0, err
+	}
+	err = sheet.Write(exportHeader)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = e.repository.GetFieldSchedule().StreamForExport(ctx, filter, ownerUUID, func(row *dto.FieldScheduleExportRow) error {
+		count++
+		return sheet.Write([]interface{}{
+			row.UUID.String(),
+			row.FieldCode,
+			row.FieldName,
+			row.Date.Format(time.DateOnly),
+			row.StartTime,
+			row.EndTime,
+			string(row.Status.GetStatusString()),
+			row.PricePerHour,
+			row.Price,
+			row.OrderID,
+			row.BookerName,
+			row.BookerEmail,
+			row.BookerPhone,
+		})
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, sheet.Close()
+}
+
+func (e *ExportService) toResponse(job *models.ExportJob) (*dto.ExportJobResponse, error) {
+	response := &dto.ExportJobResponse{
+		UUID:       job.UUID,
+		Format:     job.Format,
+		Status:     job.Status,
+		RowCount:   job.RowCount,
+		Error:      job.LastError,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if job.Status == constants.ExportJobDone {
+		url, err := e.gcs.SignedURL(job.ObjectName, e.linkExpiry())
+		if err != nil {
+			return nil, err
+		}
+		response.DownloadURL = &url
+	}
+	return response, nil
+}
+
+func (e *ExportService) CreateJob(ctx context.Context, param *dto.FieldScheduleExportRequestParam) (*dto.ExportJobResponse, error) {
+	if config.Config.GCSBucketName == "" {
+		return nil, errExport.ErrExportStorageNotConfigured
+	}
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	ownerUUID, err := e.ownerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := json.Marshal(param.FieldScheduleExportFilter)
+	if err != nil {
+		return nil, err
+	}
+	job, err := e.repository.GetExportJob().Create(ctx, &models.ExportJob{
+		RequestedBy: user.UUID,
+		OwnerUUID:   ownerUUID,
+		Format:      param.Format,
+		Filter:      string(filter),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.toResponse(job)
+}
+
+func (e *ExportService) GetJob(ctx context.Context, uuid string) (*dto.ExportJobResponse, error) {
+	job, err := e.repository.GetExportJob().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, &job.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+	return e.toResponse(job)
+}
+
+// RunJob writes the export of the job straight into storage through a pipe
+// and returns the number of schedules written. The object name is set on the
+// job for the caller to save.
+func (e *ExportService) RunJob(ctx context.Context, job *models.ExportJob) (int, error) {
+	var filter dto.FieldScheduleExportFilter
+	err := json.Unmarshal([]byte(job.Filter), &filter)
+	if err != nil {
+		return 0, err
+	}
+	job.ObjectName = fmt.Sprintf("%s/%s/%s.%s", constants.ExportObjectPrefix, job.TenantID, job.UUID, job.Format)
+
+	reader, writer := io.Pipe()
+	written := make(chan int, 1)
+	go func() {
+		count, err := e.write(ctx, job.Format, &filter, job.OwnerUUID, writer)
+		_ = writer.CloseWithError(err)
+		written <- count
+	}()
+	err = e.gcs.UploadStream(ctx, job.ObjectName, spreadsheet.ContentType(job.Format), reader)
+	_ = reader.CloseWithError(err)
+	return <-written, err
+}
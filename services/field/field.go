@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"field-service/common/gcs"
+	"field-service/common/policy"
 	"field-service/common/util"
+	"field-service/constants"
 	errConstant "field-service/constants/error"
+	errField "field-service/constants/error/field"
 	"field-service/domain/dto"
 	"field-service/domain/models"
 	"field-service/repositories"
+	auditLogService "field-service/services/auditLog"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -16,24 +20,66 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type FieldService struct {
 	repository repositories.IRepositoryRegistry
 	gcs        gcs.IGCSClient
+	audit      auditLogService.IAuditLogService
 }
 
 type IFieldService interface {
 	GetAllWithPagination(context.Context, *dto.FieldRequestParam) (*util.PaginationResult, error)
 	GetAllWithoutPagination(context.Context) ([]dto.FieldResponse, error)
+	GetAllDeleted(context.Context) ([]dto.FieldResponse, error)
 	GetByUUID(context.Context, string) (*dto.FieldResponse, error)
+	GetActiveByUUID(context.Context, string) (*dto.FieldResponse, error)
 	Create(context.Context, *dto.FieldRequest) (*dto.FieldResponse, error)
 	Update(context.Context, string, *dto.UpdateFieldRequest) (*dto.FieldResponse, error)
+	UpdateStatus(context.Context, string, *dto.UpdateFieldStatusRequest) error
 	Delete(context.Context, string) error
+	Restore(context.Context, string) error
 }
 
-func NewFieldService(repository repositories.IRepositoryRegistry, gcs gcs.IGCSClient) IFieldService {
-	return &FieldService{repository: repository, gcs: gcs}
+func NewFieldService(repository repositories.IRepositoryRegistry, gcs gcs.IGCSClient, audit auditLogService.IAuditLogService) IFieldService {
+	return &FieldService{repository: repository, gcs: gcs, audit: audit}
+}
+
+func (f *FieldService) parentUUID(field *models.Field) *uuid.UUID {
+	if field.Parent == nil {
+		return nil
+	}
+	return &field.Parent.UUID
+}
+
+// resolveParent validates the requested parent of a field. Fields only nest
+// one level deep: a parent cannot have a parent itself and a field that
+// already has children cannot become a child.
+func (f *FieldService) resolveParent(ctx context.Context, field *models.Field, parentUUID *string) (*models.Field, error) {
+	if parentUUID == nil || *parentUUID == "" {
+		return nil, nil
+	}
+	parent, err := f.repository.GetField().FindByUUID(ctx, *parentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ParentID != nil {
+		return nil, errField.ErrInvalidParentField
+	}
+	if field != nil {
+		if parent.ID == field.ID {
+			return nil, errField.ErrInvalidParentField
+		}
+		children, err := f.repository.GetField().CountByParentID(ctx, int(field.ID))
+		if err != nil {
+			return nil, err
+		}
+		if children > 0 {
+			return nil, errField.ErrInvalidParentField
+		}
+	}
+	return parent, nil
 }
 
 func (f *FieldService) GetAllWithPagination(ctx context.Context, param *dto.FieldRequestParam) (*util.PaginationResult, error) {
@@ -49,6 +95,9 @@ func (f *FieldService) GetAllWithPagination(ctx context.Context, param *dto.Fiel
 			Name:         field.Name,
 			PricePerHour: field.PricePerHour,
 			Images:       field.Images,
+			Status:       field.Status.GetStatusString(),
+			ParentID:     f.parentUUID(&field),
+			OwnerID:      field.OwnerUUID,
 			CreatedAt:    field.CreatedAt,
 			UpdatedAt:    field.UpdatedAt,
 		})
@@ -64,7 +113,7 @@ func (f *FieldService) GetAllWithPagination(ctx context.Context, param *dto.Fiel
 }
 
 func (f *FieldService) GetAllWithoutPagination(ctx context.Context) ([]dto.FieldResponse, error) {
-	fields, err := f.repository.GetField().FindAllWithoutPagination(ctx)
+	fields, err := f.repository.GetField().FindAllByStatus(ctx, constants.FieldActive)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +125,9 @@ func (f *FieldService) GetAllWithoutPagination(ctx context.Context) ([]dto.Field
 			Name:         field.Name,
 			PricePerHour: field.PricePerHour,
 			Images:       field.Images,
+			Status:       field.Status.GetStatusString(),
+			ParentID:     f.parentUUID(&field),
+			OwnerID:      field.OwnerUUID,
 			CreatedAt:    field.CreatedAt,
 			UpdatedAt:    field.UpdatedAt,
 		})
@@ -83,21 +135,71 @@ func (f *FieldService) GetAllWithoutPagination(ctx context.Context) ([]dto.Field
 	return fieldResults, err
 }
 
+func (f *FieldService) GetAllDeleted(ctx context.Context) ([]dto.FieldResponse, error) {
+	fields, err := f.repository.GetField().FindAllDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fieldResults := make([]dto.FieldResponse, 0, len(fields))
+	for _, field := range fields {
+		var deletedAt *time.Time
+		if field.DeletedAt != nil && field.DeletedAt.Valid {
+			deletedAt = &field.DeletedAt.Time
+		}
+		fieldResults = append(fieldResults, dto.FieldResponse{
+			UUID:         field.UUID,
+			Code:         field.Code,
+			Name:         field.Name,
+			PricePerHour: field.PricePerHour,
+			Images:       field.Images,
+			Status:       field.Status.GetStatusString(),
+			CreatedAt:    field.CreatedAt,
+			UpdatedAt:    field.UpdatedAt,
+			DeletedAt:    deletedAt,
+		})
+	}
+	return fieldResults, nil
+}
+
 func (f *FieldService) GetByUUID(ctx context.Context, uuid string) (*dto.FieldResponse, error) {
 	field, err := f.repository.GetField().FindByUUID(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	return f.toResponse(field), nil
+}
+
+// GetActiveByUUID returns the field to the public, which only sees fields
+// open for booking. Inactive fields and fields under maintenance are not found.
+func (f *FieldService) GetActiveByUUID(ctx context.Context, uuid string) (*dto.FieldResponse, error) {
+	field, err := f.repository.GetField().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if field.Status != constants.FieldActive {
+		return nil, errField.ErrFieldNotFound
+	}
+	return f.toResponse(field), nil
+}
+
+func (f *FieldService) toResponse(field *models.Field) *dto.FieldResponse {
 	fieldResults := dto.FieldResponse{
 		UUID:         field.UUID,
 		Code:         field.Code,
 		Name:         field.Name,
 		PricePerHour: field.PricePerHour,
 		Images:       field.Images,
+		Status:       field.Status.GetStatusString(),
+		ParentID:     f.parentUUID(field),
+		OwnerID:      field.OwnerUUID,
 		CreatedAt:    field.CreatedAt,
 		UpdatedAt:    field.UpdatedAt,
 	}
-	return &fieldResults, nil
+	return &fieldResults
 }
 
 func (f *FieldService) validateUpload(images []multipart.FileHeader) error {
@@ -155,13 +257,44 @@ func (f *FieldService) Create(ctx context.Context, req *dto.FieldRequest) (*dto.
 	// 	fmt.Println("tes srvc create eror upload image")
 	// 	return nil, err
 	// }
+	parent, err := f.resolveParent(ctx, nil, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
 	fieldRequest := models.Field{
 		Code:         req.Code,
 		Name:         req.Name,
 		PricePerHour: req.PricePerHour,
 		// Images:       imageURL,
 	}
-	field, err := f.repository.GetField().Create(ctx, &fieldRequest)
+	if parent != nil {
+		fieldRequest.ParentID = &parent.ID
+	}
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		ownerUUID, err := uuid.Parse(*req.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		fieldRequest.OwnerUUID = &ownerUUID
+	}
+	if policy.IsOwnScope(ctx) {
+		fieldRequest.OwnerUUID = &policy.UserFromContext(ctx).UUID
+	}
+	var field *models.Field
+	err = f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		field, txErr = f.repository.GetField().Create(ctx, tx, &fieldRequest)
+		if txErr != nil {
+			return txErr
+		}
+		field.Parent = parent
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditCreate,
+			Entity:     constants.AuditEntityField,
+			EntityUUID: field.UUID,
+			After:      field,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +304,9 @@ func (f *FieldService) Create(ctx context.Context, req *dto.FieldRequest) (*dto.
 		Name:         field.Name,
 		PricePerHour: field.PricePerHour,
 		// Images:       field.Images,
+		Status:    field.Status.GetStatusString(),
+		ParentID:  f.parentUUID(field),
+		OwnerID:   field.OwnerUUID,
 		CreatedAt: field.CreatedAt,
 		UpdatedAt: field.UpdatedAt,
 	}
@@ -178,7 +314,15 @@ func (f *FieldService) Create(ctx context.Context, req *dto.FieldRequest) (*dto.
 }
 
 func (f *FieldService) Update(ctx context.Context, uuidParam string, req *dto.UpdateFieldRequest) (*dto.FieldResponse, error) {
-	_, err := f.repository.GetField().FindByUUID(ctx, uuidParam)
+	field, err := f.repository.GetField().FindByUUID(ctx, uuidParam)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := f.resolveParent(ctx, field, req.ParentID)
 	if err != nil {
 		return nil, err
 	}
@@ -191,15 +335,43 @@ func (f *FieldService) Update(ctx context.Context, uuidParam string, req *dto.Up
 	// 		return nil, err
 	// 	}
 	// }
-	fieldResult, err := f.repository.GetField().Update(ctx, uuidParam, &models.Field{
-		Code:         req.Code,
-		Name:         req.Name,
-		PricePerHour: req.PricePerHour,
-		// Images:       imageURL,
+	var parentID *uint
+	if parent != nil {
+		parentID = &parent.ID
+	}
+	var fieldResult *models.Field
+	err = f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		fieldResult, txErr = f.repository.GetField().Update(ctx, tx, uuidParam, &models.Field{
+			Code:         req.Code,
+			Name:         req.Name,
+			PricePerHour: req.PricePerHour,
+			// Images:       imageURL,
+		})
+		if txErr != nil {
+			return txErr
+		}
+		txErr = f.repository.GetField().UpdateParent(ctx, tx, uuidParam, parentID)
+		if txErr != nil {
+			return txErr
+		}
+		updatedField := *field
+		updatedField.Code = fieldResult.Code
+		updatedField.Name = fieldResult.Name
+		updatedField.PricePerHour = fieldResult.PricePerHour
+		updatedField.ParentID = parentID
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditUpdate,
+			Entity:     constants.AuditEntityField,
+			EntityUUID: field.UUID,
+			Before:     field,
+			After:      &updatedField,
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
+	fieldResult.Parent = parent
 	uuidParsed, _ := uuid.Parse(uuidParam)
 	return &dto.FieldResponse{
 		UUID:         uuidParsed,
@@ -207,19 +379,82 @@ func (f *FieldService) Update(ctx context.Context, uuidParam string, req *dto.Up
 		Name:         fieldResult.Name,
 		PricePerHour: fieldResult.PricePerHour,
 		// Images:       fieldResult.Images,
+		Status:    fieldResult.Status.GetStatusString(),
+		ParentID:  f.parentUUID(fieldResult),
+		OwnerID:   field.OwnerUUID,
 		CreatedAt: fieldResult.CreatedAt,
 		UpdatedAt: fieldResult.UpdatedAt,
 	}, nil
 }
 
+func (f *FieldService) UpdateStatus(ctx context.Context, uuid string, req *dto.UpdateFieldStatusRequest) error {
+	field, err := f.repository.GetField().FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return err
+	}
+	status := constants.FieldStatusName(req.Status).GetStatusInt()
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := f.repository.GetField().UpdateStatus(ctx, tx, uuid, status)
+		if txErr != nil {
+			return txErr
+		}
+		updatedField := *field
+		updatedField.Status = status
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditUpdateStatus,
+			Entity:     constants.AuditEntityField,
+			EntityUUID: field.UUID,
+			Before:     field,
+			After:      &updatedField,
+		})
+	})
+}
+
 func (f *FieldService) Delete(ctx context.Context, uuid string) error {
-	_, err := f.repository.GetField().FindByUUID(ctx, uuid)
+	field, err := f.repository.GetField().FindByUUID(ctx, uuid)
 	if err != nil {
 		return err
 	}
-	err = f.repository.GetField().Delete(ctx, uuid)
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
 	if err != nil {
 		return err
 	}
-	return nil
+	hasBooked, err := f.repository.GetFieldSchedule().ExistsUpcomingBookedByFieldID(ctx, int(field.ID))
+	if err != nil {
+		return err
+	}
+	if hasBooked {
+		return errField.ErrFieldHasBookedSchedules
+	}
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := f.repository.GetField().Delete(ctx, tx, uuid)
+		if txErr != nil {
+			return txErr
+		}
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditDelete,
+			Entity:     constants.AuditEntityField,
+			EntityUUID: field.UUID,
+			Before:     field,
+		})
+	})
+}
+
+func (f *FieldService) Restore(ctx context.Context, uuid string) error {
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		field, txErr := f.repository.GetField().Restore(ctx, tx, uuid)
+		if txErr != nil {
+			return txErr
+		}
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditRestore,
+			Entity:     constants.AuditEntityField,
+			EntityUUID: field.UUID,
+			After:      field,
+		})
+	})
 }
@@ -2,26 +2,36 @@ package services
 
 import (
 	"context"
+	"field-service/common/policy"
 	"field-service/common/util"
 	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errField "field-service/constants/error/field"
 	errorFieldSchedule "field-service/constants/error/fieldSchedule"
 	"field-service/domain/dto"
 	"field-service/domain/models"
 	"field-service/repositories"
+	auditLogService "field-service/services/auditLog"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type FieldScheduleService struct {
 	repository repositories.IRepositoryRegistry
+	audit      auditLogService.IAuditLogService
 }
 
 type IFieldScheduleService interface {
 	GetAllWithPagination(context.Context, *dto.FieldScheduleRequestParam) (*util.PaginationResult, error)
+	GetAllWithCursor(context.Context, *dto.FieldScheduleCursorRequestParam) (*util.CursorPaginationResult, error)
 	GetAllByFieldAndDate(context.Context, string, string) ([]dto.FieldScheduleForBookingResponse, error)
 	GetByUUID(context.Context, string) (*dto.FieldScheduleResponse, error)
+	GetHistory(context.Context, string) ([]dto.FieldScheduleHistoryResponse, error)
+	GetBoardAt(context.Context, *dto.FieldScheduleBoardRequestParam) ([]dto.FieldScheduleBoardResponse, error)
 	GenerateScheduleForOneMonth(context.Context, *dto.GenerateFieldScheduleForOneMonthRequest) error
 	Create(context.Context, *dto.FieldScheduleRequest) error
 	Update(context.Context, string, *dto.UpdateFieldScheduleRequest) (*dto.FieldScheduleResponse, error)
@@ -29,8 +39,28 @@ type IFieldScheduleService interface {
 	Delete(context.Context, string) error
 }
 
-func NewFieldScheduleService(repository repositories.IRepositoryRegistry) IFieldScheduleService {
-	return &FieldScheduleService{repository: repository}
+func NewFieldScheduleService(repository repositories.IRepositoryRegistry, audit auditLogService.IAuditLogService) IFieldScheduleService {
+	return &FieldScheduleService{repository: repository, audit: audit}
+}
+
+// create writes the schedules and their audit records in one transaction.
+func (f *FieldScheduleService) create(ctx context.Context, fieldSchedules []models.FieldSchedule) error {
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := f.repository.GetFieldSchedule().CreateWithTx(ctx, tx, fieldSchedules)
+		if txErr != nil {
+			return txErr
+		}
+		entries := make([]dto.AuditEntry, 0, len(fieldSchedules))
+		for i := range fieldSchedules {
+			entries = append(entries, dto.AuditEntry{
+				Action:     constants.AuditCreate,
+				Entity:     constants.AuditEntityFieldSchedule,
+				EntityUUID: fieldSchedules[i].UUID,
+				After:      &fieldSchedules[i],
+			})
+		}
+		return f.audit.Record(ctx, tx, entries...)
+	})
 }
 
 func (f *FieldScheduleService) GetAllWithPagination(ctx context.Context, param *dto.FieldScheduleRequestParam) (*util.PaginationResult, error) {
@@ -61,6 +91,111 @@ func (f *FieldScheduleService) GetAllWithPagination(ctx context.Context, param *
 	return &response, nil
 }
 
+// cursorOrder is the order cursor pages are listed in, hashed into every
+// cursor along with the filter.
+const cursorOrder = "date,start_time,id"
+
+func (f *FieldScheduleService) encodeCursor(fieldSchedule models.FieldSchedule, backward bool, query string) (*string, error) {
+	cursor, err := util.EncodeCursor(dto.FieldScheduleCursor{
+		Date:      fieldSchedule.Date.Format(time.DateOnly),
+		StartTime: fieldSchedule.Time.StartTime,
+		ID:        fieldSchedule.ID,
+		Backward:  backward,
+		Query:     query,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (f *FieldScheduleService) GetAllWithCursor(ctx context.Context, param *dto.FieldScheduleCursorRequestParam) (*util.CursorPaginationResult, error) {
+	var (
+		cursor     *dto.FieldScheduleCursor
+		nextCursor *string
+		prevCursor *string
+		total      *int64
+	)
+	query, err := util.HashCursorQuery(param.FieldScheduleFilterParam, cursorOrder)
+	if err != nil {
+		return nil, err
+	}
+	if param.Cursor != nil && *param.Cursor != "" {
+		cursor = &dto.FieldScheduleCursor{}
+		err = util.DecodeCursor(*param.Cursor, cursor)
+		if err != nil {
+			return nil, err
+		}
+		// A cursor only continues the listing it was issued for.
+		if cursor.Query != query {
+			return nil, errConstant.ErrInvalidCursor
+		}
+	}
+
+	fieldSchedules, err := f.repository.GetFieldSchedule().FindAllWithCursor(ctx, param, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(fieldSchedules) > param.Limit
+	if hasMore {
+		fieldSchedules = fieldSchedules[:param.Limit]
+	}
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(fieldSchedules)-1; i < j; i, j = i+1, j-1 {
+			fieldSchedules[i], fieldSchedules[j] = fieldSchedules[j], fieldSchedules[i]
+		}
+	}
+
+	if len(fieldSchedules) > 0 {
+		first := fieldSchedules[0]
+		last := fieldSchedules[len(fieldSchedules)-1]
+		if backward || hasMore {
+			nextCursor, err = f.encodeCursor(last, false, query)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if (backward && hasMore) || (!backward && cursor != nil) {
+			prevCursor, err = f.encodeCursor(first, true, query)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if param.WithTotal {
+		count, err := f.repository.GetFieldSchedule().CountWithFilter(ctx, &param.FieldScheduleFilterParam)
+		if err != nil {
+			return nil, err
+		}
+		total = &count
+	}
+
+	fieldScheduleResults := make([]dto.FieldScheduleResponse, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		fieldScheduleResults = append(fieldScheduleResults, dto.FieldScheduleResponse{
+			UUID:         fieldSchedule.UUID,
+			FieldName:    fieldSchedule.Field.Name,
+			PricePerHour: fieldSchedule.Field.PricePerHour,
+			Date:         fieldSchedule.Date.Format(time.DateOnly),
+			Status:       fieldSchedule.Status.GetStatusString(),
+			Time:         fmt.Sprintf("%s - %s", fieldSchedule.Time.StartTime, fieldSchedule.Time.EndTime),
+			CreatedAt:    fieldSchedule.CreatedAt,
+			UpdatedAt:    fieldSchedule.UpdatedAt,
+		})
+	}
+	response := util.GenerateCursorPagination(util.CursorPaginationParam{
+		Limit:      param.Limit,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		Total:      total,
+		Data:       fieldScheduleResults,
+	})
+	return &response, nil
+}
+
 func (f *FieldScheduleService) convertMonthName(inputDate string) string {
 	date, err := time.Parse(time.DateOnly, inputDate)
 	if err != nil {
@@ -92,6 +227,9 @@ func (f *FieldScheduleService) GetAllByFieldAndDate(ctx context.Context, uuidFie
 	if err != nil {
 		return nil, err
 	}
+	if field.Status != constants.FieldActive {
+		return nil, errField.ErrFieldNotActive
+	}
 	fieldSchedules, err := f.repository.GetFieldSchedule().FindAllByFieldIDAndDate(ctx, int(field.ID), date)
 	if err != nil {
 		return nil, err
@@ -128,11 +266,85 @@ func (f *FieldScheduleService) GetByUUID(ctx context.Context, uuid string) (*dto
 	return &fieldScheduleResult, nil
 }
 
+func (f *FieldScheduleService) GetHistory(ctx context.Context, uuid string) ([]dto.FieldScheduleHistoryResponse, error) {
+	histories, err := f.repository.GetFieldScheduleHistory().FindAllByFieldScheduleUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return nil, errorFieldSchedule.ErrFieldScheduleNotFound
+	}
+	err = policy.CheckOwnership(ctx, histories[0].Field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	historyResults := make([]dto.FieldScheduleHistoryResponse, 0, len(histories))
+	for _, history := range histories {
+		var fromStatus *constants.FieldScheduleStatusName
+		if history.FromStatus != nil {
+			statusName := history.FromStatus.GetStatusString()
+			fromStatus = &statusName
+		}
+		historyResults = append(historyResults, dto.FieldScheduleHistoryResponse{
+			UUID:       history.UUID,
+			Event:      history.Event,
+			FromStatus: fromStatus,
+			Status:     history.Status.GetStatusString(),
+			Date:       history.Date.Format(time.DateOnly),
+			Time:       fmt.Sprintf("%s - %s", history.Time.StartTime, history.Time.EndTime),
+			Reference:  history.Reference,
+			ActorID:    history.ActorUUID,
+			ActorName:  history.ActorName,
+			ActorRole:  history.ActorRole,
+			CreatedAt:  history.CreatedAt,
+		})
+	}
+	return historyResults, nil
+}
+
+// GetBoardAt rebuilds the booking board of a field for a date as it was at the
+// requested moment, from the schedule history.
+func (f *FieldScheduleService) GetBoardAt(ctx context.Context, param *dto.FieldScheduleBoardRequestParam) ([]dto.FieldScheduleBoardResponse, error) {
+	field, err := f.repository.GetField().FindByUUID(ctx, param.FieldID)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	at, _ := time.Parse(time.RFC3339, param.At)
+	histories, err := f.repository.GetFieldScheduleHistory().FindBoardAt(ctx, field.ID, param.Date, at)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].Time.StartTime < histories[j].Time.StartTime
+	})
+	boardResults := make([]dto.FieldScheduleBoardResponse, 0, len(histories))
+	for _, history := range histories {
+		boardResults = append(boardResults, dto.FieldScheduleBoardResponse{
+			UUID:      history.FieldScheduleUUID,
+			Date:      history.Date.Format(time.DateOnly),
+			Time:      fmt.Sprintf("%s - %s", history.Time.StartTime, history.Time.EndTime),
+			Status:    history.Status.GetStatusString(),
+			Reference: history.Reference,
+			ChangedBy: history.ActorName,
+			ChangedAt: history.CreatedAt,
+		})
+	}
+	return boardResults, nil
+}
+
 func (f *FieldScheduleService) Create(ctx context.Context, request *dto.FieldScheduleRequest) error {
 	field, err := f.repository.GetField().FindByUUID(ctx, request.FieldID)
 	if err != nil {
 		return err
 	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return err
+	}
 	fieldSchedules := make([]models.FieldSchedule, 0, len(request.TimeIDs))
 	dataParsed, _ := time.Parse(time.DateOnly, request.Date)
 	for _, timeID := range request.TimeIDs {
@@ -155,11 +367,7 @@ func (f *FieldScheduleService) Create(ctx context.Context, request *dto.FieldSch
 			Status:  constants.Available,
 		})
 	}
-	err = f.repository.GetFieldSchedule().Create(ctx, fieldSchedules)
-	if err != nil {
-		return err
-	}
-	return nil
+	return f.create(ctx, fieldSchedules)
 }
 
 func (f *FieldScheduleService) GenerateScheduleForOneMonth(ctx context.Context, request *dto.GenerateFieldScheduleForOneMonthRequest) error {
@@ -167,6 +375,10 @@ func (f *FieldScheduleService) GenerateScheduleForOneMonth(ctx context.Context,
 	if err != nil {
 		return err
 	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return err
+	}
 	times, err := f.repository.GetTime().FindAll(ctx)
 	if err != nil {
 		return err
@@ -193,11 +405,7 @@ func (f *FieldScheduleService) GenerateScheduleForOneMonth(ctx context.Context,
 			})
 		}
 	}
-	err = f.repository.GetFieldSchedule().Create(ctx, fieldSchedules)
-	if err != nil {
-		return err
-	}
-	return err
+	return f.create(ctx, fieldSchedules)
 }
 
 func (f *FieldScheduleService) Update(ctx context.Context, uuid string, request *dto.UpdateFieldScheduleRequest) (*dto.FieldScheduleResponse, error) {
@@ -205,6 +413,10 @@ func (f *FieldScheduleService) Update(ctx context.Context, uuid string, request
 	if err != nil {
 		return nil, err
 	}
+	err = policy.CheckOwnership(ctx, fieldSchedule.Field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
 
 	scheduleTime, err := f.repository.GetTime().FindByUUID(ctx, request.TimeID)
 	if err != nil {
@@ -226,9 +438,23 @@ func (f *FieldScheduleService) Update(ctx context.Context, uuid string, request
 		}
 	}
 	dateParsed, _ := time.Parse(time.DateOnly, request.Date)
-	fieldScheduleResult, err := f.repository.GetFieldSchedule().Update(ctx, uuid, &models.FieldSchedule{
-		Date:   dateParsed,
-		TimeID: scheduleTime.ID,
+	var fieldScheduleResult *models.FieldSchedule
+	err = f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		fieldScheduleResult, txErr = f.repository.GetFieldSchedule().Update(ctx, tx, uuid, &models.FieldSchedule{
+			Date:   dateParsed,
+			TimeID: scheduleTime.ID,
+		})
+		if txErr != nil {
+			return txErr
+		}
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditUpdate,
+			Entity:     constants.AuditEntityFieldSchedule,
+			EntityUUID: fieldSchedule.UUID,
+			Before:     fieldSchedule,
+			After:      fieldScheduleResult,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -246,25 +472,86 @@ func (f *FieldScheduleService) Update(ctx context.Context, uuid string, request
 	return &fieldScheduleResponse, nil
 }
 
+// UpdateStatus books every requested schedule for the order, or none of them.
+// The schedules are locked and checked to be Available in the transaction
+// that books them, blocks their related slots and records the audit trail.
 func (f *FieldScheduleService) UpdateStatus(ctx context.Context, request *dto.UpdateStatusFieldScheduleRequest) error {
+	uuids := make([]string, 0, len(request.FieldScheduleIDs))
+	seen := make(map[string]bool, len(request.FieldScheduleIDs))
 	for _, fieldScheduleID := range request.FieldScheduleIDs {
-		_, err := f.repository.GetFieldSchedule().FindByUUID(ctx, fieldScheduleID)
-		if err != nil {
-			return err
+		if !seen[fieldScheduleID] {
+			seen[fieldScheduleID] = true
+			uuids = append(uuids, fieldScheduleID)
 		}
-		err = f.repository.GetFieldSchedule().UpdateStatus(ctx, constants.Booked, fieldScheduleID)
 	}
-	return nil
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		if request.Customer != nil && request.OrderID != "" {
+			txErr := f.repository.GetOrderCustomer().Save(ctx, tx, &models.OrderCustomer{
+				OrderID:      request.OrderID,
+				CustomerUUID: request.Customer.UUID,
+				Name:         request.Customer.Name,
+				Email:        request.Customer.Email,
+				PhoneNumber:  request.Customer.PhoneNumber,
+				Language:     request.Customer.Language,
+			})
+			if txErr != nil {
+				return txErr
+			}
+		}
+		fieldSchedules, txErr := f.repository.GetFieldSchedule().FindAllByUUIDs(ctx, tx, uuids)
+		if txErr != nil {
+			return txErr
+		}
+		if len(fieldSchedules) < len(uuids) {
+			return errorFieldSchedule.ErrFieldScheduleNotFound
+		}
+		ids := make([]uint, 0, len(fieldSchedules))
+		for _, fieldSchedule := range fieldSchedules {
+			if fieldSchedule.Status != constants.Available {
+				return errorFieldSchedule.ErrFieldScheduleNotAvailable
+			}
+			ids = append(ids, fieldSchedule.ID)
+		}
+		txErr = f.repository.GetFieldSchedule().UpdateStatusByIDs(ctx, tx, ids, constants.Booked, request.OrderID)
+		if txErr != nil {
+			return txErr
+		}
+		entries := make([]dto.AuditEntry, 0, len(fieldSchedules))
+		for i := range fieldSchedules {
+			bookedSchedule := fieldSchedules[i]
+			bookedSchedule.Status = constants.Booked
+			bookedSchedule.OrderID = request.OrderID
+			entries = append(entries, dto.AuditEntry{
+				Action:     constants.AuditUpdateStatus,
+				Entity:     constants.AuditEntityFieldSchedule,
+				EntityUUID: fieldSchedules[i].UUID,
+				Before:     &fieldSchedules[i],
+				After:      &bookedSchedule,
+			})
+		}
+		return f.audit.Record(ctx, tx, entries...)
+	})
 }
 
 func (f *FieldScheduleService) Delete(ctx context.Context, uuid string) error {
-	_, err := f.repository.GetFieldSchedule().FindByUUID(ctx, uuid)
+	fieldSchedule, err := f.repository.GetFieldSchedule().FindByUUID(ctx, uuid)
 	if err != nil {
 		return err
 	}
-	err = f.repository.GetFieldSchedule().Delete(ctx, uuid)
+	err = policy.CheckOwnership(ctx, fieldSchedule.Field.OwnerUUID)
 	if err != nil {
 		return err
 	}
-	return nil
+	return f.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := f.repository.GetFieldSchedule().Delete(ctx, tx, uuid)
+		if txErr != nil {
+			return txErr
+		}
+		return f.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditDelete,
+			Entity:     constants.AuditEntityFieldSchedule,
+			EntityUUID: fieldSchedule.UUID,
+			Before:     fieldSchedule,
+		})
+	})
 }
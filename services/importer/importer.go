@@ -0,0 +1,822 @@
+package services
+
+import (
+	"context"
+	"errors"
+	errWrap "field-service/common/error"
+	"field-service/common/policy"
+	"field-service/common/spreadsheet"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errField "field-service/constants/error/field"
+	errFieldSchedule "field-service/constants/error/fieldSchedule"
+	errImporter "field-service/constants/error/importer"
+	errTime "field-service/constants/error/time"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	auditLogService "field-service/services/auditLog"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	columnUUID         = "uuid"
+	columnCode         = "code"
+	columnName         = "name"
+	columnPricePerHour = "pricePerHour"
+	columnParentCode   = "parentCode"
+	columnOwnerID      = "ownerID"
+	columnStartTime    = "startTime"
+	columnEndTime      = "endTime"
+	columnFieldCode    = "fieldCode"
+	columnDate         = "date"
+	columnStatus       = "status"
+)
+
+// importableStatuses are the statuses an import may give a schedule. Booking
+// statuses are only ever set by orders.
+var importableStatuses = []constants.FieldScheduleStatus{constants.Available, constants.Maintenance, constants.Blocked}
+
+type ImporterService struct {
+	repository repositories.IRepositoryRegistry
+	audit      auditLogService.IAuditLogService
+}
+
+type IImporterService interface {
+	Import(context.Context, string, string, io.Reader, bool) (*dto.ImportResponse, error)
+}
+
+func NewImporterService(repository repositories.IRepositoryRegistry, audit auditLogService.IAuditLogService) IImporterService {
+	return &ImporterService{repository: repository, audit: audit}
+}
+
+// importApply writes a validated import in the transaction and returns the
+// audit entries of what it changed.
+type importApply func(*gorm.DB) ([]dto.AuditEntry, error)
+
+// Import reads fields, times or schedules from a CSV or XLSX file and upserts
+// them. Every row is validated before anything is written: a dry run stops
+// there and reports what would change, otherwise the rows are written in one
+// transaction, and only when none of them is invalid.
+func (i *ImporterService) Import(ctx context.Context, importType string, fileName string, file io.Reader, dryRun bool) (*dto.ImportResponse, error) {
+	header, rows, err := spreadsheet.Read(fileName, file)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrMissingHeader) {
+			return nil, errImporter.ErrImportFileEmpty
+		}
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+			return nil, errImporter.ErrImportFileInvalid
+		}
+		return nil, errWrap.WrapError(errImporter.ErrImportFileInvalid)
+	}
+	if len(rows) == 0 {
+		return nil, errImporter.ErrImportFileEmpty
+	}
+	if len(rows) > constants.MaxImportRows {
+		return nil, errImporter.ErrImportTooManyRows
+	}
+
+	response := &dto.ImportResponse{
+		Type:   importType,
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []dto.ImportRowError{},
+	}
+	var apply importApply
+	switch importType {
+	case constants.ImportFields:
+		apply, err = i.planFields(ctx, header, rows, response)
+	case constants.ImportTimes:
+		apply, err = i.planTimes(ctx, header, rows, response)
+	case constants.ImportSchedules:
+		apply, err = i.planSchedules(ctx, header, rows, response)
+	default:
+		return nil, errImporter.ErrImportTypeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	failedRows := map[int]bool{}
+	for _, rowError := range response.Errors {
+		if rowError.Row > 1 {
+			failedRows[rowError.Row] = true
+		}
+	}
+	response.Failed = len(failedRows)
+	if dryRun {
+		return response, nil
+	}
+	if len(response.Errors) > 0 || apply == nil {
+		return response, errImporter.ErrImportInvalidRows
+	}
+
+	err = i.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		entries, txErr := apply(tx)
+		if txErr != nil {
+			return txErr
+		}
+		return i.audit.Record(ctx, tx, entries...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Committed = true
+	return response, nil
+}
+
+func (i *ImporterService) rowError(response *dto.ImportResponse, row int, column string, message string) {
+	response.Errors = append(response.Errors, dto.ImportRowError{Row: row, Column: column, Message: message})
+}
+
+func (i *ImporterService) hasColumn(header []string, column string) bool {
+	column = spreadsheet.NormalizeColumn(column)
+	for _, name := range header {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// requireColumns reports every required column missing from the header
+// against row 1.
+func (i *ImporterService) requireColumns(header []string, response *dto.ImportResponse, columns ...string) bool {
+	present := true
+	for _, column := range columns {
+		if !i.hasColumn(header, column) {
+			i.rowError(response, 1, column, "column is missing")
+			present = false
+		}
+	}
+	return present
+}
+
+// parseClock accepts HH:MM or HH:MM:SS and returns HH:MM:SS, the way times
+// are stored.
+func (i *ImporterService) parseClock(value string) (string, bool) {
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return parsed.Format(time.TimeOnly), true
+		}
+	}
+	return "", false
+}
+
+func (i *ImporterService) timeKey(startTime string, endTime string) string {
+	if parsed, ok := i.parseClock(startTime); ok {
+		startTime = parsed
+	}
+	if parsed, ok := i.parseClock(endTime); ok {
+		endTime = parsed
+	}
+	return fmt.Sprintf("%s-%s", startTime, endTime)
+}
+
+type fieldImport struct {
+	row       int
+	key       string
+	parentKey string
+	before    *models.Field
+	field     models.Field
+	unchanged bool
+}
+
+// planFields matches each row to an existing field by uuid, or else by code,
+// and checks that parents still nest one level deep once every row is
+// applied. parentCode, when the column is present, is authoritative: a blank
+// value detaches the field from its parent.
+func (i *ImporterService) planFields(ctx context.Context, header []string, rows []spreadsheet.Row, response *dto.ImportResponse) (importApply, error) {
+	if !i.requireColumns(header, response, columnCode, columnName, columnPricePerHour) {
+		return nil, nil
+	}
+	var ownUser *dto.UserLogin
+	if policy.IsOwnScope(ctx) {
+		ownUser = policy.UserFromContext(ctx)
+		if ownUser == nil {
+			return nil, errConstant.ErrForbidden
+		}
+	}
+	fields, err := i.repository.GetField().FindAllWithoutPagination(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fieldByUUID := make(map[uuid.UUID]*models.Field, len(fields))
+	fieldByCode := make(map[string]*models.Field, len(fields))
+	for idx := range fields {
+		fieldByUUID[fields[idx].UUID] = &fields[idx]
+		fieldByCode[strings.ToLower(fields[idx].Code)] = &fields[idx]
+	}
+	hasParentColumn := i.hasColumn(header, columnParentCode)
+
+	imports := make([]*fieldImport, 0, len(rows))
+	parentCodes := make(map[*fieldImport]string, len(rows))
+	rowByCode := map[string]int{}
+	rowByField := map[uint]int{}
+	for _, row := range rows {
+		valid := true
+		code := row.Get(columnCode)
+		name := row.Get(columnName)
+		if code == "" || len(code) > 15 {
+			i.rowError(response, row.Number, columnCode, "must be 1 to 15 characters")
+			valid = false
+		} else if first, ok := rowByCode[strings.ToLower(code)]; ok {
+			i.rowError(response, row.Number, columnCode, fmt.Sprintf("duplicates row %d", first))
+			valid = false
+		}
+		if name == "" || len(name) > 100 {
+			i.rowError(response, row.Number, columnName, "must be 1 to 100 characters")
+			valid = false
+		}
+		pricePerHour, err := strconv.Atoi(row.Get(columnPricePerHour))
+		if err != nil || pricePerHour <= 0 {
+			i.rowError(response, row.Number, columnPricePerHour, "must be a whole number above 0")
+			valid = false
+		}
+		var ownerUUID *uuid.UUID
+		if value := row.Get(columnOwnerID); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				i.rowError(response, row.Number, columnOwnerID, "must be a UUID")
+				valid = false
+			} else {
+				ownerUUID = &parsed
+			}
+		}
+
+		var before *models.Field
+		if value := row.Get(columnUUID); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				i.rowError(response, row.Number, columnUUID, "must be a UUID")
+				continue
+			}
+			before = fieldByUUID[parsed]
+			if before == nil {
+				i.rowError(response, row.Number, columnUUID, errField.ErrFieldNotFound.Error())
+				continue
+			}
+			if other := fieldByCode[strings.ToLower(code)]; other != nil && other.ID != before.ID {
+				i.rowError(response, row.Number, columnCode, "is used by another field")
+				valid = false
+			}
+		} else {
+			before = fieldByCode[strings.ToLower(code)]
+		}
+		if before != nil {
+			if first, ok := rowByField[before.ID]; ok {
+				i.rowError(response, row.Number, "", fmt.Sprintf("updates the same field as row %d", first))
+				valid = false
+			}
+			if policy.CheckOwnership(ctx, before.OwnerUUID) != nil {
+				i.rowError(response, row.Number, "", errConstant.ErrForbidden.Error())
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		imported := &fieldImport{row: row.Number, key: fmt.Sprintf("row:%d", row.Number), before: before}
+		if before != nil {
+			imported.key = fmt.Sprintf("id:%d", before.ID)
+			imported.field = *before
+			rowByField[before.ID] = row.Number
+		}
+		imported.field.Code = code
+		imported.field.Name = name
+		imported.field.PricePerHour = pricePerHour
+		if ownerUUID != nil {
+			imported.field.OwnerUUID = ownerUUID
+		}
+		if ownUser != nil {
+			imported.field.OwnerUUID = &ownUser.UUID
+		}
+		if hasParentColumn {
+			parentCodes[imported] = row.Get(columnParentCode)
+		}
+		rowByCode[strings.ToLower(code)] = row.Number
+		imports = append(imports, imported)
+	}
+
+	// Work out every field's parent once the import is applied. Fields are
+	// keyed by ID, or by row for the ones the import creates, since codes may
+	// change.
+	keyByCode := make(map[string]string, len(fields)+len(imports))
+	parentOf := make(map[string]string, len(fields)+len(imports))
+	for _, field := range fields {
+		key := fmt.Sprintf("id:%d", field.ID)
+		keyByCode[strings.ToLower(field.Code)] = key
+		if field.ParentID != nil {
+			parentOf[key] = fmt.Sprintf("id:%d", *field.ParentID)
+		}
+	}
+	for _, imported := range imports {
+		if imported.before != nil {
+			delete(keyByCode, strings.ToLower(imported.before.Code))
+		}
+	}
+	for _, imported := range imports {
+		keyByCode[strings.ToLower(imported.field.Code)] = imported.key
+	}
+	invalid := map[*fieldImport]bool{}
+	for _, imported := range imports {
+		parentCode, ok := parentCodes[imported]
+		if !ok {
+			continue
+		}
+		delete(parentOf, imported.key)
+		if parentCode == "" {
+			continue
+		}
+		parentKey, found := keyByCode[strings.ToLower(parentCode)]
+		if !found {
+			i.rowError(response, imported.row, columnParentCode, errField.ErrFieldNotFound.Error())
+			invalid[imported] = true
+			continue
+		}
+		parentOf[imported.key] = parentKey
+	}
+	hasChildren := make(map[string]bool, len(parentOf))
+	for _, parentKey := range parentOf {
+		hasChildren[parentKey] = true
+	}
+
+	valid := make([]*fieldImport, 0, len(imports))
+	for _, imported := range imports {
+		if invalid[imported] {
+			continue
+		}
+		imported.parentKey = parentOf[imported.key]
+		if imported.parentKey != "" {
+			if imported.parentKey == imported.key || parentOf[imported.parentKey] != "" || hasChildren[imported.key] {
+				i.rowError(response, imported.row, columnParentCode, errField.ErrInvalidParentField.Error())
+				continue
+			}
+		}
+		valid = append(valid, imported)
+	}
+
+	for _, imported := range valid {
+		before := imported.before
+		switch {
+		case before == nil:
+			response.Created++
+		case before.Code == imported.field.Code &&
+			before.Name == imported.field.Name &&
+			before.PricePerHour == imported.field.PricePerHour &&
+			i.sameUUID(before.OwnerUUID, imported.field.OwnerUUID) &&
+			parentOf[imported.key] == i.fieldParentKey(before):
+			imported.unchanged = true
+			response.Unchanged++
+		default:
+			response.Updated++
+		}
+	}
+
+	return func(tx *gorm.DB) ([]dto.AuditEntry, error) {
+		entries := make([]dto.AuditEntry, 0, len(valid))
+		idByKey := make(map[string]uint, len(fields)+len(valid))
+		for _, field := range fields {
+			idByKey[fmt.Sprintf("id:%d", field.ID)] = field.ID
+		}
+		// Parents never have a parent themselves, so writing the fields
+		// without one first gives every new parent its ID before its children.
+		ordered := make([]*fieldImport, 0, len(valid))
+		for _, imported := range valid {
+			if imported.parentKey == "" {
+				ordered = append(ordered, imported)
+			}
+		}
+		for _, imported := range valid {
+			if imported.parentKey != "" {
+				ordered = append(ordered, imported)
+			}
+		}
+		for _, imported := range ordered {
+			if imported.unchanged {
+				continue
+			}
+			field := imported.field
+			field.Parent = nil
+			field.Children = nil
+			field.FieldSchedule = nil
+			field.ParentID = nil
+			if imported.parentKey != "" {
+				parentID := idByKey[imported.parentKey]
+				field.ParentID = &parentID
+			}
+			result, err := i.repository.GetField().Upsert(ctx, tx, &field)
+			if err != nil {
+				return nil, err
+			}
+			idByKey[imported.key] = result.ID
+			entry := dto.AuditEntry{
+				Action:     constants.AuditCreate,
+				Entity:     constants.AuditEntityField,
+				EntityUUID: result.UUID,
+				After:      result,
+			}
+			if imported.before != nil {
+				entry.Action = constants.AuditUpdate
+				entry.Before = imported.before
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}, nil
+}
+
+func (i *ImporterService) sameUUID(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (i *ImporterService) fieldParentKey(field *models.Field) string {
+	if field.ParentID == nil {
+		return ""
+	}
+	return fmt.Sprintf("id:%d", *field.ParentID)
+}
+
+type timeImport struct {
+	before *models.Time
+	time   models.Time
+}
+
+// planTimes matches each row to an existing time by uuid, or else by its
+// start and end. Times are shared by every field of the tenant, so they cannot
+// be imported by a user limited to their own fields.
+func (i *ImporterService) planTimes(ctx context.Context, header []string, rows []spreadsheet.Row, response *dto.ImportResponse) (importApply, error) {
+	if policy.IsOwnScope(ctx) {
+		return nil, errConstant.ErrForbidden
+	}
+	if !i.requireColumns(header, response, columnStartTime, columnEndTime) {
+		return nil, nil
+	}
+	times, err := i.repository.GetTime().FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeByUUID := make(map[uuid.UUID]*models.Time, len(times))
+	timeByKey := make(map[string]*models.Time, len(times))
+	for idx := range times {
+		timeByUUID[times[idx].UUID] = &times[idx]
+		timeByKey[i.timeKey(times[idx].StartTime, times[idx].EndTime)] = &times[idx]
+	}
+
+	imports := make([]timeImport, 0, len(rows))
+	rowByKey := map[string]int{}
+	rowByTime := map[uint]int{}
+	for _, row := range rows {
+		valid := true
+		startTime, startOK := i.parseClock(row.Get(columnStartTime))
+		if !startOK {
+			i.rowError(response, row.Number, columnStartTime, "must be a time as HH:MM or HH:MM:SS")
+			valid = false
+		}
+		endTime, endOK := i.parseClock(row.Get(columnEndTime))
+		if !endOK {
+			i.rowError(response, row.Number, columnEndTime, "must be a time as HH:MM or HH:MM:SS")
+			valid = false
+		}
+		key := i.timeKey(startTime, endTime)
+		if startOK && endOK {
+			if startTime == endTime {
+				i.rowError(response, row.Number, columnEndTime, "must differ from startTime")
+				valid = false
+			} else if first, ok := rowByKey[key]; ok {
+				i.rowError(response, row.Number, "", fmt.Sprintf("duplicates row %d", first))
+				valid = false
+			}
+		}
+
+		var before *models.Time
+		if value := row.Get(columnUUID); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				i.rowError(response, row.Number, columnUUID, "must be a UUID")
+				continue
+			}
+			before = timeByUUID[parsed]
+			if before == nil {
+				i.rowError(response, row.Number, columnUUID, errTime.ErrTimeNotFound.Error())
+				continue
+			}
+			if first, ok := rowByTime[before.ID]; ok {
+				i.rowError(response, row.Number, columnUUID, fmt.Sprintf("updates the same time as row %d", first))
+				valid = false
+			}
+			if other := timeByKey[key]; other != nil && other.ID != before.ID {
+				i.rowError(response, row.Number, "", "is already another time")
+				valid = false
+			}
+		} else {
+			before = timeByKey[key]
+		}
+		if !valid {
+			continue
+		}
+		rowByKey[key] = row.Number
+
+		switch {
+		case before == nil:
+			response.Created++
+			imports = append(imports, timeImport{time: models.Time{StartTime: startTime, EndTime: endTime}})
+		case i.timeKey(before.StartTime, before.EndTime) == key:
+			response.Unchanged++
+		default:
+			rowByTime[before.ID] = row.Number
+			response.Updated++
+			updated := *before
+			updated.StartTime = startTime
+			updated.EndTime = endTime
+			imports = append(imports, timeImport{before: before, time: updated})
+		}
+	}
+
+	return func(tx *gorm.DB) ([]dto.AuditEntry, error) {
+		entries := make([]dto.AuditEntry, 0, len(imports))
+		for _, imported := range imports {
+			timeItem := imported.time
+			result, err := i.repository.GetTime().Upsert(ctx, tx, &timeItem)
+			if err != nil {
+				return nil, err
+			}
+			entry := dto.AuditEntry{
+				Action:     constants.AuditCreate,
+				Entity:     constants.AuditEntityTime,
+				EntityUUID: result.UUID,
+				After:      result,
+			}
+			if imported.before != nil {
+				entry.Action = constants.AuditUpdate
+				entry.Before = imported.before
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}, nil
+}
+
+type scheduleRow struct {
+	row     int
+	uuid    *uuid.UUID
+	fieldID uint
+	timeID  uint
+	date    time.Time
+	status  *constants.FieldScheduleStatus
+}
+
+func (i *ImporterService) scheduleKey(fieldID uint, date time.Time, timeID uint) string {
+	return fmt.Sprintf("%d|%s|%d", fieldID, date.Format(time.DateOnly), timeID)
+}
+
+// parseScheduleStatus accepts the statuses an import may set, by name.
+func (i *ImporterService) parseScheduleStatus(value string) (*constants.FieldScheduleStatus, bool) {
+	status := constants.FieldScheduleStatusName(value).GetStatusInt()
+	for _, importable := range importableStatuses {
+		if status == importable {
+			return &status, true
+		}
+	}
+	return nil, false
+}
+
+// planSchedules matches each row to an existing schedule by uuid, or else by
+// field, date and time, and creates the schedules matching none. An existing
+// schedule only has its status imported: a row with a uuid needs no other
+// column, and moving a schedule stays with the update endpoint. Booked
+// schedules are left to their orders.
+func (i *ImporterService) planSchedules(ctx context.Context, header []string, rows []spreadsheet.Row, response *dto.ImportResponse) (importApply, error) {
+	if !i.hasColumn(header, columnUUID) &&
+		!i.requireColumns(header, response, columnFieldCode, columnDate, columnStartTime, columnEndTime) {
+		return nil, nil
+	}
+	fields, err := i.repository.GetField().FindAllWithoutPagination(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fieldByCode := make(map[string]*models.Field, len(fields))
+	fieldByID := make(map[uint]*models.Field, len(fields))
+	for idx := range fields {
+		fieldByCode[strings.ToLower(fields[idx].Code)] = &fields[idx]
+		fieldByID[fields[idx].ID] = &fields[idx]
+	}
+	times, err := i.repository.GetTime().FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeByKey := make(map[string]*models.Time, len(times))
+	for idx := range times {
+		timeByKey[i.timeKey(times[idx].StartTime, times[idx].EndTime)] = &times[idx]
+	}
+
+	parsed := make([]scheduleRow, 0, len(rows))
+	uuids := make([]string, 0)
+	fieldIDs := make([]uint, 0)
+	var from, until time.Time
+	for _, row := range rows {
+		valid := true
+		parsedRow := scheduleRow{row: row.Number}
+		if value := row.Get(columnStatus); value != "" {
+			status, ok := i.parseScheduleStatus(value)
+			if !ok {
+				i.rowError(response, row.Number, columnStatus, "must be Available, Maintenance or Blocked")
+				valid = false
+			}
+			parsedRow.status = status
+		}
+
+		if value := row.Get(columnUUID); value != "" {
+			scheduleUUID, err := uuid.Parse(value)
+			if err != nil {
+				i.rowError(response, row.Number, columnUUID, "must be a UUID")
+				continue
+			}
+			if !valid {
+				continue
+			}
+			parsedRow.uuid = &scheduleUUID
+			uuids = append(uuids, scheduleUUID.String())
+			parsed = append(parsed, parsedRow)
+			continue
+		}
+
+		field := fieldByCode[strings.ToLower(row.Get(columnFieldCode))]
+		if field == nil {
+			i.rowError(response, row.Number, columnFieldCode, errField.ErrFieldNotFound.Error())
+			valid = false
+		} else if policy.CheckOwnership(ctx, field.OwnerUUID) != nil {
+			i.rowError(response, row.Number, columnFieldCode, errConstant.ErrForbidden.Error())
+			valid = false
+		}
+		date, err := time.Parse(time.DateOnly, row.Get(columnDate))
+		if err != nil {
+			i.rowError(response, row.Number, columnDate, "must be a date as YYYY-MM-DD")
+			valid = false
+		}
+		timeItem := timeByKey[i.timeKey(row.Get(columnStartTime), row.Get(columnEndTime))]
+		if timeItem == nil {
+			i.rowError(response, row.Number, columnStartTime, errTime.ErrTimeNotFound.Error())
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+		parsedRow.fieldID = field.ID
+		parsedRow.timeID = timeItem.ID
+		parsedRow.date = date
+		fieldIDs = append(fieldIDs, field.ID)
+		if from.IsZero() || date.Before(from) {
+			from = date
+		}
+		if until.IsZero() || date.After(until) {
+			until = date
+		}
+		parsed = append(parsed, parsedRow)
+	}
+
+	scheduleByUUID := map[uuid.UUID]*models.FieldSchedule{}
+	if len(uuids) > 0 {
+		fieldSchedules, err := i.repository.GetFieldSchedule().FindAllByUUIDs(ctx, i.repository.GetTx(), uuids)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range fieldSchedules {
+			scheduleByUUID[fieldSchedules[idx].UUID] = &fieldSchedules[idx]
+		}
+	}
+	scheduleByKey := map[string]*models.FieldSchedule{}
+	if len(fieldIDs) > 0 {
+		fieldSchedules, err := i.repository.GetFieldSchedule().FindAllByFieldIDsBetween(ctx, fieldIDs, from.Format(time.DateOnly), until.Format(time.DateOnly))
+		if err != nil {
+			return nil, err
+		}
+		for idx := range fieldSchedules {
+			fieldSchedule := &fieldSchedules[idx]
+			scheduleByKey[i.scheduleKey(fieldSchedule.FieldID, fieldSchedule.Date, fieldSchedule.TimeID)] = fieldSchedule
+		}
+	}
+
+	creates := make([]models.FieldSchedule, 0, len(parsed))
+	updates := map[constants.FieldScheduleStatus][]models.FieldSchedule{}
+	rowBySchedule := map[string]int{}
+	for _, parsedRow := range parsed {
+		var (
+			target *models.FieldSchedule
+			key    string
+		)
+		if parsedRow.uuid != nil {
+			target = scheduleByUUID[*parsedRow.uuid]
+			if target == nil {
+				i.rowError(response, parsedRow.row, columnUUID, errFieldSchedule.ErrFieldScheduleNotFound.Error())
+				continue
+			}
+			field := fieldByID[target.FieldID]
+			if field == nil {
+				i.rowError(response, parsedRow.row, columnUUID, errField.ErrFieldNotFound.Error())
+				continue
+			}
+			if policy.CheckOwnership(ctx, field.OwnerUUID) != nil {
+				i.rowError(response, parsedRow.row, columnUUID, errConstant.ErrForbidden.Error())
+				continue
+			}
+			key = i.scheduleKey(target.FieldID, target.Date, target.TimeID)
+		} else {
+			key = i.scheduleKey(parsedRow.fieldID, parsedRow.date, parsedRow.timeID)
+			target = scheduleByKey[key]
+		}
+		if first, ok := rowBySchedule[key]; ok {
+			i.rowError(response, parsedRow.row, "", fmt.Sprintf("duplicates row %d", first))
+			continue
+		}
+		rowBySchedule[key] = parsedRow.row
+
+		switch {
+		case target == nil:
+			status := constants.Available
+			if parsedRow.status != nil {
+				status = *parsedRow.status
+			}
+			creates = append(creates, models.FieldSchedule{
+				UUID:    uuid.New(),
+				FieldID: parsedRow.fieldID,
+				TimeID:  parsedRow.timeID,
+				Date:    parsedRow.date,
+				Status:  status,
+			})
+			response.Created++
+		case parsedRow.status == nil || *parsedRow.status == target.Status:
+			response.Unchanged++
+		case target.Status.IsBooking():
+			i.rowError(response, parsedRow.row, columnStatus, errFieldSchedule.ErrFieldScheduleNotAvailable.Error())
+		default:
+			updates[*parsedRow.status] = append(updates[*parsedRow.status], *target)
+			response.Updated++
+		}
+	}
+
+	return func(tx *gorm.DB) ([]dto.AuditEntry, error) {
+		entries := make([]dto.AuditEntry, 0, len(creates))
+		err := i.repository.GetFieldSchedule().CreateWithTx(ctx, tx, creates)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range creates {
+			entries = append(entries, dto.AuditEntry{
+				Action:     constants.AuditCreate,
+				Entity:     constants.AuditEntityFieldSchedule,
+				EntityUUID: creates[idx].UUID,
+				After:      &creates[idx],
+			})
+		}
+		for _, status := range importableStatuses {
+			fieldSchedules := updates[status]
+			if len(fieldSchedules) == 0 {
+				continue
+			}
+			uuids := make([]string, 0, len(fieldSchedules))
+			for _, fieldSchedule := range fieldSchedules {
+				uuids = append(uuids, fieldSchedule.UUID.String())
+			}
+			// A schedule may have been booked since the rows were checked.
+			locked, err := i.repository.GetFieldSchedule().FindAllByUUIDs(ctx, tx, uuids)
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]uint, 0, len(locked))
+			for _, fieldSchedule := range locked {
+				if fieldSchedule.Status.IsBooking() {
+					return nil, errFieldSchedule.ErrFieldScheduleNotAvailable
+				}
+				ids = append(ids, fieldSchedule.ID)
+				before := fieldSchedule
+				after := fieldSchedule
+				after.Status = status
+				entries = append(entries, dto.AuditEntry{
+					Action:     constants.AuditUpdateStatus,
+					Entity:     constants.AuditEntityFieldSchedule,
+					EntityUUID: fieldSchedule.UUID,
+					Before:     &before,
+					After:      &after,
+				})
+			}
+			err = i.repository.GetFieldSchedule().UpdateStatusByIDs(ctx, tx, ids, status, "")
+			if err != nil {
+				return nil, err
+			}
+		}
+		return entries, nil
+	}, nil
+}
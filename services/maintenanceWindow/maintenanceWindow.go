@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"field-service/common/policy"
+	"field-service/constants"
+	errMaintenanceWindow "field-service/constants/error/maintenanceWindow"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceWindowService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type IMaintenanceWindowService interface {
+	GetAllByField(context.Context, *dto.MaintenanceWindowRequestParam) ([]dto.MaintenanceWindowResponse, error)
+	GetByUUID(context.Context, string) (*dto.MaintenanceWindowResponse, error)
+	Create(context.Context, *dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error)
+	Delete(context.Context, string) error
+}
+
+func NewMaintenanceWindowService(repository repositories.IRepositoryRegistry) IMaintenanceWindowService {
+	return &MaintenanceWindowService{repository: repository}
+}
+
+func (m *MaintenanceWindowService) toResponse(window *models.MaintenanceWindow, cancelledBookings int) dto.MaintenanceWindowResponse {
+	return dto.MaintenanceWindowResponse{
+		UUID:              window.UUID,
+		FieldName:         window.Field.Name,
+		StartDate:         window.StartDate.Format(time.DateOnly),
+		EndDate:           window.EndDate.Format(time.DateOnly),
+		StartTime:         window.StartTime,
+		EndTime:           window.EndTime,
+		Reason:            window.Reason,
+		CancelledBookings: cancelledBookings,
+		CreatedAt:         window.CreatedAt,
+		UpdatedAt:         window.UpdatedAt,
+	}
+}
+
+func (m *MaintenanceWindowService) GetAllByField(ctx context.Context, param *dto.MaintenanceWindowRequestParam) ([]dto.MaintenanceWindowResponse, error) {
+	field, err := m.repository.GetField().FindByUUID(ctx, param.FieldID)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := m.repository.GetMaintenanceWindow().FindAllByFieldID(ctx, int(field.ID))
+	if err != nil {
+		return nil, err
+	}
+	windowResults := make([]dto.MaintenanceWindowResponse, 0, len(windows))
+	for _, window := range windows {
+		windowResults = append(windowResults, m.toResponse(&window, 0))
+	}
+	return windowResults, nil
+}
+
+func (m *MaintenanceWindowService) GetByUUID(ctx context.Context, uuid string) (*dto.MaintenanceWindowResponse, error) {
+	window, err := m.repository.GetMaintenanceWindow().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, window.Field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	response := m.toResponse(window, 0)
+	return &response, nil
+}
+
+func (m *MaintenanceWindowService) Create(ctx context.Context, req *dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	field, err := m.repository.GetField().FindByUUID(ctx, req.FieldID)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, field.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	startDate, _ := time.Parse(time.DateOnly, req.StartDate)
+	endDate, _ := time.Parse(time.DateOnly, req.EndDate)
+	if endDate.Before(startDate) || req.StartTime >= req.EndTime {
+		return nil, errMaintenanceWindow.ErrInvalidMaintenanceWindowRange
+	}
+
+	var (
+		window    *models.MaintenanceWindow
+		cancelled []models.FieldSchedule
+	)
+	err = m.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		fieldSchedules, txErr := m.repository.GetFieldSchedule().FindAllOverlappingMaintenance(ctx, tx, &models.MaintenanceWindow{
+			FieldID:   field.ID,
+			StartDate: startDate,
+			EndDate:   endDate,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		ids := make([]uint, 0, len(fieldSchedules))
+		for _, fieldSchedule := range fieldSchedules {
+			switch fieldSchedule.Status {
+			case constants.Booked:
+				if !req.Force {
+					return errMaintenanceWindow.ErrMaintenanceWindowHasBooked
+				}
+				cancelled = append(cancelled, fieldSchedule)
+				ids = append(ids, fieldSchedule.ID)
+			case constants.Available, constants.Blocked:
+				ids = append(ids, fieldSchedule.ID)
+			}
+		}
+
+		window, txErr = m.repository.GetMaintenanceWindow().Create(ctx, tx, &models.MaintenanceWindow{
+			FieldID:   field.ID,
+			StartDate: startDate,
+			EndDate:   endDate,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+			Reason:    req.Reason,
+		})
+		if txErr != nil {
+			return txErr
+		}
+		return m.repository.GetFieldSchedule().UpdateStatusByIDs(ctx, tx, ids, constants.Maintenance, window.UUID.String())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	window.Field = *field
+	response := m.toResponse(window, len(cancelled))
+	return &response, nil
+}
+
+func (m *MaintenanceWindowService) Delete(ctx context.Context, uuid string) error {
+	window, err := m.repository.GetMaintenanceWindow().FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	err = policy.CheckOwnership(ctx, window.Field.OwnerUUID)
+	if err != nil {
+		return err
+	}
+	err = m.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		txErr := m.repository.GetMaintenanceWindow().Delete(ctx, tx, uuid)
+		if txErr != nil {
+			return txErr
+		}
+		return m.repository.GetFieldSchedule().ReleaseMaintenance(ctx, tx, window)
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
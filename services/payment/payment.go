@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+type IPaymentService interface {
+	HandleEvent(context.Context, string, *dto.PaymentEvent) error
+}
+
+func NewPaymentService(repository repositories.IRepositoryRegistry) IPaymentService {
+	return &PaymentService{repository: repository}
+}
+
+// supersedes reports whether the event is newer than the result already
+// applied to the order. A refund is final.
+func (p *PaymentService) supersedes(payment *models.Payment, request *dto.PaymentEvent) bool {
+	if payment == nil {
+		return true
+	}
+	if payment.Status == constants.PaymentRefunded {
+		return false
+	}
+	return request.OccurredAt.After(payment.OccurredAt)
+}
+
+// HandleEvent books the order's schedules when it is paid and releases them
+// when the payment expires, fails or is refunded. Redelivered events are
+// skipped by their ID, and a result older than the one already applied to the
+// order is ignored, so events may arrive twice and in any order.
+func (p *PaymentService) HandleEvent(ctx context.Context, eventID string, request *dto.PaymentEvent) error {
+	return p.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		isNew, err := p.repository.GetInbox().Record(ctx, tx, &models.InboxEvent{
+			EventID: eventID,
+			Type:    request.Status,
+		})
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			logrus.Infof("skipping payment event %s already handled", eventID)
+			return nil
+		}
+
+		payment, err := p.repository.GetPayment().FindByOrderID(ctx, tx, request.OrderID)
+		if err != nil {
+			return err
+		}
+		if !p.supersedes(payment, request) {
+			logrus.Infof("skipping %s payment event %s of order %s older than %s", request.Status, eventID, request.OrderID, payment.Status)
+			return nil
+		}
+
+		if request.Status == constants.PaymentPaid {
+			err = p.saveCustomer(ctx, tx, request)
+			if err != nil {
+				return err
+			}
+			err = p.book(ctx, tx, request)
+		} else {
+			err = p.release(ctx, tx, request)
+		}
+		if err != nil {
+			return err
+		}
+
+		if payment == nil {
+			payment = &models.Payment{OrderID: request.OrderID}
+		}
+		payment.Status = request.Status
+		payment.OccurredAt = request.OccurredAt
+		return p.repository.GetPayment().Save(ctx, tx, payment)
+	})
+}
+
+// saveCustomer keeps the customer sent with a paid order, for the reminders
+// of its schedules.
+func (p *PaymentService) saveCustomer(ctx context.Context, tx *gorm.DB, request *dto.PaymentEvent) error {
+	if request.Customer == nil {
+		return nil
+	}
+	return p.repository.GetOrderCustomer().Save(ctx, tx, &models.OrderCustomer{
+		OrderID:      request.OrderID,
+		CustomerUUID: request.Customer.UUID,
+		Name:         request.Customer.Name,
+		Email:        request.Customer.Email,
+		PhoneNumber:  request.Customer.PhoneNumber,
+		Language:     request.Customer.Language,
+	})
+}
+
+// book marks the available schedules of the event Booked for the order. A
+// schedule taken by someone else in the meantime is left alone and logged,
+// for the order to be refunded.
+func (p *PaymentService) book(ctx context.Context, tx *gorm.DB, request *dto.PaymentEvent) error {
+	uuids := make([]string, 0, len(request.ScheduleIDs))
+	for _, scheduleID := range request.ScheduleIDs {
+		uuids = append(uuids, scheduleID.String())
+	}
+	if len(uuids) == 0 {
+		return nil
+	}
+	fieldSchedules, err := p.repository.GetFieldSchedule().FindAllByUUIDs(ctx, tx, uuids)
+	if err != nil {
+		return err
+	}
+	if len(fieldSchedules) < len(uuids) {
+		logrus.Warnf("order %s was paid for %d schedules, only %d exist", request.OrderID, len(uuids), len(fieldSchedules))
+	}
+	ids := make([]uint, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		switch {
+		case fieldSchedule.Status == constants.Available:
+			ids = append(ids, fieldSchedule.ID)
+		case fieldSchedule.Status == constants.Booked && fieldSchedule.OrderID == request.OrderID:
+		default:
+			logrus.Warnf("order %s was paid for schedule %s which is %s", request.OrderID, fieldSchedule.UUID, fieldSchedule.Status.GetStatusString())
+		}
+	}
+	return p.repository.GetFieldSchedule().UpdateStatusByIDs(ctx, tx, ids, constants.Booked, request.OrderID)
+}
+
+// release puts the schedules booked by the order back to Available.
+func (p *PaymentService) release(ctx context.Context, tx *gorm.DB, request *dto.PaymentEvent) error {
+	fieldSchedules, err := p.repository.GetFieldSchedule().FindAllBookedByOrderID(ctx, tx, request.OrderID)
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, 0, len(fieldSchedules))
+	for _, fieldSchedule := range fieldSchedules {
+		ids = append(ids, fieldSchedule.ID)
+	}
+	return p.repository.GetFieldSchedule().UpdateStatusByIDs(ctx, tx, ids, constants.Available, request.OrderID)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"field-service/common/tenant"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testOrderID = "order-1"
+
+func newTestService(t *testing.T) (IPaymentService, *gorm.DB, context.Context, []uuid.UUID) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.Field{}, &models.Time{}, &models.FieldSchedule{}, &models.FieldScheduleHistory{},
+		&models.MaintenanceWindow{}, &models.OutboxEvent{}, &models.InboxEvent{}, &models.Payment{},
+		&models.OrderCustomer{},
+	)
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	ctx := tenant.WithTenant(context.Background(), "tenant-a")
+	ctx = context.WithValue(ctx, constants.ServiceName, constants.PaymentEventActor)
+	field := models.Field{UUID: uuid.New(), Code: "A1", Name: "Field A1", PricePerHour: 100000, Images: []string{}}
+	if err = db.WithContext(ctx).Create(&field).Error; err != nil {
+		t.Fatalf("failed to create field: %v", err)
+	}
+	scheduleTime := models.Time{UUID: uuid.New(), StartTime: "08:00:00", EndTime: "09:00:00"}
+	if err = db.WithContext(ctx).Create(&scheduleTime).Error; err != nil {
+		t.Fatalf("failed to create time: %v", err)
+	}
+	scheduleIDs := make([]uuid.UUID, 0, 2)
+	for day := 1; day <= 2; day++ {
+		fieldSchedule := models.FieldSchedule{
+			UUID:    uuid.New(),
+			FieldID: field.ID,
+			TimeID:  scheduleTime.ID,
+			Date:    time.Now().AddDate(0, 0, day),
+			Status:  constants.Available,
+		}
+		if err = db.WithContext(ctx).Create(&fieldSchedule).Error; err != nil {
+			t.Fatalf("failed to create schedule: %v", err)
+		}
+		scheduleIDs = append(scheduleIDs, fieldSchedule.UUID)
+	}
+	return NewPaymentService(repositories.NewRepositoryRegistry(db)), db, ctx, scheduleIDs
+}
+
+func assertStatus(t *testing.T, db *gorm.DB, ctx context.Context, want constants.FieldScheduleStatus, wantOrderID string) {
+	t.Helper()
+	var fieldSchedules []models.FieldSchedule
+	if err := db.WithContext(ctx).Find(&fieldSchedules).Error; err != nil {
+		t.Fatalf("failed to find schedules: %v", err)
+	}
+	for _, fieldSchedule := range fieldSchedules {
+		if fieldSchedule.Status != want || fieldSchedule.OrderID != wantOrderID {
+			t.Errorf("schedule %s = %s for %q, want %s for %q",
+				fieldSchedule.UUID, fieldSchedule.Status.GetStatusString(), fieldSchedule.OrderID, want.GetStatusString(), wantOrderID)
+		}
+	}
+}
+
+func paymentEvent(status string, occurredAt time.Time, scheduleIDs []uuid.UUID) *dto.PaymentEvent {
+	return &dto.PaymentEvent{OrderID: testOrderID, Status: status, ScheduleIDs: scheduleIDs, OccurredAt: occurredAt}
+}
+
+func TestHandleEventBooksAndReleases(t *testing.T) {
+	service, db, ctx, scheduleIDs := newTestService(t)
+	now := time.Now()
+
+	if err := service.HandleEvent(ctx, "event-1", paymentEvent(constants.PaymentPaid, now, scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle paid event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Booked, testOrderID)
+
+	if err := service.HandleEvent(ctx, "event-2", paymentEvent(constants.PaymentExpired, now.Add(time.Minute), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle expired event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Available, "")
+}
+
+func TestHandleEventRecordsBookedPrice(t *testing.T) {
+	service, db, ctx, scheduleIDs := newTestService(t)
+	now := time.Now()
+
+	if err := service.HandleEvent(ctx, "event-1", paymentEvent(constants.PaymentPaid, now, scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle paid event: %v", err)
+	}
+	// A later change of price leaves the bookings at the price they were made.
+	db.WithContext(ctx).Model(&models.Field{}).Where("code = ?", "A1").Update("price_per_hour", 150000)
+	var fieldSchedules []models.FieldSchedule
+	db.WithContext(ctx).Find(&fieldSchedules)
+	for _, fieldSchedule := range fieldSchedules {
+		if fieldSchedule.Price != 100000 {
+			t.Errorf("schedule %s booked at %d, want 100000", fieldSchedule.UUID, fieldSchedule.Price)
+		}
+	}
+
+	if err := service.HandleEvent(ctx, "event-2", paymentEvent(constants.PaymentExpired, now.Add(time.Minute), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle expired event: %v", err)
+	}
+	db.WithContext(ctx).Find(&fieldSchedules)
+	for _, fieldSchedule := range fieldSchedules {
+		if fieldSchedule.Price != 0 {
+			t.Errorf("released schedule %s keeps price %d", fieldSchedule.UUID, fieldSchedule.Price)
+		}
+	}
+}
+
+func TestHandleEventSkipsRedeliveredEvent(t *testing.T) {
+	service, db, ctx, scheduleIDs := newTestService(t)
+	now := time.Now()
+
+	if err := service.HandleEvent(ctx, "event-1", paymentEvent(constants.PaymentPaid, now, scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle paid event: %v", err)
+	}
+	// A redelivery is skipped by its ID even when its content is newer.
+	if err := service.HandleEvent(ctx, "event-1", paymentEvent(constants.PaymentExpired, now.Add(time.Minute), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle redelivered event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Booked, testOrderID)
+
+	var inboxEvents int64
+	db.WithContext(ctx).Model(&models.InboxEvent{}).Count(&inboxEvents)
+	if inboxEvents != 1 {
+		t.Errorf("inbox holds %d events, want 1", inboxEvents)
+	}
+}
+
+func TestHandleEventIgnoresSupersededResults(t *testing.T) {
+	service, db, ctx, scheduleIDs := newTestService(t)
+	now := time.Now()
+
+	if err := service.HandleEvent(ctx, "event-paid", paymentEvent(constants.PaymentPaid, now, scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle paid event: %v", err)
+	}
+	// An expiry that happened before the payment arrives late.
+	if err := service.HandleEvent(ctx, "event-expired", paymentEvent(constants.PaymentExpired, now.Add(-time.Minute), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle expired event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Booked, testOrderID)
+
+	if err := service.HandleEvent(ctx, "event-refunded", paymentEvent(constants.PaymentRefunded, now.Add(time.Minute), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle refunded event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Available, "")
+
+	// A refund is final, even against a later payment.
+	if err := service.HandleEvent(ctx, "event-paid-again", paymentEvent(constants.PaymentPaid, now.Add(time.Hour), scheduleIDs)); err != nil {
+		t.Fatalf("failed to handle paid event: %v", err)
+	}
+	assertStatus(t, db, ctx, constants.Available, "")
+
+	var payment models.Payment
+	if err := db.WithContext(ctx).Where("order_id = ?", testOrderID).First(&payment).Error; err != nil {
+		t.Fatalf("failed to find payment: %v", err)
+	}
+	if payment.Status != constants.PaymentRefunded {
+		t.Errorf("payment = %s, want refunded", payment.Status)
+	}
+}
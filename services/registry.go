@@ -2,37 +2,116 @@ package services
 
 import (
 	"field-service/common/gcs"
+	"field-service/common/pubsub"
 	"field-service/repositories"
+	analyticsService "field-service/services/analytics"
+	auditLogService "field-service/services/auditLog"
+	availabilityService "field-service/services/availability"
+	calendarService "field-service/services/calendar"
+	checkInService "field-service/services/checkIn"
+	dailySheetService "field-service/services/dailySheet"
+	exportService "field-service/services/export"
 	fieldService "field-service/services/field"
 	fieldScheduleService "field-service/services/fieldSchedule"
+	importerService "field-service/services/importer"
+	maintenanceWindowService "field-service/services/maintenanceWindow"
+	paymentService "field-service/services/payment"
 	timeService "field-service/services/time"
+	webhookService "field-service/services/webhook"
 )
 
 type Registry struct {
 	repository repositories.IRepositoryRegistry
 	gcs        gcs.IGCSClient
+	pubSub     pubsub.IPubSub
 }
 
 type IServiceRegistry interface {
 	GetField() fieldService.IFieldService
 	GetFieldSchedule() fieldScheduleService.IFieldScheduleService
 	GetTime() timeService.ITimeService
+	GetMaintenanceWindow() maintenanceWindowService.IMaintenanceWindowService
+	GetAuditLog() auditLogService.IAuditLogService
+	GetPayment() paymentService.IPaymentService
+	GetWebhook() webhookService.IWebhookService
+	GetAvailability() availabilityService.IAvailabilityService
+	GetCheckIn() checkInService.ICheckInService
+	GetCalendar() calendarService.ICalendarService
+	GetImporter() importerService.IImporterService
+	GetExport() exportService.IExportService
+	GetAnalytics() analyticsService.IAnalyticsService
+	GetDailySheet() dailySheetService.IDailySheetService
 }
 
-func NewServiceRegistry(repository repositories.IRepositoryRegistry, gcs gcs.IGCSClient) IServiceRegistry {
-	return &Registry{repository: repository, gcs: gcs}
+func NewServiceRegistry(repository repositories.IRepositoryRegistry, gcs gcs.IGCSClient, pubSub pubsub.IPubSub) IServiceRegistry {
+	return &Registry{repository: repository, gcs: gcs, pubSub: pubSub}
 }
 
 func (r *Registry) GetField() fieldService.IFieldService {
-	return fieldService.NewFieldService(r.repository, r.gcs)
+	return fieldService.NewFieldService(r.repository, r.gcs, r.GetAuditLog())
 }
 
 // GetFieldSchedule implements IServiceRegistry.
 func (r *Registry) GetFieldSchedule() fieldScheduleService.IFieldScheduleService {
-	return fieldScheduleService.NewFieldScheduleService(r.repository)
+	return fieldScheduleService.NewFieldScheduleService(r.repository, r.GetAuditLog())
 }
 
 // GetTime implements IServiceRegistry.
 func (r *Registry) GetTime() timeService.ITimeService {
-	return timeService.NewTimeService(r.repository)
+	return timeService.NewTimeService(r.repository, r.GetAuditLog())
+}
+
+// GetMaintenanceWindow implements IServiceRegistry.
+func (r *Registry) GetMaintenanceWindow() maintenanceWindowService.IMaintenanceWindowService {
+	return maintenanceWindowService.NewMaintenanceWindowService(r.repository)
+}
+
+// GetAuditLog implements IServiceRegistry.
+func (r *Registry) GetAuditLog() auditLogService.IAuditLogService {
+	return auditLogService.NewAuditLogService(r.repository)
+}
+
+// GetPayment implements IServiceRegistry.
+func (r *Registry) GetPayment() paymentService.IPaymentService {
+	return paymentService.NewPaymentService(r.repository)
+}
+
+// GetWebhook implements IServiceRegistry.
+func (r *Registry) GetWebhook() webhookService.IWebhookService {
+	return webhookService.NewWebhookService(r.repository)
+}
+
+// GetAvailability implements IServiceRegistry.
+func (r *Registry) GetAvailability() availabilityService.IAvailabilityService {
+	return availabilityService.NewAvailabilityService(r.repository, r.pubSub)
+}
+
+// GetCheckIn implements IServiceRegistry.
+func (r *Registry) GetCheckIn() checkInService.ICheckInService {
+	return checkInService.NewCheckInService(r.repository, r.GetAuditLog())
+}
+
+// GetCalendar implements IServiceRegistry.
+func (r *Registry) GetCalendar() calendarService.ICalendarService {
+	return calendarService.NewCalendarService(r.repository)
+}
+
+// GetImporter implements IServiceRegistry.
+func (r *Registry) GetImporter() importerService.IImporterService {
+	return importerService.NewImporterService(r.repository, r.GetAuditLog())
+}
+
+// GetExport implements IServiceRegistry.
+func (r *Registry) GetExport() exportService.IExportService {
+	return exportService.NewExportService(r.repository, r.gcs)
+}
+
+// GetAnalytics implements IServiceRegistry.
+func (r *Registry) GetAnalytics() analyticsService.IAnalyticsService {
+	return analyticsService.NewAnalyticsService(r.repository)
+}
+
+// GetDailySheet implements IServiceRegistry.
+func (r *Registry) GetDailySheet() dailySheetService.IDailySheetService {
+	return dailySheetService.NewDailySheetService(r.repository)
 }
@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
+	"field-service/constants"
 	"field-service/domain/dto"
 	"field-service/domain/models"
 	"field-service/repositories"
+	auditLogService "field-service/services/auditLog"
+
+	"gorm.io/gorm"
 )
 
 type TimeService struct {
 	repository repositories.IRepositoryRegistry
+	audit      auditLogService.IAuditLogService
 }
 
 type ITimeService interface {
@@ -17,8 +22,8 @@ type ITimeService interface {
 	Create(context.Context, *dto.TimeRequest) (*dto.TimeResponse, error)
 }
 
-func NewTimeService(repository repositories.IRepositoryRegistry) ITimeService {
-	return &TimeService{repository: repository}
+func NewTimeService(repository repositories.IRepositoryRegistry, audit auditLogService.IAuditLogService) ITimeService {
+	return &TimeService{repository: repository, audit: audit}
 }
 
 func (t *TimeService) GetAll(ctx context.Context) ([]dto.TimeResponse, error) {
@@ -59,7 +64,20 @@ func (t *TimeService) Create(ctx context.Context, req *dto.TimeRequest) (*dto.Ti
 		StartTime: req.StartTime,
 		EndTime:   req.EndTime,
 	}
-	timeResult, err := t.repository.GetTime().Create(ctx, &timeRequest)
+	var timeResult *models.Time
+	err := t.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		timeResult, txErr = t.repository.GetTime().Create(ctx, tx, &timeRequest)
+		if txErr != nil {
+			return txErr
+		}
+		return t.audit.Record(ctx, tx, dto.AuditEntry{
+			Action:     constants.AuditCreate,
+			Entity:     constants.AuditEntityTime,
+			EntityUUID: timeResult.UUID,
+			After:      timeResult,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
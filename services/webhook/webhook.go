@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"field-service/common/event"
+	"field-service/common/policy"
+	"field-service/common/safehttp"
+	"field-service/common/util"
+	"field-service/constants"
+	errConstant "field-service/constants/error"
+	errField "field-service/constants/error/field"
+	errWebhook "field-service/constants/error/webhook"
+	"field-service/domain/dto"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookService struct {
+	repository repositories.IRepositoryRegistry
+}
+
+// IWebhookService manages partner webhooks. It is also an event publisher:
+// the outbox relay hands it every event so it can queue the deliveries.
+type IWebhookService interface {
+	GetAll(context.Context) ([]dto.WebhookResponse, error)
+	GetByUUID(context.Context, string) (*dto.WebhookResponse, error)
+	Create(context.Context, *dto.WebhookRequest) (*dto.WebhookResponse, error)
+	Update(context.Context, string, *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(context.Context, string) error
+	GetDeliveries(context.Context, string, *dto.WebhookDeliveryRequestParam) (*util.PaginationResult, error)
+	Redeliver(context.Context, string) error
+	Publish(context.Context, event.Event) error
+}
+
+func NewWebhookService(repository repositories.IRepositoryRegistry) IWebhookService {
+	return &WebhookService{repository: repository}
+}
+
+func (w *WebhookService) toResponse(webhook *models.Webhook) dto.WebhookResponse {
+	response := dto.WebhookResponse{
+		UUID:                webhook.UUID,
+		TargetURL:           webhook.TargetURL,
+		EventTypes:          webhook.EventTypes,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+	if webhook.Field != nil {
+		response.FieldID = &webhook.Field.UUID
+		response.FieldName = webhook.Field.Name
+	}
+	return response
+}
+
+func (w *WebhookService) generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func (w *WebhookService) GetAll(ctx context.Context) ([]dto.WebhookResponse, error) {
+	var ownerUUID *uuid.UUID
+	if policy.IsOwnScope(ctx) {
+		user := policy.UserFromContext(ctx)
+		if user == nil {
+			return nil, errConstant.ErrForbidden
+		}
+		ownerUUID = &user.UUID
+	}
+	webhooks, err := w.repository.GetWebhook().FindAll(ctx, ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+	webhookResults := make([]dto.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookResults = append(webhookResults, w.toResponse(&webhook))
+	}
+	return webhookResults, nil
+}
+
+func (w *WebhookService) GetByUUID(ctx context.Context, uuid string) (*dto.WebhookResponse, error) {
+	webhook, err := w.repository.GetWebhook().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, &webhook.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	response := w.toResponse(webhook)
+	return &response, nil
+}
+
+// checkTargetURL rejects a target that is not https or resolves to a loopback,
+// private or link-local address. The dispatcher checks the address again when
+// it connects.
+func (w *WebhookService) checkTargetURL(ctx context.Context, targetURL string) error {
+	err := safehttp.ValidateURL(ctx, targetURL)
+	if err != nil {
+		logrus.Warnf("rejected webhook target %s: %v", targetURL, err)
+		return errWebhook.ErrWebhookTargetNotAllowed
+	}
+	return nil
+}
+
+// Create subscribes to one field, or to every field of the user when no field
+// is given. The secret is generated unless provided, and only returned here.
+func (w *WebhookService) Create(ctx context.Context, request *dto.WebhookRequest) (*dto.WebhookResponse, error) {
+	user := policy.UserFromContext(ctx)
+	if user == nil {
+		return nil, errConstant.ErrForbidden
+	}
+	err := w.checkTargetURL(ctx, request.TargetURL)
+	if err != nil {
+		return nil, err
+	}
+	webhook := &models.Webhook{
+		OwnerUUID:  user.UUID,
+		TargetURL:  request.TargetURL,
+		EventTypes: request.EventTypes,
+	}
+	if request.FieldID != nil && *request.FieldID != "" {
+		field, err := w.repository.GetField().FindByUUID(ctx, *request.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		err = policy.CheckOwnership(ctx, field.OwnerUUID)
+		if err != nil {
+			return nil, err
+		}
+		if field.OwnerUUID != nil {
+			webhook.OwnerUUID = *field.OwnerUUID
+		}
+		webhook.FieldID = &field.ID
+		webhook.Field = field
+	}
+	if request.Secret != nil && *request.Secret != "" {
+		webhook.Secret = *request.Secret
+	} else {
+		secret, err := w.generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+
+	webhook, err = w.repository.GetWebhook().Create(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	response := w.toResponse(webhook)
+	response.Secret = webhook.Secret
+	return &response, nil
+}
+
+func (w *WebhookService) Update(ctx context.Context, uuid string, request *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := w.repository.GetWebhook().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, &webhook.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	err = w.checkTargetURL(ctx, request.TargetURL)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err = w.repository.GetWebhook().Update(ctx, uuid, &models.Webhook{
+		TargetURL:  request.TargetURL,
+		EventTypes: request.EventTypes,
+		Active:     request.Active,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := w.toResponse(webhook)
+	return &response, nil
+}
+
+func (w *WebhookService) Delete(ctx context.Context, uuid string) error {
+	webhook, err := w.repository.GetWebhook().FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	err = policy.CheckOwnership(ctx, &webhook.OwnerUUID)
+	if err != nil {
+		return err
+	}
+	return w.repository.GetWebhook().Delete(ctx, uuid)
+}
+
+func (w *WebhookService) GetDeliveries(ctx context.Context, uuid string, param *dto.WebhookDeliveryRequestParam) (*util.PaginationResult, error) {
+	webhook, err := w.repository.GetWebhook().FindByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = policy.CheckOwnership(ctx, &webhook.OwnerUUID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, total, err := w.repository.GetWebhookDelivery().FindAllWithPagination(ctx, webhook.ID, param)
+	if err != nil {
+		return nil, err
+	}
+	deliveryResults := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryResults = append(deliveryResults, dto.WebhookDeliveryResponse{
+			UUID:           delivery.UUID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        json.RawMessage(delivery.Payload),
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			DeliveredAt:    delivery.DeliveredAt,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+	pagination := &util.PaginationParam{
+		Count: total,
+		Page:  param.Page,
+		Limit: param.Limit,
+		Data:  deliveryResults,
+	}
+	response := util.GeneratePagination(*pagination)
+	return &response, nil
+}
+
+// Redeliver sends a delivery again, whatever its outcome so far, as long as
+// its webhook is active.
+func (w *WebhookService) Redeliver(ctx context.Context, uuid string) error {
+	delivery, err := w.repository.GetWebhookDelivery().FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if delivery.Webhook == nil {
+		return errWebhook.ErrWebhookNotFound
+	}
+	err = policy.CheckOwnership(ctx, &delivery.Webhook.OwnerUUID)
+	if err != nil {
+		return err
+	}
+	if !delivery.Webhook.Active {
+		return errWebhook.ErrWebhookDisabled
+	}
+	return w.repository.GetWebhookDelivery().Redeliver(ctx, delivery.ID)
+}
+
+// Publish queues a delivery of the schedule event to every webhook subscribed
+// to it. Events about fields that no longer exist are dropped.
+func (w *WebhookService) Publish(ctx context.Context, ev event.Event) error {
+	data, err := json.Marshal(ev.Payload)
+	if err != nil {
+		return err
+	}
+	var scheduleEvent dto.FieldScheduleEvent
+	if json.Unmarshal(data, &scheduleEvent) != nil || scheduleEvent.FieldUUID == uuid.Nil {
+		return nil
+	}
+	field, err := w.repository.GetField().FindByUUID(ctx, scheduleEvent.FieldUUID.String())
+	if err != nil {
+		if errors.Is(err, errField.ErrFieldNotFound) {
+			logrus.Warnf("skipping webhooks of event %s for missing field %s", ev.ID, scheduleEvent.FieldUUID)
+			return nil
+		}
+		return err
+	}
+	webhooks, err := w.repository.GetWebhook().FindAllSubscribed(ctx, ev.TenantID, field, ev.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(dto.WebhookPayload{
+		ID:         ev.ID,
+		Type:       ev.Type,
+		OccurredAt: ev.OccurredAt,
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			UUID:          uuid.New(),
+			TenantID:      webhook.TenantID,
+			WebhookID:     webhook.ID,
+			EventID:       ev.ID,
+			EventType:     ev.Type,
+			Payload:       string(body),
+			Status:        constants.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	return w.repository.GetWebhookDelivery().Create(ctx, deliveries)
+}
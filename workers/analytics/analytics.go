@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"context"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/repositories"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	// daysPerRefresh is how many days are recomputed in one transaction.
+	daysPerRefresh = 31
+	// changeOverlap looks back past the previous refresh for changes that
+	// were still being committed when it read the schedules.
+	changeOverlap = time.Minute
+)
+
+type RollupWorker struct {
+	repository   repositories.IRepositoryRegistry
+	pollInterval time.Duration
+	since        *time.Time
+}
+
+type IRollupWorker interface {
+	Run(context.Context)
+}
+
+func NewRollupWorker(repository repositories.IRepositoryRegistry) IRollupWorker {
+	worker := &RollupWorker{
+		repository:   repository,
+		pollInterval: time.Duration(config.Config.Analytics.PollIntervalSecond) * time.Second,
+	}
+	if worker.pollInterval <= 0 {
+		worker.pollInterval = defaultPollInterval
+	}
+	return worker
+}
+
+// Run keeps the schedule rollups up to date until the context is cancelled.
+// The first pass continues from the latest refresh, or rolls up every day
+// when there is none yet.
+func (w *RollupWorker) Run(ctx context.Context) {
+	latest, err := w.repository.GetFieldScheduleRollup().FindLatestRefreshedAt(tenant.Unscoped(ctx))
+	if err != nil {
+		logrus.Errorf("failed to find latest schedule rollup: %v", err)
+	}
+	if latest != nil {
+		since := latest.Add(-changeOverlap)
+		w.since = &since
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		err = w.refresh(ctx)
+		if err != nil {
+			logrus.Errorf("failed to refresh schedule rollups: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh recomputes the rollups of the days changed since the previous pass,
+// each tenant within its own scope. The next pass only moves on when every
+// day was refreshed.
+func (w *RollupWorker) refresh(ctx context.Context) error {
+	startedAt := time.Now()
+	daysByTenant, err := w.repository.GetFieldScheduleRollup().FindDays(tenant.Unscoped(ctx), w.since)
+	if err != nil {
+		return err
+	}
+	for tenantID, days := range daysByTenant {
+		tenantCtx := tenant.WithTenant(ctx, tenantID)
+		for start := 0; start < len(days); start += daysPerRefresh {
+			end := start + daysPerRefresh
+			if end > len(days) {
+				end = len(days)
+			}
+			err = w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+				return w.repository.GetFieldScheduleRollup().Refresh(tenantCtx, tx, days[start:end], startedAt)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	since := startedAt.Add(-changeOverlap)
+	w.since = &since
+	return nil
+}
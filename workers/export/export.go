@@ -0,0 +1,115 @@
+package workers
+
+import (
+	"context"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"field-service/services"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	// defaultRunTimeout bounds a single job. A job still running after it is
+	// presumed lost with its worker and claimed again.
+	defaultRunTimeout = 30 * time.Minute
+)
+
+type ExportWorker struct {
+	repository   repositories.IRepositoryRegistry
+	service      services.IServiceRegistry
+	pollInterval time.Duration
+	runTimeout   time.Duration
+}
+
+type IExportWorker interface {
+	Run(context.Context)
+}
+
+func NewExportWorker(repository repositories.IRepositoryRegistry, service services.IServiceRegistry) IExportWorker {
+	worker := &ExportWorker{
+		repository:   repository,
+		service:      service,
+		pollInterval: time.Duration(config.Config.Export.PollIntervalSecond) * time.Second,
+		runTimeout:   defaultRunTimeout,
+	}
+	if worker.pollInterval <= 0 {
+		worker.pollInterval = defaultPollInterval
+	}
+	return worker
+}
+
+// Run writes the pending export jobs to storage until the context is
+// cancelled.
+func (w *ExportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			picked, err := w.runNext(ctx)
+			if err != nil {
+				logrus.Errorf("failed to run export job: %v", err)
+				break
+			}
+			if !picked {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim marks the next job of any tenant running, so that no other replica
+// picks it up while it is written.
+func (w *ExportWorker) claim(ctx context.Context) (*models.ExportJob, error) {
+	var job *models.ExportJob
+	err := w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var err error
+		job, err = w.repository.GetExportJob().FindNext(ctx, tx, time.Now().Add(-w.runTimeout))
+		if err != nil || job == nil {
+			return err
+		}
+		now := time.Now()
+		job.Status = constants.ExportJobRunning
+		job.StartedAt = &now
+		return w.repository.GetExportJob().Update(ctx, tx, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// runNext runs the next job within its tenant and records how it ended. It
+// reports whether there was a job to run.
+func (w *ExportWorker) runNext(ctx context.Context) (bool, error) {
+	job, err := w.claim(tenant.Unscoped(ctx))
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	runCtx, cancel := context.WithTimeout(tenant.WithTenant(ctx, job.TenantID), w.runTimeout)
+	defer cancel()
+	rowCount, err := w.service.GetExport().RunJob(runCtx, job)
+	now := time.Now()
+	job.RowCount = rowCount
+	job.FinishedAt = &now
+	job.Status = constants.ExportJobDone
+	if err != nil {
+		logrus.Errorf("failed to write export job %s: %v", job.UUID, err)
+		job.Status = constants.ExportJobFailed
+		job.LastError = err.Error()
+	}
+	err = w.repository.GetExportJob().Update(tenant.WithTenant(ctx, job.TenantID), w.repository.GetTx(), job)
+	return true, err
+}
@@ -0,0 +1,98 @@
+package workers
+
+import (
+	"context"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/repositories"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultBatchSize    = 100
+)
+
+type NoShowWorker struct {
+	repository   repositories.IRepositoryRegistry
+	noShowAfter  time.Duration
+	pollInterval time.Duration
+	batchSize    int
+}
+
+type INoShowWorker interface {
+	Run(context.Context)
+}
+
+func NewNoShowWorker(repository repositories.IRepositoryRegistry) INoShowWorker {
+	cfg := config.Config.CheckIn
+	worker := &NoShowWorker{
+		repository:   repository,
+		noShowAfter:  time.Duration(cfg.NoShowAfterMinutes) * time.Minute,
+		pollInterval: time.Duration(cfg.PollIntervalSecond) * time.Second,
+		batchSize:    cfg.BatchSize,
+	}
+	if worker.noShowAfter <= 0 {
+		worker.noShowAfter = constants.DefaultNoShowAfterMinutes * time.Minute
+	}
+	if worker.pollInterval <= 0 {
+		worker.pollInterval = defaultPollInterval
+	}
+	if worker.batchSize <= 0 {
+		worker.batchSize = defaultBatchSize
+	}
+	return worker
+}
+
+// Run marks bookings NoShow until the context is cancelled.
+func (w *NoShowWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			picked, err := w.markBatch(ctx)
+			if err != nil {
+				logrus.Errorf("failed to mark no-shows: %v", err)
+				break
+			}
+			if picked < w.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// markBatch marks NoShow one batch of bookings not checked in within
+// noShowAfter of their start, and returns how many were picked up. The
+// bookings are found across tenants and changed within their own tenant, so
+// their history and events are recorded for it.
+func (w *NoShowWorker) markBatch(ctx context.Context) (int, error) {
+	fieldSchedules, err := w.repository.GetFieldSchedule().FindAllBookedStartedBefore(
+		tenant.Unscoped(ctx), time.Now().Add(-w.noShowAfter), w.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	idsByTenant := map[string][]uint{}
+	for _, fieldSchedule := range fieldSchedules {
+		idsByTenant[fieldSchedule.TenantID] = append(idsByTenant[fieldSchedule.TenantID], fieldSchedule.ID)
+	}
+	for tenantID, ids := range idsByTenant {
+		tenantCtx := context.WithValue(tenant.WithTenant(ctx, tenantID), constants.ServiceName, constants.NoShowActor)
+		err = w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+			return w.repository.GetFieldSchedule().UpdateStatusByIDs(tenantCtx, tx, ids, constants.NoShow, "")
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(fieldSchedules), nil
+}
@@ -0,0 +1,181 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"field-service/common/event"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 10
+	defaultBaseBackoff  = 5 * time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+	claimTimeout        = time.Minute
+)
+
+type OutboxRelay struct {
+	repository   repositories.IRepositoryRegistry
+	publisher    event.IFanoutPublisher
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+}
+
+type IOutboxRelay interface {
+	Run(context.Context)
+}
+
+func NewOutboxRelay(repository repositories.IRepositoryRegistry, publisher event.IFanoutPublisher) IOutboxRelay {
+	cfg := config.Config.Outbox
+	relay := &OutboxRelay{
+		repository:   repository,
+		publisher:    publisher,
+		pollInterval: time.Duration(cfg.PollIntervalSecond) * time.Second,
+		batchSize:    cfg.BatchSize,
+		maxAttempts:  cfg.MaxAttempts,
+		baseBackoff:  time.Duration(cfg.BaseBackoffSecond) * time.Second,
+		maxBackoff:   time.Duration(cfg.MaxBackoffSecond) * time.Second,
+	}
+	if relay.pollInterval <= 0 {
+		relay.pollInterval = defaultPollInterval
+	}
+	if relay.batchSize <= 0 {
+		relay.batchSize = defaultBatchSize
+	}
+	if relay.maxAttempts <= 0 {
+		relay.maxAttempts = defaultMaxAttempts
+	}
+	if relay.baseBackoff <= 0 {
+		relay.baseBackoff = defaultBaseBackoff
+	}
+	if relay.maxBackoff <= 0 {
+		relay.maxBackoff = defaultMaxBackoff
+	}
+	return relay
+}
+
+// Run publishes due outbox events until the context is cancelled. Delivery
+// is at least once: an event whose outcome could not be recorded is sent
+// again, with the same ID, once its claim lapses.
+func (o *OutboxRelay) Run(ctx context.Context) {
+	ctx = tenant.Unscoped(ctx)
+	ticker := time.NewTicker(o.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			picked, err := o.relayBatch(ctx)
+			if err != nil {
+				logrus.Errorf("failed to relay outbox events: %v", err)
+				break
+			}
+			if picked < o.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backoff doubles the base delay with every attempt, up to the maximum.
+func (o *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := o.baseBackoff
+	for i := 1; i < attempts && delay < o.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.maxBackoff {
+		delay = o.maxBackoff
+	}
+	return delay
+}
+
+// claim locks the due events and pushes their next attempt past claimTimeout,
+// so that other relays leave them alone while they are published.
+func (o *OutboxRelay) claim(ctx context.Context) ([]models.OutboxEvent, error) {
+	var outboxEvents []models.OutboxEvent
+	err := o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var err error
+		outboxEvents, err = o.repository.GetOutbox().FindAllDue(ctx, tx, o.batchSize)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, 0, len(outboxEvents))
+		for _, outboxEvent := range outboxEvents {
+			ids = append(ids, outboxEvent.ID)
+		}
+		return o.repository.GetOutbox().Claim(ctx, tx, ids, time.Now().Add(claimTimeout))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outboxEvents, nil
+}
+
+// relayBatch publishes one batch of due events and returns how many were
+// picked up. The events are claimed in one transaction, published without
+// holding any lock, and their outcome recorded in a second transaction. An
+// event that fails on some sinks is rescheduled with backoff for those sinks
+// only, or marked failed once it runs out of attempts.
+func (o *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	outboxEvents, err := o.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	published := make([]uint, 0, len(outboxEvents))
+	var retries []models.OutboxEvent
+	for _, outboxEvent := range outboxEvents {
+		occurredAt := time.Now()
+		if outboxEvent.CreatedAt != nil {
+			occurredAt = *outboxEvent.CreatedAt
+		}
+		accepted, err := o.publisher.PublishExcept(ctx, event.Event{
+			ID:         outboxEvent.UUID.String(),
+			TenantID:   outboxEvent.TenantID,
+			Type:       outboxEvent.Type,
+			Key:        outboxEvent.Key,
+			Payload:    json.RawMessage(outboxEvent.Payload),
+			OccurredAt: occurredAt,
+		}, outboxEvent.PublishedSinks)
+		if err == nil {
+			published = append(published, outboxEvent.ID)
+			continue
+		}
+
+		outboxEvent.PublishedSinks = append(outboxEvent.PublishedSinks, accepted...)
+		outboxEvent.Attempts++
+		outboxEvent.LastError = err.Error()
+		outboxEvent.NextAttemptAt = time.Now().Add(o.backoff(outboxEvent.Attempts))
+		if outboxEvent.Attempts >= o.maxAttempts {
+			outboxEvent.Status = constants.OutboxFailed
+			logrus.Errorf("giving up on outbox event %s after %d attempts: %v", outboxEvent.UUID, outboxEvent.Attempts, err)
+		}
+		retries = append(retries, outboxEvent)
+	}
+
+	err = o.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		for _, outboxEvent := range retries {
+			err := o.repository.GetOutbox().MarkRetry(ctx, tx, &outboxEvent)
+			if err != nil {
+				return err
+			}
+		}
+		return o.repository.GetOutbox().MarkPublished(ctx, tx, published)
+	})
+	return len(outboxEvents), err
+}
@@ -0,0 +1,192 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"field-service/common/event"
+	"field-service/common/tenant"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// flakyPublisher fails the first failures calls and counts every call.
+type flakyPublisher struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyPublisher) Publish(context.Context, event.Event) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("broker down")
+	}
+	return nil
+}
+
+func newTestRelay(t *testing.T, sinks ...event.Sink) (*OutboxRelay, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	if err = db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return &OutboxRelay{
+		repository:   repositories.NewRepositoryRegistry(db),
+		publisher:    event.NewFanoutPublisher(sinks...),
+		pollInterval: time.Second,
+		batchSize:    defaultBatchSize,
+		maxAttempts:  3,
+		baseBackoff:  time.Minute,
+		maxBackoff:   time.Hour,
+	}, db
+}
+
+func createOutboxEvents(t *testing.T, db *gorm.DB, types ...string) []models.OutboxEvent {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), "tenant-a")
+	outboxEvents := make([]models.OutboxEvent, 0, len(types))
+	for _, eventType := range types {
+		outboxEvent := models.OutboxEvent{
+			UUID:          uuid.New(),
+			Type:          eventType,
+			Key:           "schedule-1",
+			Payload:       `{}`,
+			Status:        constants.OutboxPending,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		}
+		if err := db.WithContext(ctx).Create(&outboxEvent).Error; err != nil {
+			t.Fatalf("failed to create outbox event: %v", err)
+		}
+		outboxEvents = append(outboxEvents, outboxEvent)
+	}
+	return outboxEvents
+}
+
+func findOutboxEvents(t *testing.T, db *gorm.DB) []models.OutboxEvent {
+	t.Helper()
+	var outboxEvents []models.OutboxEvent
+	if err := db.WithContext(tenant.Unscoped(context.Background())).Order("id").Find(&outboxEvents).Error; err != nil {
+		t.Fatalf("failed to find outbox events: %v", err)
+	}
+	return outboxEvents
+}
+
+func makeDue(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	err := db.WithContext(tenant.Unscoped(context.Background())).
+		Model(&models.OutboxEvent{}).
+		Where("status = ?", constants.OutboxPending).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error
+	if err != nil {
+		t.Fatalf("failed to make events due: %v", err)
+	}
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	broker := event.NewMemoryPublisher()
+	relay, db := newTestRelay(t, event.Sink{Name: constants.EventSinkBroker, Publisher: broker})
+	created := createOutboxEvents(t, db, constants.FieldScheduleBooked, constants.FieldScheduleCheckedIn, constants.FieldScheduleReleased)
+	ctx := tenant.Unscoped(context.Background())
+
+	picked, err := relay.relayBatch(ctx)
+	if err != nil || picked != 3 {
+		t.Fatalf("picked %d events (%v), want 3", picked, err)
+	}
+	published := broker.Events()
+	if len(published) != len(created) {
+		t.Fatalf("published %d events, want %d", len(published), len(created))
+	}
+	for i, outboxEvent := range created {
+		if published[i].ID != outboxEvent.UUID.String() || published[i].Type != outboxEvent.Type || published[i].TenantID != "tenant-a" {
+			t.Errorf("event %d = %s %s, want %s %s", i, published[i].Type, published[i].ID, outboxEvent.Type, outboxEvent.UUID)
+		}
+	}
+	for _, outboxEvent := range findOutboxEvents(t, db) {
+		if outboxEvent.Status != constants.OutboxPublished || outboxEvent.PublishedAt == nil {
+			t.Errorf("event %s = %s, want published", outboxEvent.UUID, outboxEvent.Status)
+		}
+	}
+
+	if picked, err = relay.relayBatch(ctx); err != nil || picked != 0 {
+		t.Errorf("picked %d events (%v) again after publishing them", picked, err)
+	}
+}
+
+func TestRelayRetriesOnlyFailedSinks(t *testing.T) {
+	broker := &flakyPublisher{failures: 1}
+	webhook := &flakyPublisher{}
+	relay, db := newTestRelay(t,
+		event.Sink{Name: constants.EventSinkBroker, Publisher: broker},
+		event.Sink{Name: constants.EventSinkWebhook, Publisher: webhook},
+	)
+	createOutboxEvents(t, db, constants.FieldScheduleBooked)
+	ctx := tenant.Unscoped(context.Background())
+
+	if _, err := relay.relayBatch(ctx); err != nil {
+		t.Fatalf("failed to relay: %v", err)
+	}
+	outboxEvent := findOutboxEvents(t, db)[0]
+	if outboxEvent.Status != constants.OutboxPending || outboxEvent.Attempts != 1 || outboxEvent.LastError == "" {
+		t.Fatalf("event = %s after %d attempts, want pending after 1 with an error", outboxEvent.Status, outboxEvent.Attempts)
+	}
+	if len(outboxEvent.PublishedSinks) != 1 || outboxEvent.PublishedSinks[0] != constants.EventSinkWebhook {
+		t.Errorf("published sinks = %v, want [webhook]", outboxEvent.PublishedSinks)
+	}
+	if wait := time.Until(outboxEvent.NextAttemptAt); wait < 50*time.Second {
+		t.Errorf("next attempt in %s, want the base backoff", wait)
+	}
+	if picked, err := relay.relayBatch(ctx); err != nil || picked != 0 {
+		t.Fatalf("picked %d events (%v) before the backoff elapsed", picked, err)
+	}
+
+	makeDue(t, db)
+	if _, err := relay.relayBatch(ctx); err != nil {
+		t.Fatalf("failed to relay: %v", err)
+	}
+	if outboxEvent = findOutboxEvents(t, db)[0]; outboxEvent.Status != constants.OutboxPublished {
+		t.Errorf("event = %s, want published on retry", outboxEvent.Status)
+	}
+	if broker.calls != 2 || webhook.calls != 1 {
+		t.Errorf("broker called %d times and webhook %d, want 2 and 1", broker.calls, webhook.calls)
+	}
+}
+
+func TestRelayGivesUpAfterMaxAttempts(t *testing.T) {
+	broker := &flakyPublisher{failures: 100}
+	relay, db := newTestRelay(t, event.Sink{Name: constants.EventSinkBroker, Publisher: broker})
+	createOutboxEvents(t, db, constants.FieldScheduleBooked)
+	ctx := tenant.Unscoped(context.Background())
+
+	for i := 0; i < relay.maxAttempts+1; i++ {
+		makeDue(t, db)
+		if _, err := relay.relayBatch(ctx); err != nil {
+			t.Fatalf("failed to relay: %v", err)
+		}
+	}
+	outboxEvent := findOutboxEvents(t, db)[0]
+	if outboxEvent.Status != constants.OutboxFailed || outboxEvent.Attempts != relay.maxAttempts {
+		t.Errorf("event = %s after %d attempts, want failed after %d", outboxEvent.Status, outboxEvent.Attempts, relay.maxAttempts)
+	}
+	if broker.calls != relay.maxAttempts {
+		t.Errorf("broker called %d times, want %d", broker.calls, relay.maxAttempts)
+	}
+}
@@ -0,0 +1,66 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"field-service/common/event"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/dto"
+	"field-service/services"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentConsumer struct {
+	service    services.IServiceRegistry
+	subscriber event.IEventSubscriber
+}
+
+type IPaymentConsumer interface {
+	Run(context.Context)
+}
+
+func NewPaymentConsumer(service services.IServiceRegistry, subscriber event.IEventSubscriber) IPaymentConsumer {
+	return &PaymentConsumer{service: service, subscriber: subscriber}
+}
+
+// Run consumes payment results until the context is cancelled.
+func (p *PaymentConsumer) Run(ctx context.Context) {
+	err := p.subscriber.Subscribe(ctx, p.handle)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		logrus.Errorf("payment consumer stopped: %v", err)
+	}
+}
+
+// handle applies one payment result within its tenant. A message that cannot
+// be decoded is logged and acknowledged since retrying would not help.
+func (p *PaymentConsumer) handle(ctx context.Context, message event.Message) error {
+	var request dto.PaymentEvent
+	err := json.Unmarshal(message.Payload, &request)
+	if err != nil {
+		logrus.Errorf("dropping undecodable payment message %s: %v", message.ID, err)
+		return nil
+	}
+	err = validator.New().Struct(request)
+	if err != nil {
+		logrus.Errorf("dropping invalid payment message %s: %v", message.ID, err)
+		return nil
+	}
+
+	eventID := message.ID
+	if eventID == "" {
+		eventID = fmt.Sprintf("%s:%s:%d", request.OrderID, request.Status, request.OccurredAt.UnixNano())
+	}
+	tenantID := message.TenantID
+	if tenantID == "" {
+		tenantID = config.Config.DefaultTenantID
+	}
+	ctx = tenant.WithTenant(ctx, tenantID)
+	ctx = context.WithValue(ctx, constants.ServiceName, constants.PaymentEventActor)
+	return p.service.GetPayment().HandleEvent(ctx, eventID, &request)
+}
@@ -0,0 +1,300 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"field-service/common/notification"
+	"field-service/common/tenant"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultBatchSize    = 50
+	defaultMaxAttempts  = 5
+	defaultBaseBackoff  = time.Minute
+	defaultMaxBackoff   = 30 * time.Minute
+	claimTimeout        = 10 * time.Minute
+)
+
+var defaultOffsetMinutes = []int{24 * 60, 2 * 60}
+
+type ReminderWorker struct {
+	repository    repositories.IRepositoryRegistry
+	channels      map[string]notification.IChannel
+	offsetMinutes []int
+	language      string
+	pollInterval  time.Duration
+	batchSize     int
+	maxAttempts   int
+	baseBackoff   time.Duration
+	maxBackoff    time.Duration
+}
+
+type IReminderWorker interface {
+	Run(context.Context)
+}
+
+func NewReminderWorker(repository repositories.IRepositoryRegistry, channels ...notification.IChannel) IReminderWorker {
+	cfg := config.Config.Notification
+	worker := &ReminderWorker{
+		repository:    repository,
+		channels:      make(map[string]notification.IChannel, len(channels)),
+		offsetMinutes: make([]int, 0, len(cfg.ReminderOffsetMinutes)),
+		language:      cfg.DefaultLanguage,
+		pollInterval:  time.Duration(cfg.PollIntervalSecond) * time.Second,
+		batchSize:     cfg.BatchSize,
+		maxAttempts:   cfg.MaxAttempts,
+		baseBackoff:   time.Duration(cfg.BaseBackoffSecond) * time.Second,
+		maxBackoff:    time.Duration(cfg.MaxBackoffSecond) * time.Second,
+	}
+	for _, channel := range channels {
+		worker.channels[channel.Name()] = channel
+	}
+	for _, offset := range cfg.ReminderOffsetMinutes {
+		if offset > 0 {
+			worker.offsetMinutes = append(worker.offsetMinutes, offset)
+		}
+	}
+	if len(worker.offsetMinutes) == 0 {
+		worker.offsetMinutes = defaultOffsetMinutes
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(worker.offsetMinutes)))
+	if worker.language == "" {
+		worker.language = constants.LanguageIndonesian
+	}
+	if worker.pollInterval <= 0 {
+		worker.pollInterval = defaultPollInterval
+	}
+	if worker.batchSize <= 0 {
+		worker.batchSize = defaultBatchSize
+	}
+	if worker.maxAttempts <= 0 {
+		worker.maxAttempts = defaultMaxAttempts
+	}
+	if worker.baseBackoff <= 0 {
+		worker.baseBackoff = defaultBaseBackoff
+	}
+	if worker.maxBackoff <= 0 {
+		worker.maxBackoff = defaultMaxBackoff
+	}
+	return worker
+}
+
+// Run queues and sends booking reminders until the context is cancelled.
+func (w *ReminderWorker) Run(ctx context.Context) {
+	ctx = tenant.Unscoped(ctx)
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		err := w.queue(ctx)
+		if err != nil {
+			logrus.Errorf("failed to queue reminders: %v", err)
+		}
+		for {
+			picked, err := w.sendBatch(ctx)
+			if err != nil {
+				logrus.Errorf("failed to send reminders: %v", err)
+				break
+			}
+			if picked < w.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ReminderWorker) backoff(attempts int) time.Duration {
+	delay := w.baseBackoff
+	for i := 1; i < attempts && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.maxBackoff {
+		delay = w.maxBackoff
+	}
+	return delay
+}
+
+// queue adds a pending reminder per channel for the booked schedules that
+// reached an offset. A schedule only gets the reminder of the latest offset
+// it reached, so one booked an hour before it starts is not also sent the
+// reminder of the day before.
+func (w *ReminderWorker) queue(ctx context.Context) error {
+	now := time.Now()
+	for i, offset := range w.offsetMinutes {
+		from := now
+		if i+1 < len(w.offsetMinutes) {
+			from = now.Add(time.Duration(w.offsetMinutes[i+1]) * time.Minute)
+		}
+		until := now.Add(time.Duration(offset) * time.Minute)
+		for {
+			fieldSchedules, err := w.repository.GetScheduleReminder().FindAllUnreminded(ctx, from, until, offset, w.batchSize)
+			if err != nil {
+				return err
+			}
+			reminders := make([]models.ScheduleReminder, 0, len(fieldSchedules)*len(w.channels))
+			for _, fieldSchedule := range fieldSchedules {
+				for name := range w.channels {
+					reminders = append(reminders, models.ScheduleReminder{
+						UUID:            uuid.New(),
+						TenantID:        fieldSchedule.TenantID,
+						FieldScheduleID: fieldSchedule.ID,
+						OrderID:         fieldSchedule.OrderID,
+						OffsetMinute:    offset,
+						Channel:         name,
+						Status:          constants.ReminderPending,
+						NextAttemptAt:   now,
+					})
+				}
+			}
+			err = w.repository.GetScheduleReminder().Create(ctx, reminders)
+			if err != nil {
+				return err
+			}
+			if len(fieldSchedules) < w.batchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// startAt is when the schedule starts, in local time like its date and time.
+func (w *ReminderWorker) startAt(fieldSchedule *models.FieldSchedule) (time.Time, error) {
+	return time.ParseInLocation(
+		time.DateTime,
+		fmt.Sprintf("%s %s", fieldSchedule.Date.Format(time.DateOnly), fieldSchedule.Time.StartTime),
+		time.Local,
+	)
+}
+
+// send delivers one reminder. The returned skip reason is set when the
+// reminder no longer applies and must not be retried.
+func (w *ReminderWorker) send(ctx context.Context, reminder *models.ScheduleReminder) (string, error) {
+	fieldSchedule := reminder.FieldSchedule
+	if fieldSchedule == nil || fieldSchedule.Status != constants.Booked || fieldSchedule.OrderID != reminder.OrderID {
+		return "the booking was cancelled", nil
+	}
+	startAt, err := w.startAt(fieldSchedule)
+	if err != nil {
+		return "", err
+	}
+	if !startAt.After(time.Now()) {
+		return "the schedule has started", nil
+	}
+	channel, ok := w.channels[reminder.Channel]
+	if !ok {
+		return "the channel is no longer configured", nil
+	}
+	customer, err := w.repository.GetOrderCustomer().FindByOrderID(ctx, w.repository.GetTx(), reminder.TenantID, reminder.OrderID)
+	if err != nil {
+		return "", err
+	}
+	if customer == nil {
+		return "the order has no customer", nil
+	}
+
+	language := customer.Language
+	if language == "" {
+		language = w.language
+	}
+	message, err := notification.Render(notification.BookingReminder, language, notification.ReminderData{
+		CustomerName: customer.Name,
+		FieldName:    fieldSchedule.Field.Name,
+		Date:         fieldSchedule.Date,
+		StartTime:    fieldSchedule.Time.StartTime,
+		EndTime:      fieldSchedule.Time.EndTime,
+		Lead:         time.Duration(reminder.OffsetMinute) * time.Minute,
+	})
+	if err != nil {
+		return "", err
+	}
+	message.To = notification.Recipient{
+		Name:        customer.Name,
+		Email:       customer.Email,
+		PhoneNumber: customer.PhoneNumber,
+	}
+	err = channel.Send(ctx, *message)
+	if errors.Is(err, notification.ErrNoAddress) {
+		return err.Error(), nil
+	}
+	return "", err
+}
+
+// claim locks the due reminders and pushes their next attempt past
+// claimTimeout, so that no other worker picks them up while they are sent.
+func (w *ReminderWorker) claim(ctx context.Context) ([]models.ScheduleReminder, error) {
+	var reminders []models.ScheduleReminder
+	err := w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var err error
+		reminders, err = w.repository.GetScheduleReminder().FindAllDue(ctx, tx, w.batchSize)
+		if err != nil {
+			return err
+		}
+		claimedUntil := time.Now().Add(claimTimeout)
+		for i := range reminders {
+			reminders[i].Attempts++
+			reminders[i].NextAttemptAt = claimedUntil
+			err = w.repository.GetScheduleReminder().Update(ctx, tx, &reminders[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// sendBatch sends one batch of due reminders and returns how many were picked
+// up. The reminders are claimed in one transaction and sent without holding
+// any lock, and each outcome is recorded afterwards. A reminder claimed by a
+// worker that stopped mid-batch is sent again once its claim lapses.
+func (w *ReminderWorker) sendBatch(ctx context.Context) (int, error) {
+	reminders, err := w.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, reminder := range reminders {
+		skipReason, sendErr := w.send(ctx, &reminder)
+		switch {
+		case skipReason != "":
+			reminder.Status = constants.ReminderSkipped
+			reminder.LastError = skipReason
+		case sendErr == nil:
+			now := time.Now()
+			reminder.Status = constants.ReminderSent
+			reminder.LastError = ""
+			reminder.SentAt = &now
+		default:
+			reminder.LastError = sendErr.Error()
+			reminder.NextAttemptAt = time.Now().Add(w.backoff(reminder.Attempts))
+			if reminder.Attempts >= w.maxAttempts {
+				reminder.Status = constants.ReminderFailed
+				logrus.Warnf("giving up %s reminder %s after %d attempts: %v", reminder.Channel, reminder.UUID, reminder.Attempts, sendErr)
+			}
+		}
+		err = w.repository.GetScheduleReminder().Update(ctx, w.repository.GetTx(), &reminder)
+		if err != nil {
+			return len(reminders), err
+		}
+	}
+	return len(reminders), nil
+}
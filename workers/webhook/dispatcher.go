@@ -0,0 +1,252 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"field-service/common/safehttp"
+	"field-service/common/tenant"
+	"field-service/common/util"
+	"field-service/config"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPollInterval         = 2 * time.Second
+	defaultBatchSize            = 20
+	defaultTimeout              = 10 * time.Second
+	defaultMaxAttempts          = 8
+	defaultBaseBackoff          = 30 * time.Second
+	defaultMaxBackoff           = time.Hour
+	defaultDisableAfterFailures = 20
+	maxResponseBody             = 1024
+)
+
+type WebhookDispatcher struct {
+	repository           repositories.IRepositoryRegistry
+	client               *http.Client
+	timeout              time.Duration
+	pollInterval         time.Duration
+	batchSize            int
+	maxAttempts          int
+	baseBackoff          time.Duration
+	maxBackoff           time.Duration
+	disableAfterFailures int
+}
+
+type IWebhookDispatcher interface {
+	Run(context.Context)
+}
+
+func NewWebhookDispatcher(repository repositories.IRepositoryRegistry) IWebhookDispatcher {
+	cfg := config.Config.Webhook
+	timeout := time.Duration(cfg.TimeoutSecond) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	dispatcher := &WebhookDispatcher{
+		repository:           repository,
+		client:               safehttp.NewClient(timeout),
+		timeout:              timeout,
+		pollInterval:         time.Duration(cfg.PollIntervalSecond) * time.Second,
+		batchSize:            cfg.BatchSize,
+		maxAttempts:          cfg.MaxAttempts,
+		baseBackoff:          time.Duration(cfg.BaseBackoffSecond) * time.Second,
+		maxBackoff:           time.Duration(cfg.MaxBackoffSecond) * time.Second,
+		disableAfterFailures: cfg.DisableAfterFailures,
+	}
+	if dispatcher.pollInterval <= 0 {
+		dispatcher.pollInterval = defaultPollInterval
+	}
+	if dispatcher.batchSize <= 0 {
+		dispatcher.batchSize = defaultBatchSize
+	}
+	if dispatcher.maxAttempts <= 0 {
+		dispatcher.maxAttempts = defaultMaxAttempts
+	}
+	if dispatcher.baseBackoff <= 0 {
+		dispatcher.baseBackoff = defaultBaseBackoff
+	}
+	if dispatcher.maxBackoff <= 0 {
+		dispatcher.maxBackoff = defaultMaxBackoff
+	}
+	if dispatcher.disableAfterFailures <= 0 {
+		dispatcher.disableAfterFailures = defaultDisableAfterFailures
+	}
+	return dispatcher
+}
+
+// Run sends due webhook deliveries until the context is cancelled.
+func (w *WebhookDispatcher) Run(ctx context.Context) {
+	ctx = tenant.Unscoped(ctx)
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			picked, err := w.dispatchBatch(ctx)
+			if err != nil {
+				logrus.Errorf("failed to dispatch webhooks: %v", err)
+				break
+			}
+			if picked < w.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *WebhookDispatcher) backoff(attempts int) time.Duration {
+	delay := w.baseBackoff
+	for i := 1; i < attempts && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.maxBackoff {
+		delay = w.maxBackoff
+	}
+	return delay
+}
+
+// send posts the payload signed with the webhook secret. The signature is the
+// HMAC-SHA256 of "<timestamp>.<body>", so receivers can reject replays of old
+// deliveries.
+func (w *WebhookDispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) (int, string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := util.GenerateHMACSHA256(delivery.Webhook.Secret, fmt.Sprintf("%s.%s", timestamp, delivery.Payload))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.TargetURL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(constants.XWebhookID, delivery.EventID)
+	request.Header.Set(constants.XWebhookEvent, delivery.EventType)
+	request.Header.Set(constants.XWebhookTimestamp, timestamp)
+	request.Header.Set(constants.XWebhookSignature, constants.WebhookSignaturePrefix+signature)
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBody))
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, string(body), fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, string(body), nil
+}
+
+// claim locks the due deliveries and pushes their next attempt past the time
+// the batch may take to send, so no other dispatcher picks them up meanwhile.
+// Deliveries of a webhook that was disabled or deleted are failed instead. It
+// returns the deliveries to send and how many were picked up.
+func (w *WebhookDispatcher) claim(ctx context.Context) ([]models.WebhookDelivery, int, error) {
+	var (
+		claimed []models.WebhookDelivery
+		picked  int
+	)
+	err := w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		deliveries, err := w.repository.GetWebhookDelivery().FindAllDue(ctx, tx, w.batchSize)
+		if err != nil {
+			return err
+		}
+		picked = len(deliveries)
+		claimedUntil := time.Now().Add(w.timeout * time.Duration(len(deliveries)+1))
+		for _, delivery := range deliveries {
+			if delivery.Webhook == nil || !delivery.Webhook.Active {
+				delivery.Status = constants.WebhookDeliveryFailed
+				delivery.LastError = "webhook is disabled"
+			} else {
+				delivery.Attempts++
+				delivery.NextAttemptAt = claimedUntil
+				claimed = append(claimed, delivery)
+			}
+			err = w.repository.GetWebhookDelivery().Update(ctx, tx, &delivery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return claimed, picked, nil
+}
+
+// record stores the outcome of an attempt along with the webhook's failure
+// streak, and reports whether the webhook got disabled by it.
+func (w *WebhookDispatcher) record(ctx context.Context, delivery *models.WebhookDelivery, status int, body string, sendErr error) (bool, error) {
+	var disabled bool
+	err := w.repository.GetTx().Transaction(func(tx *gorm.DB) error {
+		var err error
+		delivery.ResponseStatus = status
+		delivery.ResponseBody = body
+		if sendErr == nil {
+			now := time.Now()
+			delivery.Status = constants.WebhookDeliverySucceeded
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			err = w.repository.GetWebhook().RecordSuccess(ctx, tx, delivery.WebhookID)
+		} else {
+			delivery.LastError = sendErr.Error()
+			delivery.NextAttemptAt = time.Now().Add(w.backoff(delivery.Attempts))
+			if delivery.Attempts >= w.maxAttempts {
+				delivery.Status = constants.WebhookDeliveryFailed
+			}
+			disabled, err = w.repository.GetWebhook().RecordFailure(ctx, tx, delivery.WebhookID, w.disableAfterFailures)
+		}
+		if err != nil {
+			return err
+		}
+		return w.repository.GetWebhookDelivery().Update(ctx, tx, delivery)
+	})
+	return disabled, err
+}
+
+// dispatchBatch sends one batch of due deliveries and returns how many were
+// picked up. The deliveries are claimed in one transaction and sent without
+// holding any lock, and each outcome is recorded in a transaction of its own.
+// A delivery claimed by a dispatcher that stopped mid-batch is picked up again
+// once its claim lapses.
+func (w *WebhookDispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	deliveries, picked, err := w.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	disabledWebhooks := map[uint]bool{}
+	for _, delivery := range deliveries {
+		if disabledWebhooks[delivery.WebhookID] {
+			delivery.Status = constants.WebhookDeliveryFailed
+			delivery.LastError = "webhook is disabled"
+			err = w.repository.GetWebhookDelivery().Update(ctx, w.repository.GetTx(), &delivery)
+			if err != nil {
+				return picked, err
+			}
+			continue
+		}
+
+		status, body, sendErr := w.send(ctx, &delivery)
+		disabled, err := w.record(ctx, &delivery, status, body, sendErr)
+		if err != nil {
+			return picked, err
+		}
+		if disabled {
+			disabledWebhooks[delivery.WebhookID] = true
+			logrus.Warnf("disabled webhook %s after %d failed deliveries in a row", delivery.Webhook.UUID, w.disableAfterFailures)
+		}
+	}
+	return picked, nil
+}
@@ -0,0 +1,248 @@
+package workers
+
+import (
+	"context"
+	"field-service/common/tenant"
+	"field-service/common/util"
+	"field-service/constants"
+	"field-service/domain/models"
+	"field-service/repositories"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "0123456789abcdef"
+
+func newTestDispatcher(t *testing.T, client *http.Client) (*WebhookDispatcher, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err = db.Use(tenant.NewPlugin()); err != nil {
+		t.Fatalf("failed to register tenant plugin: %v", err)
+	}
+	if err = db.AutoMigrate(&models.Field{}, &models.Webhook{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return &WebhookDispatcher{
+		repository:           repositories.NewRepositoryRegistry(db),
+		client:               client,
+		timeout:              time.Second,
+		pollInterval:         time.Second,
+		batchSize:            defaultBatchSize,
+		maxAttempts:          3,
+		baseBackoff:          time.Minute,
+		maxBackoff:           time.Hour,
+		disableAfterFailures: defaultDisableAfterFailures,
+	}, db
+}
+
+func createWebhook(t *testing.T, db *gorm.DB, targetURL string, deliveries int) *models.Webhook {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), "tenant-a")
+	webhook := &models.Webhook{
+		UUID:       uuid.New(),
+		OwnerUUID:  uuid.New(),
+		TargetURL:  targetURL,
+		EventTypes: []string{constants.FieldScheduleBooked},
+		Secret:     testSecret,
+		Active:     true,
+	}
+	if err := db.WithContext(ctx).Create(webhook).Error; err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	for i := 0; i < deliveries; i++ {
+		err := db.WithContext(ctx).Create(&models.WebhookDelivery{
+			UUID:          uuid.New(),
+			WebhookID:     webhook.ID,
+			EventID:       uuid.NewString(),
+			EventType:     constants.FieldScheduleBooked,
+			Payload:       fmt.Sprintf(`{"n":%d}`, i),
+			Status:        constants.WebhookDeliveryPending,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		}).Error
+		if err != nil {
+			t.Fatalf("failed to create delivery: %v", err)
+		}
+	}
+	return webhook
+}
+
+func findDeliveries(t *testing.T, db *gorm.DB) []models.WebhookDelivery {
+	t.Helper()
+	var deliveries []models.WebhookDelivery
+	if err := db.WithContext(tenant.Unscoped(context.Background())).Order("id").Find(&deliveries).Error; err != nil {
+		t.Fatalf("failed to find deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func findWebhook(t *testing.T, db *gorm.DB, id uint) models.Webhook {
+	t.Helper()
+	var webhook models.Webhook
+	if err := db.WithContext(tenant.Unscoped(context.Background())).First(&webhook, id).Error; err != nil {
+		t.Fatalf("failed to find webhook: %v", err)
+	}
+	return webhook
+}
+
+func TestDispatchSignsPayload(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	dispatcher, db := newTestDispatcher(t, server.Client())
+	createWebhook(t, db, server.URL, 1)
+
+	ctx := tenant.Unscoped(context.Background())
+	if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	if request == nil {
+		t.Fatal("webhook was not called")
+	}
+	delivery := findDeliveries(t, db)[0]
+	if string(body) != delivery.Payload {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	timestamp := request.Header.Get(constants.XWebhookTimestamp)
+	want := constants.WebhookSignaturePrefix + util.GenerateHMACSHA256(testSecret, timestamp+"."+delivery.Payload)
+	if got := request.Header.Get(constants.XWebhookSignature); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if got := request.Header.Get(constants.XWebhookID); got != delivery.EventID {
+		t.Errorf("webhook id = %s, want %s", got, delivery.EventID)
+	}
+	if delivery.Status != constants.WebhookDeliverySucceeded || delivery.DeliveredAt == nil {
+		t.Errorf("status = %s, want the delivery recorded as succeeded", delivery.Status)
+	}
+}
+
+func TestDispatchRetriesWithBackoff(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	dispatcher, db := newTestDispatcher(t, server.Client())
+	webhook := createWebhook(t, db, server.URL, 1)
+	ctx := tenant.Unscoped(context.Background())
+
+	if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	delivery := findDeliveries(t, db)[0]
+	if delivery.Status != constants.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("delivery = %s after %d attempts with %d, want pending after 1 with 500", delivery.Status, delivery.Attempts, delivery.ResponseStatus)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 50*time.Second {
+		t.Errorf("next attempt in %s, want the base backoff", wait)
+	}
+	if got := findWebhook(t, db, webhook.ID).ConsecutiveFailures; got != 1 {
+		t.Errorf("consecutive failures = %d, want 1", got)
+	}
+
+	picked, err := dispatcher.dispatchBatch(ctx)
+	if err != nil || picked != 0 {
+		t.Fatalf("picked %d deliveries (%v) before the backoff elapsed", picked, err)
+	}
+
+	db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if _, err = dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	delivery = findDeliveries(t, db)[0]
+	if delivery.Status != constants.WebhookDeliverySucceeded || delivery.Attempts != 2 {
+		t.Errorf("delivery = %s after %d attempts, want succeeded after 2", delivery.Status, delivery.Attempts)
+	}
+	if got := findWebhook(t, db, webhook.ID).ConsecutiveFailures; got != 0 {
+		t.Errorf("consecutive failures = %d, want the streak reset", got)
+	}
+}
+
+func TestDispatchFailsAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	dispatcher, db := newTestDispatcher(t, server.Client())
+	createWebhook(t, db, server.URL, 1)
+	ctx := tenant.Unscoped(context.Background())
+
+	for i := 0; i < dispatcher.maxAttempts; i++ {
+		db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("1 = 1").Update("next_attempt_at", time.Now().Add(-time.Second))
+		if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+			t.Fatalf("failed to dispatch: %v", err)
+		}
+	}
+	delivery := findDeliveries(t, db)[0]
+	if delivery.Status != constants.WebhookDeliveryFailed || delivery.Attempts != dispatcher.maxAttempts {
+		t.Errorf("delivery = %s after %d attempts, want failed after %d", delivery.Status, delivery.Attempts, dispatcher.maxAttempts)
+	}
+}
+
+func TestDispatchDisablesFailingWebhook(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	dispatcher, db := newTestDispatcher(t, server.Client())
+	dispatcher.disableAfterFailures = 2
+	webhook := createWebhook(t, db, server.URL, 3)
+	ctx := tenant.Unscoped(context.Background())
+
+	if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("webhook called %d times, want it disabled after 2", calls)
+	}
+	disabled := findWebhook(t, db, webhook.ID)
+	if disabled.Active || disabled.DisabledAt == nil {
+		t.Error("webhook is still active")
+	}
+	deliveries := findDeliveries(t, db)
+	if last := deliveries[2]; last.Status != constants.WebhookDeliveryFailed || last.LastError != "webhook is disabled" {
+		t.Errorf("last delivery = %s (%s), want failed as disabled", last.Status, last.LastError)
+	}
+
+	db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("1 = 1").Update("next_attempt_at", time.Now().Add(-time.Second))
+	if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("failed to dispatch: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("webhook called %d times after it was disabled", calls)
+	}
+	for _, delivery := range findDeliveries(t, db) {
+		if delivery.Status != constants.WebhookDeliveryFailed {
+			t.Errorf("delivery %s = %s, want failed", delivery.UUID, delivery.Status)
+		}
+	}
+}